@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+// ArchivedSession is what remains of a session once it has ended and its
+// status files are removed.
+type ArchivedSession struct {
+	Repo      string `json:"repo"`
+	Worktree  string `json:"worktree"`
+	Harness   string `json:"harness"`
+	SessionID string `json:"session_id"`
+	Model     string `json:"model,omitempty"`
+	// Status is the last status the session reported before it ended.
+	Status       Status          `json:"status"`
+	StartTime    time.Time       `json:"start_time"`
+	EndTime      time.Time       `json:"end_time"`
+	ToolCount    int             `json:"tool_count,omitempty"`
+	FilesTouched []string        `json:"files_touched,omitempty"`
+	Timeline     []TimelineEntry `json:"timeline,omitempty"`
+	Usage        harness.Usage   `json:"usage,omitempty"`
+	CostUSD      float64         `json:"cost_usd,omitempty"`
+}
+
+// Duration is how long the session ran.
+func (a ArchivedSession) Duration() time.Duration {
+	if a.StartTime.IsZero() {
+		return 0
+	}
+	return a.EndTime.Sub(a.StartTime)
+}
+
+// ArchiveDir holds one gzipped JSON file per ended session:
+// <willow-base>/sessions/<repo>/<end time>-<harness>-<session>.json.gz.
+func ArchiveDir() string {
+	return filepath.Join(config.WillowHome(), "sessions")
+}
+
+const (
+	archiveExt        = ".json.gz"
+	archiveTimeLayout = "20060102T150405Z"
+)
+
+func archivePath(a ArchivedSession) string {
+	name := a.EndTime.UTC().Format(archiveTimeLayout) + "-" + a.Harness + "-" + a.SessionID + archiveExt
+	return filepath.Join(ArchiveDir(), a.Repo, name)
+}
+
+// archiveSession records a session's status, files, and timeline in the
+// archive before its status files are removed. Sessions without a status
+// file are skipped.
+func archiveSession(repoName, worktreeDir, harnessID, sessionID string, end time.Time) error {
+	ss := readSessionFile(repoName, worktreeDir, harnessID, sessionID, SessionPath(repoName, worktreeDir, harnessID, sessionID))
+	if ss == nil {
+		return nil
+	}
+	timeline, _ := ReadTimeline(repoName, worktreeDir, sessionID, time.Time{})
+	a := ArchivedSession{
+		Repo:         repoName,
+		Worktree:     worktreeDir,
+		Harness:      ss.Harness,
+		SessionID:    ss.SessionID,
+		Model:        ss.Model,
+		Status:       ss.Status,
+		StartTime:    ss.StartTime,
+		EndTime:      end.UTC(),
+		ToolCount:    ss.ToolCount,
+		FilesTouched: ReadFilesTouched(repoName, worktreeDir, sessionID),
+		Timeline:     timeline,
+		Usage:        ss.Usage,
+		CostUSD:      ss.CostUSD,
+	}
+	return writeArchive(a)
+}
+
+// ArchiveWorktreeSessions archives every session in a worktree's status
+// dir, for when the worktree is removed while its agents are still live.
+func ArchiveWorktreeSessions(repoName, worktreeDir string) error {
+	end := time.Now()
+	var firstErr error
+	for _, ss := range ReadAllSessions(repoName, worktreeDir) {
+		if err := archiveSession(repoName, worktreeDir, ss.Harness, ss.SessionID, end); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// writeArchive stores a, replacing any earlier archive of the same session,
+// e.g. one written before the worktree was restored from the trash.
+func writeArchive(a ArchivedSession) error {
+	path := archivePath(a)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create session archive dir: %w", err)
+	}
+	earlier, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*-"+a.Harness+"-"+a.SessionID+archiveExt))
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("create session archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write session archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write session archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write session archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	for _, p := range earlier {
+		if p != path {
+			os.Remove(p)
+		}
+	}
+	return nil
+}
+
+type ArchiveReadOpts struct {
+	Repo  string
+	Since time.Time
+}
+
+// ReadArchive returns archived sessions that ended at or after opts.Since,
+// oldest first. Unreadable files are skipped.
+func ReadArchive(opts ArchiveReadOpts) ([]ArchivedSession, error) {
+	var result []ArchivedSession
+	err := walkArchive(opts.Repo, func(path string, end time.Time) {
+		if !opts.Since.IsZero() && end.Before(opts.Since) {
+			return
+		}
+		a, err := readArchiveFile(path)
+		if err != nil {
+			return
+		}
+		result = append(result, a)
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].EndTime.Before(result[j].EndTime)
+	})
+	return result, err
+}
+
+// PruneArchive removes archived sessions that ended before cutoff and
+// returns how many there were. With dryRun, nothing is removed.
+func PruneArchive(cutoff time.Time, dryRun bool) (int, error) {
+	pruned := 0
+	var firstErr error
+	err := walkArchive("", func(path string, end time.Time) {
+		if !end.Before(cutoff) {
+			return
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+		}
+		pruned++
+	})
+	if err != nil {
+		return pruned, err
+	}
+	return pruned, firstErr
+}
+
+// walkArchive calls fn with each archive file and its end time, taken from
+// the file name so old sessions can be skipped without decompressing them.
+func walkArchive(repo string, fn func(path string, end time.Time)) error {
+	repos, err := os.ReadDir(ArchiveDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, r := range repos {
+		if !r.IsDir() || (repo != "" && r.Name() != repo) {
+			continue
+		}
+		dir := filepath.Join(ArchiveDir(), r.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := f.Name()
+			if f.IsDir() || !strings.HasSuffix(name, archiveExt) {
+				continue
+			}
+			stamp, _, _ := strings.Cut(name, "-")
+			end, err := time.Parse(archiveTimeLayout, stamp)
+			if err != nil {
+				continue
+			}
+			fn(filepath.Join(dir, name), end)
+		}
+	}
+	return nil
+}
+
+func readArchiveFile(path string) (ArchivedSession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ArchivedSession{}, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return ArchivedSession{}, err
+	}
+	defer zr.Close()
+	var a ArchivedSession
+	if err := json.NewDecoder(zr).Decode(&a); err != nil {
+		return ArchivedSession{}, err
+	}
+	return a, nil
+}
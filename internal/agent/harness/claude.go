@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
 )
 
 var claudeHookEvents = []string{
@@ -157,10 +159,14 @@ func (h Claude) RemoveLegacyHooks() ([]string, bool, error) {
 }
 
 type claudeHookInput struct {
-	SessionID     string `json:"session_id"`
-	HookEventName string `json:"hook_event_name"`
-	ToolName      string `json:"tool_name"`
-	FilePath      string `json:"file_path"`
+	SessionID        string          `json:"session_id"`
+	HookEventName    string          `json:"hook_event_name"`
+	ToolName         string          `json:"tool_name"`
+	FilePath         string          `json:"file_path"`
+	TranscriptPath   string          `json:"transcript_path"`
+	ToolInput        json.RawMessage `json:"tool_input"`
+	Message          string          `json:"message"`
+	NotificationType string          `json:"notification_type"`
 }
 
 func (Claude) NormalizeHook(raw []byte) (NormalizedHook, bool) {
@@ -171,26 +177,51 @@ func (Claude) NormalizeHook(raw []byte) (NormalizedHook, bool) {
 	if in.SessionID == "" {
 		return NormalizedHook{}, false
 	}
+	status := claudeNotificationStatus(in)
 	files := []string{}
 	if in.HookEventName == "PreToolUse" && isClaudeWriteTool(in.ToolName) && in.FilePath != "" {
 		files = append(files, in.FilePath)
 	}
 	return NormalizedHook{
-		HarnessID:    ClaudeID,
-		SessionID:    in.SessionID,
-		EventName:    in.HookEventName,
-		ToolName:     in.ToolName,
-		FilePath:     in.FilePath,
-		FilesTouched: files,
+		HarnessID:        ClaudeID,
+		SessionID:        in.SessionID,
+		EventName:        in.HookEventName,
+		ToolName:         in.ToolName,
+		FilePath:         in.FilePath,
+		FilesTouched:     files,
+		TranscriptPath:   in.TranscriptPath,
+		ToolInput:        in.ToolInput,
+		Message:          in.Message,
+		MappedStatus:     status,
+		PermissionPrompt: status == EventStatusWait,
 	}, true
 }
 
+// claudeNotificationStatus maps a permission prompt notification to WAIT,
+// even while the session is BUSY. Other notifications are left to the
+// hook handler. Older Claude Code versions don't send notification_type,
+// so the message text is checked too.
+func claudeNotificationStatus(in claudeHookInput) string {
+	if in.HookEventName != "Notification" {
+		return ""
+	}
+	if in.NotificationType == "permission_prompt" || strings.Contains(in.Message, "needs your permission") {
+		return EventStatusWait
+	}
+	return ""
+}
+
 func (Claude) BuildLaunch(opts LaunchOptions) LaunchCommand {
-	return launchCommand("claude", nil, opts, true, []string{"--dangerously-skip-permissions"})
+	return launchCommand("claude", nil, opts, true, "", []string{"--dangerously-skip-permissions"})
 }
 
 func (Claude) BuildShellLaunch(opts ShellLaunchOptions) string {
-	return shellLaunch("claude", nil, opts, true, []string{"--dangerously-skip-permissions"})
+	return shellLaunch("claude", nil, opts, true, "", []string{"--dangerously-skip-permissions"})
+}
+
+// PermissionKeys accepts the prompt's highlighted "Yes", or escapes it.
+func (Claude) PermissionKeys(overrides config.AgentHarnessConfig) PermissionKeys {
+	return permissionKeys(overrides, []string{"Enter"}, []string{"Escape"})
 }
 
 func (h Claude) addHookToSettings(command string) error {
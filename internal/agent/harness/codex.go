@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
 )
 
 var codexHookEvents = []string{
@@ -84,6 +86,7 @@ type codexHookInput struct {
 	Model          string          `json:"model"`
 	TurnID         string          `json:"turn_id"`
 	PermissionMode string          `json:"permission_mode"`
+	TranscriptPath string          `json:"transcript_path"`
 	ToolInput      json.RawMessage `json:"tool_input"`
 }
 
@@ -106,15 +109,22 @@ func (Codex) NormalizeHook(raw []byte) (NormalizedHook, bool) {
 		Model:          in.Model,
 		TurnID:         in.TurnID,
 		PermissionMode: in.PermissionMode,
+		TranscriptPath: in.TranscriptPath,
+		ToolInput:      in.ToolInput,
 	}, true
 }
 
 func (Codex) BuildLaunch(opts LaunchOptions) LaunchCommand {
-	return launchCommand("codex", nil, opts, false, []string{"--dangerously-bypass-approvals-and-sandbox"})
+	return launchCommand("codex", nil, opts, false, "", []string{"--dangerously-bypass-approvals-and-sandbox"})
 }
 
 func (Codex) BuildShellLaunch(opts ShellLaunchOptions) string {
-	return shellLaunch("codex", nil, opts, false, []string{"--dangerously-bypass-approvals-and-sandbox"})
+	return shellLaunch("codex", nil, opts, false, "", []string{"--dangerously-bypass-approvals-and-sandbox"})
+}
+
+// PermissionKeys answers Codex's approval modal with "y", or escapes it.
+func (Codex) PermissionKeys(overrides config.AgentHarnessConfig) PermissionKeys {
+	return permissionKeys(overrides, []string{"y"}, []string{"Escape"})
 }
 
 func (h Codex) addHookToConfig(command string) error {
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
 )
 
 var cursorHookEvents = []string{
@@ -110,15 +112,20 @@ func (Cursor) NormalizeHook(raw []byte) (NormalizedHook, bool) {
 		FilesTouched: files,
 		Model:        nonEmptyString(in.Model, in.ModelID),
 		TurnID:       in.GenerationID,
+		ToolInput:    in.ToolInput,
 	}, true
 }
 
 func (Cursor) BuildLaunch(opts LaunchOptions) LaunchCommand {
-	return launchCommand("cursor-agent", nil, opts, false, []string{"--force"})
+	return launchCommand("cursor-agent", nil, opts, false, "", []string{"--force"})
 }
 
 func (Cursor) BuildShellLaunch(opts ShellLaunchOptions) string {
-	return shellLaunch("cursor-agent", nil, opts, false, []string{"--force"})
+	return shellLaunch("cursor-agent", nil, opts, false, "", []string{"--force"})
+}
+
+func (Cursor) PermissionKeys(overrides config.AgentHarnessConfig) PermissionKeys {
+	return permissionKeys(overrides, []string{"Enter"}, []string{"Escape"})
 }
 
 func (h Cursor) addHookToConfig(command string) error {
@@ -0,0 +1,305 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+// Custom is a command-driven harness declared under agent.harnesses in the
+// global config. Launch arguments, hook event → status mapping, and the JSON
+// field paths used to read hook payloads all come from config.
+type Custom struct {
+	id  string
+	cfg config.AgentHarnessConfig
+}
+
+func NewCustom(id string, cfg config.AgentHarnessConfig) Custom {
+	return Custom{id: NormalizeID(id), cfg: cfg}
+}
+
+func (c Custom) ID() string { return c.id }
+
+func (c Custom) DisplayName() string {
+	if c.cfg.DisplayName != "" {
+		return c.cfg.DisplayName
+	}
+	return c.id
+}
+
+func (c Custom) ExecutableName() string    { return c.cfg.Command }
+func (c Custom) SetupCommandLabel() string { return "ww agent setup " + c.id }
+
+func (c Custom) DocsHint() string {
+	if path := c.settingsPath(); path != "" {
+		return fmt.Sprintf("%s hooks are installed into %s.", c.DisplayName(), path)
+	}
+	events := c.HookEvents()
+	if len(events) == 0 {
+		return fmt.Sprintf("%s has no hooks.events configured; status tracking is disabled.", c.DisplayName())
+	}
+	return fmt.Sprintf("Configure %s to run the hook command for: %s.", c.DisplayName(), strings.Join(events, ", "))
+}
+
+func (c Custom) HookEvents() []string {
+	if c.cfg.Hooks == nil {
+		return nil
+	}
+	events := make([]string, 0, len(c.cfg.Hooks.Events))
+	for event := range c.cfg.Hooks.Events {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+func (c Custom) Capabilities() Capabilities {
+	var caps Capabilities
+	if c.cfg.Hooks == nil {
+		return caps
+	}
+	for _, status := range c.cfg.Hooks.Events {
+		switch status, _ := config.HookEventStatus(status); status {
+		case EventStatusWait:
+			caps.SupportsPermissionWait = true
+		case EventStatusEnd:
+			caps.SupportsSessionEnd = true
+		}
+	}
+	caps.SupportsFilesTouched = c.fields().FilePath != ""
+	return caps
+}
+
+func (c Custom) HookCommand(exe string) string {
+	return exe + " hook --harness " + c.id
+}
+
+// InstallHooks writes willow's hook rule into hooks.settingsFile. Harnesses
+// without a settings file are wired up by hand, so there is nothing to do.
+func (c Custom) InstallHooks(command string) (bool, error) {
+	path := c.settingsPath()
+	if path == "" {
+		return false, nil
+	}
+	before, _ := os.ReadFile(path)
+	if err := c.addHookToConfig(path, command); err != nil {
+		return false, err
+	}
+	after, _ := os.ReadFile(path)
+	return !bytes.Equal(before, after), nil
+}
+
+// HooksInstalled reports true when there is no settings file to inspect;
+// willow cannot verify hooks the user wired up by hand.
+func (c Custom) HooksInstalled(command string) bool {
+	path := c.settingsPath()
+	if path == "" {
+		return true
+	}
+	settings, err := readJSONFile(path)
+	if err != nil {
+		return false
+	}
+	hooksMap, ok := settings["hooks"].(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, event := range c.HookEvents() {
+		if !eventHasHook(hooksMap, event, command) {
+			return false
+		}
+	}
+	return true
+}
+
+func (Custom) LegacyHooks() []LegacyHook { return nil }
+
+func (Custom) RemoveLegacyHooks() ([]string, bool, error) {
+	return nil, false, nil
+}
+
+// NormalizeHook reads the configured field paths out of the payload. Events
+// without a valid status mapping are rejected so they never touch session
+// state; config validation reports the invalid ones.
+func (c Custom) NormalizeHook(raw []byte) (NormalizedHook, bool) {
+	if c.cfg.Hooks == nil {
+		return NormalizedHook{}, false
+	}
+	var payload any
+	if err := json.Unmarshal(bytes.TrimSpace(raw), &payload); err != nil {
+		return NormalizedHook{}, false
+	}
+	fields := c.fields()
+	sessionID := lookupJSONPath(payload, fields.SessionID)
+	if sessionID == "" {
+		return NormalizedHook{}, false
+	}
+	event := lookupJSONPath(payload, fields.Event)
+	status, ok := config.HookEventStatus(c.cfg.Hooks.Events[event])
+	if !ok {
+		return NormalizedHook{}, false
+	}
+	filePath := lookupJSONPath(payload, fields.FilePath)
+	files := []string{}
+	if filePath != "" {
+		files = append(files, filePath)
+	}
+	toolUse := false
+	for _, toolEvent := range c.cfg.Hooks.ToolEvents {
+		if toolEvent == event {
+			toolUse = true
+			break
+		}
+	}
+	return NormalizedHook{
+		HarnessID:      c.id,
+		SessionID:      sessionID,
+		EventName:      event,
+		ToolName:       lookupJSONPath(payload, fields.Tool),
+		FilePath:       filePath,
+		FilesTouched:   files,
+		Model:          lookupJSONPath(payload, fields.Model),
+		MappedStatus:   status,
+		ToolUse:        toolUse,
+		TranscriptPath: lookupJSONPath(payload, fields.Transcript),
+	}, true
+}
+
+func (c Custom) BuildLaunch(opts LaunchOptions) LaunchCommand {
+	return launchCommand(c.cfg.Command, c.cfg.Args, opts, c.promptFirst(), c.cfg.PromptFlag, c.cfg.YoloArgs)
+}
+
+func (c Custom) BuildShellLaunch(opts ShellLaunchOptions) string {
+	return shellLaunch(c.cfg.Command, c.cfg.Args, opts, c.promptFirst(), c.cfg.PromptFlag, c.cfg.YoloArgs)
+}
+
+func (c Custom) PermissionKeys(overrides config.AgentHarnessConfig) PermissionKeys {
+	return permissionKeys(overrides, []string{"Enter"}, []string{"Escape"})
+}
+
+func (c Custom) promptFirst() bool {
+	return c.cfg.PromptPosition == "first"
+}
+
+func (c Custom) settingsPath() string {
+	if c.cfg.Hooks == nil {
+		return ""
+	}
+	return config.NormalizeBaseDir(c.cfg.Hooks.SettingsFile)
+}
+
+// fields returns the configured payload paths, defaulting to the Claude-style
+// field names most hook-emitting agents share.
+func (c Custom) fields() config.HarnessFieldsConfig {
+	var f config.HarnessFieldsConfig
+	if c.cfg.Hooks != nil {
+		f = c.cfg.Hooks.Fields
+	}
+	if f.SessionID == "" {
+		f.SessionID = "session_id"
+	}
+	if f.Event == "" {
+		f.Event = "hook_event_name"
+	}
+	if f.Tool == "" {
+		f.Tool = "tool_name"
+	}
+	if f.Model == "" {
+		f.Model = "model"
+	}
+	if f.Transcript == "" {
+		f.Transcript = "transcript_path"
+	}
+	return f
+}
+
+func (c Custom) addHookToConfig(path, command string) error {
+	settings, err := readJSONFile(path)
+	if err != nil {
+		return err
+	}
+	hooksMap, ok := settings["hooks"].(map[string]any)
+	if !ok {
+		hooksMap = make(map[string]any)
+	}
+
+	var willowRule map[string]any
+	if c.cfg.Hooks.Format == "flat" {
+		willowRule = map[string]any{"command": command}
+	} else {
+		willowRule = map[string]any{
+			"hooks": []any{
+				map[string]any{
+					"type":    "command",
+					"command": command,
+				},
+			},
+		}
+	}
+
+	for _, event := range c.HookEvents() {
+		existing, _ := hooksMap[event].([]any)
+		filtered := make([]any, 0, len(existing))
+		for _, rule := range existing {
+			if !c.isWillowRule(rule) {
+				filtered = append(filtered, rule)
+			}
+		}
+		hooksMap[event] = append(filtered, willowRule)
+	}
+
+	settings["hooks"] = hooksMap
+	return writeJSONFile(path, settings)
+}
+
+func (c Custom) isWillowRule(rule any) bool {
+	ruleMap, ok := rule.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, cmd := range ruleCommands(ruleMap) {
+		cmd = strings.TrimSpace(cmd)
+		if strings.HasSuffix(cmd, " hook --harness "+c.id) && strings.Contains(cmd, "willow") {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupJSONPath resolves a dot-separated path ("tool_input.file_path",
+// "edits.0.path") against a decoded JSON value and returns scalars as strings.
+func lookupJSONPath(value any, path string) string {
+	if path == "" {
+		return ""
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			value = v[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return ""
+			}
+			value = v[i]
+		default:
+			return ""
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
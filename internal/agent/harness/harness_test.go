@@ -256,3 +256,290 @@ func readTestJSON(t *testing.T, path string) map[string]any {
 	}
 	return out
 }
+
+func testCustomConfig() config.AgentHarnessConfig {
+	return config.AgentHarnessConfig{
+		Command:        "aider",
+		DisplayName:    "Aider",
+		Args:           []string{"--no-auto-commits"},
+		YoloArgs:       []string{"--yes-always"},
+		PromptPosition: "last",
+		PromptFlag:     "--message",
+		Hooks: &config.HarnessHookConfig{
+			Events: map[string]string{
+				"start": "BUSY",
+				"tool":  "busy",
+				"ask":   "WAIT",
+				"stop":  "DONE",
+				"exit":  "END",
+			},
+			ToolEvents: []string{"tool"},
+			Fields: config.HarnessFieldsConfig{
+				SessionID: "session.id",
+				Event:     "event",
+				Tool:      "call.name",
+				FilePath:  "call.args.path",
+			},
+		},
+	}
+}
+
+func TestRegisterCustom(t *testing.T) {
+	t.Cleanup(func() { RegisterCustom(nil) })
+	cfg := config.DefaultConfig()
+	cfg.Agent.Harnesses = map[string]config.AgentHarnessConfig{
+		"Aider":  testCustomConfig(),
+		"codex":  {Command: "my-codex"},
+		"noexec": {DisplayName: "No command"},
+	}
+
+	RegisterCustom(cfg)
+
+	h, ok := Get("aider")
+	if !ok {
+		t.Fatal("custom harness aider not registered")
+	}
+	if h.DisplayName() != "Aider" || h.SetupCommandLabel() != "ww agent setup aider" {
+		t.Fatalf("custom harness = %q / %q", h.DisplayName(), h.SetupCommandLabel())
+	}
+	if codex, _ := Get("codex"); codex.DisplayName() != "Codex CLI" {
+		t.Fatal("custom config must not replace a built-in harness")
+	}
+	if _, ok := Get("noexec"); ok {
+		t.Fatal("entry without command should not register a harness")
+	}
+
+	RegisterCustom(config.DefaultConfig())
+	if _, ok := Get("aider"); ok {
+		t.Fatal("re-registering should drop stale custom harnesses")
+	}
+	if _, ok := Get(ClaudeID); !ok {
+		t.Fatal("re-registering must keep built-ins")
+	}
+}
+
+func TestCustomLaunchBuilders(t *testing.T) {
+	h := NewCustom("aider", testCustomConfig())
+
+	launch := h.BuildLaunch(LaunchOptions{Prompt: "fix bug", Yolo: true})
+	if launch.Command != "aider" || strings.Join(launch.Args, " ") != "--no-auto-commits --yes-always --message fix bug" {
+		t.Fatalf("custom launch = %#v", launch)
+	}
+
+	shell := h.BuildShellLaunch(ShellLaunchOptions{PromptArg: `"$(cat p)"`, PromptArgRaw: true})
+	if shell != `'aider' '--no-auto-commits' '--message' "$(cat p)"` {
+		t.Fatalf("custom shell launch = %s", shell)
+	}
+
+	cfg := testCustomConfig()
+	cfg.PromptPosition = "first"
+	cfg.PromptFlag = ""
+	first := NewCustom("gemini", cfg).BuildLaunch(LaunchOptions{Prompt: "fix bug", Yolo: true})
+	if strings.Join(first.Args, " ") != "--no-auto-commits fix bug --yes-always" {
+		t.Fatalf("prompt-first launch = %#v", first)
+	}
+}
+
+func TestCustomNormalizeHook(t *testing.T) {
+	h := NewCustom("aider", testCustomConfig())
+
+	got, ok := h.NormalizeHook([]byte(`{
+		"session": {"id": "sess"},
+		"event": "tool",
+		"model": "sonnet",
+		"call": {"name": "edit", "args": {"path": "main.go"}}
+	}`))
+	if !ok {
+		t.Fatal("NormalizeHook returned false")
+	}
+	if got.HarnessID != "aider" || got.SessionID != "sess" || got.EventName != "tool" {
+		t.Fatalf("normalized hook = %#v", got)
+	}
+	if got.MappedStatus != EventStatusBusy || !got.ToolUse || got.ToolName != "edit" || got.Model != "sonnet" {
+		t.Fatalf("normalized hook metadata = %#v", got)
+	}
+	if strings.Join(got.FilesTouched, ",") != "main.go" {
+		t.Fatalf("FilesTouched = %#v", got.FilesTouched)
+	}
+
+	if _, ok := h.NormalizeHook([]byte(`{"session": {"id": "sess"}, "event": "unmapped"}`)); ok {
+		t.Fatal("unmapped events should be rejected")
+	}
+
+	typo := testCustomConfig()
+	typo.Hooks.Events["tool"] = "BUSSY"
+	if _, ok := NewCustom("aider", typo).NormalizeHook([]byte(`{"session": {"id": "sess"}, "event": "tool"}`)); ok {
+		t.Fatal("events mapped to an unknown status should be rejected")
+	}
+	if _, ok := h.NormalizeHook([]byte(`{"event": "stop"}`)); ok {
+		t.Fatal("missing session id should be rejected")
+	}
+}
+
+func TestCustomCapabilities(t *testing.T) {
+	caps := NewCustom("aider", testCustomConfig()).Capabilities()
+	if !caps.SupportsPermissionWait || !caps.SupportsSessionEnd || !caps.SupportsFilesTouched {
+		t.Fatalf("capabilities = %#v", caps)
+	}
+	if caps := NewCustom("bare", config.AgentHarnessConfig{Command: "bare"}).Capabilities(); caps != (Capabilities{}) {
+		t.Fatalf("capabilities without hooks = %#v", caps)
+	}
+}
+
+func TestCustomHookInstaller(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := testCustomConfig()
+	cfg.Hooks.SettingsFile = "~/.aider/hooks.json"
+	cfg.Hooks.Format = "flat"
+	h := NewCustom("aider", cfg)
+	command := "/usr/local/bin/willow hook --harness aider"
+
+	if h.HooksInstalled(command) {
+		t.Fatal("hooks should not be installed yet")
+	}
+	changed, err := h.InstallHooks(command)
+	if err != nil {
+		t.Fatalf("InstallHooks: %v", err)
+	}
+	if !changed {
+		t.Fatal("first install should change settings")
+	}
+	if !h.HooksInstalled(command) {
+		t.Fatal("HooksInstalled returned false")
+	}
+	changed, err = h.InstallHooks(command)
+	if err != nil {
+		t.Fatalf("second InstallHooks: %v", err)
+	}
+	if changed {
+		t.Fatal("second install should be idempotent")
+	}
+
+	settings := readTestJSON(t, filepath.Join(home, ".aider", "hooks.json"))
+	hooks := settings["hooks"].(map[string]any)
+	rules := hooks["stop"].([]any)
+	if len(rules) != 1 || rules[0].(map[string]any)["command"] != command {
+		t.Fatalf("stop rules = %#v, want one flat willow rule", rules)
+	}
+
+	manual := NewCustom("manual", config.AgentHarnessConfig{Command: "manual"})
+	if changed, err := manual.InstallHooks(command); err != nil || changed {
+		t.Fatalf("InstallHooks without settings file = %v, %v", changed, err)
+	}
+	if !manual.HooksInstalled(command) {
+		t.Fatal("harness without settings file should report hooks installed")
+	}
+}
+
+func writeTranscript(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClaudeNormalizeHookTranscriptPath(t *testing.T) {
+	got, ok := Claude{}.NormalizeHook([]byte(`{"session_id":"s1","hook_event_name":"Stop","transcript_path":"/tmp/s1.jsonl"}`))
+	if !ok || got.TranscriptPath != "/tmp/s1.jsonl" {
+		t.Fatalf("normalized hook = %#v, ok=%v", got, ok)
+	}
+}
+
+func TestClaudeNormalizeHookPermissionPrompt(t *testing.T) {
+	got, ok := Claude{}.NormalizeHook([]byte(`{"session_id":"s1","hook_event_name":"Notification","notification_type":"permission_prompt","message":"Claude needs your permission to use Bash"}`))
+	if !ok || got.MappedStatus != EventStatusWait || got.Message == "" {
+		t.Fatalf("normalized hook = %#v, ok=%v", got, ok)
+	}
+	got, _ = Claude{}.NormalizeHook([]byte(`{"session_id":"s1","hook_event_name":"Notification","notification_type":"idle_prompt","message":"Claude is waiting for your input"}`))
+	if got.MappedStatus != "" {
+		t.Errorf("idle notification mapped to %q, want it left to the hook handler", got.MappedStatus)
+	}
+}
+
+func TestPermissionKeysOverrides(t *testing.T) {
+	keys := Codex{}.PermissionKeys(config.AgentHarnessConfig{})
+	if strings.Join(keys.Approve, " ") != "y" || strings.Join(keys.Deny, " ") != "Escape" {
+		t.Errorf("codex keys = %+v", keys)
+	}
+	keys = Claude{}.PermissionKeys(config.AgentHarnessConfig{ApproveKeys: []string{"2", "Enter"}})
+	if strings.Join(keys.Approve, " ") != "2 Enter" || strings.Join(keys.Deny, " ") != "Escape" {
+		t.Errorf("overridden claude keys = %+v", keys)
+	}
+}
+
+func TestClaudeReadUsage(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}}}`,
+		// Same message, second content block: must not be counted twice.
+		`{"type":"assistant","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}}}`,
+		`{"type":"assistant","requestId":"r2","message":{"id":"m2","model":"claude-haiku-4-5","usage":{"input_tokens":1,"output_tokens":2}}}`,
+		`{"type":"assistant","message":{"model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}`,
+		`not json`,
+	)
+	got, err := Claude{}.ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	want := Usage{
+		"claude-sonnet-4-5": {Input: 10, Output: 5, CacheRead: 1000, CacheWrite: 100},
+		"claude-haiku-4-5":  {Input: 1, Output: 2},
+	}
+	if len(got) != len(want) || got["claude-sonnet-4-5"] != want["claude-sonnet-4-5"] || got["claude-haiku-4-5"] != want["claude-haiku-4-5"] {
+		t.Fatalf("usage = %#v, want %#v", got, want)
+	}
+}
+
+func TestCodexReadUsageSplitsByModel(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"turn_context","payload":{"model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":50}}}}`,
+		// Duplicate total: no new usage.
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":50}}}}`,
+		`{"type":"turn_context","payload":{"model":"gpt-5-mini"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1500,"cached_input_tokens":1000,"output_tokens":80}}}}`,
+	)
+	got, err := Codex{}.ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if got["gpt-5-codex"] != (TokenUsage{Input: 200, Output: 50, CacheRead: 800}) {
+		t.Errorf("gpt-5-codex usage = %#v", got["gpt-5-codex"])
+	}
+	if got["gpt-5-mini"] != (TokenUsage{Input: 300, Output: 30, CacheRead: 200}) {
+		t.Errorf("gpt-5-mini usage = %#v", got["gpt-5-mini"])
+	}
+}
+
+func TestUsageSince(t *testing.T) {
+	prev := Usage{"a": {Input: 10, Output: 5}, "b": {Input: 3}}
+	cur := Usage{"a": {Input: 15, Output: 5}, "b": {Input: 3}, "c": {Output: 7}}
+	got := cur.Since(prev)
+	if len(got) != 2 || got["a"] != (TokenUsage{Input: 5}) || got["c"] != (TokenUsage{Output: 7}) {
+		t.Fatalf("Since = %#v", got)
+	}
+	// A rewritten transcript that shrank restarts the count.
+	if got := (Usage{"a": {Input: 2}}).Since(prev); got["a"] != (TokenUsage{Input: 2}) {
+		t.Fatalf("Since after shrink = %#v", got)
+	}
+}
+
+func TestCustomReadUsageUsesTranscriptFormat(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"assistant","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":4,"output_tokens":6}}}`,
+	)
+	cfg := testCustomConfig()
+	got, err := NewCustom("aider", cfg).ReadUsage(path)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("without transcriptFormat: usage = %#v, err = %v", got, err)
+	}
+	cfg.Hooks.TranscriptFormat = "claude"
+	got, err = NewCustom("aider", cfg).ReadUsage(path)
+	if err != nil || got["claude-sonnet-4-5"] != (TokenUsage{Input: 4, Output: 6}) {
+		t.Fatalf("with transcriptFormat: usage = %#v, err = %v", got, err)
+	}
+}
@@ -1,6 +1,7 @@
 package harness
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -30,6 +31,15 @@ type LegacyHook struct {
 	Command string
 }
 
+// Event statuses a custom harness can map its hook events to. END removes
+// the session, like a built-in SessionEnd.
+const (
+	EventStatusBusy = "BUSY"
+	EventStatusWait = "WAIT"
+	EventStatusDone = "DONE"
+	EventStatusEnd  = "END"
+)
+
 type NormalizedHook struct {
 	HarnessID      string
 	SessionID      string
@@ -40,6 +50,21 @@ type NormalizedHook struct {
 	Model          string
 	TurnID         string
 	PermissionMode string
+	// MappedStatus is set by harnesses that resolve the session status
+	// themselves (see Custom); built-ins leave it empty.
+	MappedStatus string
+	// ToolUse marks the event as the start of a tool call for ToolCount.
+	ToolUse bool
+	// TranscriptPath is the harness's local transcript for the session,
+	// read by UsageReader implementations to account for tokens.
+	TranscriptPath string
+	// ToolInput is the raw arguments of the tool call, and Message the
+	// text of a notification, shown while a permission prompt is pending.
+	ToolInput json.RawMessage
+	Message   string
+	// PermissionPrompt marks a notification that the agent is asking for
+	// permission to run a tool, as opposed to a question or idle prompt.
+	PermissionPrompt bool
 }
 
 type LaunchCommand struct {
@@ -60,6 +85,26 @@ type ShellLaunchOptions struct {
 	Overrides    config.AgentHarnessConfig
 }
 
+// PermissionKeys are the tmux keys that answer a harness's permission
+// prompt.
+type PermissionKeys struct {
+	Approve []string
+	Deny    []string
+}
+
+// permissionKeys applies the approveKeys and denyKeys overrides to a
+// harness's defaults.
+func permissionKeys(overrides config.AgentHarnessConfig, approve, deny []string) PermissionKeys {
+	keys := PermissionKeys{Approve: approve, Deny: deny}
+	if len(overrides.ApproveKeys) > 0 {
+		keys.Approve = overrides.ApproveKeys
+	}
+	if len(overrides.DenyKeys) > 0 {
+		keys.Deny = overrides.DenyKeys
+	}
+	return keys
+}
+
 type Harness interface {
 	ID() string
 	DisplayName() string
@@ -76,14 +121,39 @@ type Harness interface {
 	NormalizeHook(raw []byte) (NormalizedHook, bool)
 	BuildLaunch(LaunchOptions) LaunchCommand
 	BuildShellLaunch(ShellLaunchOptions) string
+	PermissionKeys(overrides config.AgentHarnessConfig) PermissionKeys
 }
 
-var registry = map[string]Harness{}
+var (
+	registry = map[string]Harness{}
+	custom   = map[string]bool{}
+)
 
 func Register(h Harness) {
 	registry[h.ID()] = h
 }
 
+// RegisterCustom registers a Custom harness for every agent.harnesses entry
+// whose ID is not built in and that declares a command. Custom harnesses from
+// a previous call are dropped first, so the registry always mirrors cfg.
+func RegisterCustom(cfg *config.Config) {
+	for id := range custom {
+		delete(registry, id)
+		delete(custom, id)
+	}
+	if cfg == nil {
+		return
+	}
+	for rawID, hc := range cfg.Agent.Harnesses {
+		id := NormalizeID(rawID)
+		if _, builtin := registry[id]; builtin || strings.TrimSpace(hc.Command) == "" {
+			continue
+		}
+		registry[id] = NewCustom(id, hc)
+		custom[id] = true
+	}
+}
+
 func Get(id string) (Harness, bool) {
 	id = NormalizeID(id)
 	h, ok := registry[id]
@@ -152,7 +222,7 @@ func appendYoloArgs(args []string, opts LaunchOptions, defaults []string) []stri
 	return append(args, yoloArgs...)
 }
 
-func launchCommand(defaultCommand string, defaultArgs []string, opts LaunchOptions, promptFirst bool, yoloDefaults []string) LaunchCommand {
+func launchCommand(defaultCommand string, defaultArgs []string, opts LaunchOptions, promptFirst bool, promptFlag string, yoloDefaults []string) LaunchCommand {
 	command := defaultCommand
 	if opts.Overrides.Command != "" {
 		command = opts.Overrides.Command
@@ -161,21 +231,21 @@ func launchCommand(defaultCommand string, defaultArgs []string, opts LaunchOptio
 	if len(args) == 0 {
 		args = append(args, defaultArgs...)
 	}
+	var promptArgs []string
+	if opts.Prompt != "" {
+		promptArgs = withPromptFlag(promptFlag, opts.Prompt)
+	}
 	if promptFirst {
-		if opts.Prompt != "" {
-			args = append(args, opts.Prompt)
-		}
+		args = append(args, promptArgs...)
 		args = appendYoloArgs(args, opts, yoloDefaults)
 	} else {
 		args = appendYoloArgs(args, opts, yoloDefaults)
-		if opts.Prompt != "" {
-			args = append(args, opts.Prompt)
-		}
+		args = append(args, promptArgs...)
 	}
 	return LaunchCommand{Command: command, Args: args}
 }
 
-func shellLaunch(defaultCommand string, defaultArgs []string, opts ShellLaunchOptions, promptFirst bool, yoloDefaults []string) string {
+func shellLaunch(defaultCommand string, defaultArgs []string, opts ShellLaunchOptions, promptFirst bool, promptFlag string, yoloDefaults []string) string {
 	command := defaultCommand
 	if opts.Overrides.Command != "" {
 		command = opts.Overrides.Command
@@ -195,16 +265,16 @@ func shellLaunch(defaultCommand string, defaultArgs []string, opts ShellLaunchOp
 			yoloArgs = opts.Overrides.YoloArgs
 		}
 	}
+	var promptArgs []string
+	if promptArg != "" {
+		promptArgs = withPromptFlag(promptFlag, promptArg)
+	}
 	if promptFirst {
-		if promptArg != "" {
-			args = append(args, promptArg)
-		}
+		args = append(args, promptArgs...)
 		args = append(args, yoloArgs...)
 	} else {
 		args = append(args, yoloArgs...)
-		if promptArg != "" {
-			args = append(args, promptArg)
-		}
+		args = append(args, promptArgs...)
 	}
 	parts := []string{shellQuote(command)}
 	for _, arg := range args {
@@ -220,3 +290,10 @@ func shellLaunch(defaultCommand string, defaultArgs []string, opts ShellLaunchOp
 	}
 	return strings.Join(parts, " ")
 }
+
+func withPromptFlag(flag, prompt string) []string {
+	if flag == "" {
+		return []string{prompt}
+	}
+	return []string{flag, prompt}
+}
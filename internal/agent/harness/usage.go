@@ -0,0 +1,206 @@
+package harness
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// maxTranscriptLine bounds a single transcript line. Tool results can embed
+// whole files, so this is generous.
+const maxTranscriptLine = 16 * 1024 * 1024
+
+// TokenUsage counts tokens for one model. Input excludes cached input, which
+// is reported separately as CacheRead (and CacheWrite for prompt caching that
+// bills writes).
+type TokenUsage struct {
+	Input      int64 `json:"input,omitempty"`
+	Output     int64 `json:"output,omitempty"`
+	CacheRead  int64 `json:"cache_read,omitempty"`
+	CacheWrite int64 `json:"cache_write,omitempty"`
+}
+
+func (t TokenUsage) Total() int64 {
+	return t.Input + t.Output + t.CacheRead + t.CacheWrite
+}
+
+func (t TokenUsage) Add(o TokenUsage) TokenUsage {
+	return TokenUsage{
+		Input:      t.Input + o.Input,
+		Output:     t.Output + o.Output,
+		CacheRead:  t.CacheRead + o.CacheRead,
+		CacheWrite: t.CacheWrite + o.CacheWrite,
+	}
+}
+
+func (t TokenUsage) Sub(o TokenUsage) TokenUsage {
+	return TokenUsage{
+		Input:      t.Input - o.Input,
+		Output:     t.Output - o.Output,
+		CacheRead:  t.CacheRead - o.CacheRead,
+		CacheWrite: t.CacheWrite - o.CacheWrite,
+	}
+}
+
+// Usage is a session's cumulative token usage keyed by model.
+type Usage map[string]TokenUsage
+
+// Total sums usage across models.
+func (u Usage) Total() TokenUsage {
+	var t TokenUsage
+	for _, m := range u {
+		t = t.Add(m)
+	}
+	return t
+}
+
+// Since returns the usage added after prev. Models whose counts went
+// backwards (a rewritten transcript) are reported from zero.
+func (u Usage) Since(prev Usage) Usage {
+	delta := Usage{}
+	for model, cur := range u {
+		d := cur.Sub(prev[model])
+		if d.Input < 0 || d.Output < 0 || d.CacheRead < 0 || d.CacheWrite < 0 {
+			d = cur
+		}
+		if d.Total() > 0 {
+			delta[model] = d
+		}
+	}
+	return delta
+}
+
+// UsageReader is implemented by harnesses whose transcripts record token
+// usage. ReadUsage returns the session's cumulative usage so far.
+type UsageReader interface {
+	ReadUsage(transcriptPath string) (Usage, error)
+}
+
+func (Claude) ReadUsage(path string) (Usage, error) { return readClaudeUsage(path) }
+func (Codex) ReadUsage(path string) (Usage, error)  { return readCodexUsage(path) }
+
+// ReadUsage reads the transcript with the parser named by
+// hooks.transcriptFormat. Custom harnesses without one report no usage.
+func (c Custom) ReadUsage(path string) (Usage, error) {
+	if c.cfg.Hooks == nil {
+		return nil, nil
+	}
+	switch c.cfg.Hooks.TranscriptFormat {
+	case ClaudeID:
+		return readClaudeUsage(path)
+	case CodexID:
+		return readCodexUsage(path)
+	}
+	return nil, nil
+}
+
+type claudeTranscriptLine struct {
+	Type      string `json:"type"`
+	RequestID string `json:"requestId"`
+	Message   struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// readClaudeUsage sums message.usage over assistant entries. Claude Code
+// writes one line per content block with the same message usage repeated,
+// so entries are de-duplicated by message and request ID.
+func readClaudeUsage(path string) (Usage, error) {
+	usage := Usage{}
+	seen := map[string]bool{}
+	err := scanTranscript(path, func(line []byte) {
+		var entry claudeTranscriptLine
+		if json.Unmarshal(line, &entry) != nil || entry.Type != "assistant" || entry.Message.Usage == nil {
+			return
+		}
+		if key := entry.Message.ID + ":" + entry.RequestID; key != ":" {
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+		}
+		model := entry.Message.Model
+		if model == "" || model == "<synthetic>" {
+			return
+		}
+		u := entry.Message.Usage
+		usage[model] = usage[model].Add(TokenUsage{
+			Input:      u.InputTokens,
+			Output:     u.OutputTokens,
+			CacheRead:  u.CacheReadInputTokens,
+			CacheWrite: u.CacheCreationInputTokens,
+		})
+	})
+	return usage, err
+}
+
+type codexTranscriptLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		Model string `json:"model"`
+		Info  *struct {
+			TotalTokenUsage struct {
+				InputTokens       int64 `json:"input_tokens"`
+				CachedInputTokens int64 `json:"cached_input_tokens"`
+				OutputTokens      int64 `json:"output_tokens"`
+			} `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+// readCodexUsage follows the running total_token_usage in token_count
+// events and attributes each increase to the model from the latest
+// turn_context, so sessions that switch models are split correctly.
+func readCodexUsage(path string) (Usage, error) {
+	usage := Usage{}
+	model := "unknown"
+	var prev TokenUsage
+	err := scanTranscript(path, func(line []byte) {
+		var entry codexTranscriptLine
+		if json.Unmarshal(line, &entry) != nil {
+			return
+		}
+		switch {
+		case entry.Type == "turn_context" && entry.Payload.Model != "":
+			model = entry.Payload.Model
+		case entry.Type == "event_msg" && entry.Payload.Type == "token_count" && entry.Payload.Info != nil:
+			t := entry.Payload.Info.TotalTokenUsage
+			total := TokenUsage{
+				Input:     t.InputTokens - t.CachedInputTokens,
+				Output:    t.OutputTokens,
+				CacheRead: t.CachedInputTokens,
+			}
+			delta := total.Sub(prev)
+			if delta.Input < 0 || delta.Output < 0 || delta.CacheRead < 0 {
+				delta = total
+			}
+			prev = total
+			if delta.Total() > 0 {
+				usage[model] = usage[model].Add(delta)
+			}
+		}
+	})
+	return usage, err
+}
+
+func scanTranscript(path string, fn func(line []byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLine)
+	for scanner.Scan() {
+		fn(scanner.Bytes())
+	}
+	return scanner.Err()
+}
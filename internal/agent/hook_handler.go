@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/iamrajjoshi/willow/internal/agent/harness"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/notify"
 	"github.com/iamrajjoshi/willow/internal/telemetry"
+	"github.com/iamrajjoshi/willow/internal/usage"
 )
 
 // HookInput models the Claude-shaped hook payload. It stays exported for tests
@@ -58,25 +61,81 @@ func HandleHook(r io.Reader, harnessIDs ...string) error {
 	destDir := SessionDir(repo, wt, h.ID())
 	destFile := SessionPath(repo, wt, h.ID(), in.SessionID)
 
-	if in.EventName == "SessionEnd" || in.EventName == "sessionEnd" {
+	if !isSessionEnd(in) {
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return fmt.Errorf("mkdir status dir: %w", err)
+		}
+	}
+
+	// Hooks for one session can overlap, e.g. Stop and SessionEnd or a
+	// retried Stop. Holding the session lock from reading the previous
+	// state to writing the new one and appending to the usage ledger keeps
+	// both from adding the same usage delta.
+	var notifySession *SessionStatus
+	var notifyDuration time.Duration
+	err = withSessionLock(destDir, in.SessionID, func() error {
+		var err error
+		notifySession, notifyDuration, err = applyHook(repo, wt, h, in, destFile)
+		return err
+	})
+	if err != nil || notifySession == nil {
+		return err
+	}
+	fireNotifications(repo, wt, *notifySession, notifyDuration)
+	return nil
+}
+
+func isSessionEnd(in harness.NormalizedHook) bool {
+	return in.EventName == "SessionEnd" || in.EventName == "sessionEnd" || in.MappedStatus == harness.EventStatusEnd
+}
+
+// withSessionLock runs fn while holding an exclusive flock on the
+// session's lock file in dir. Like withNotifyLock, it falls back to running
+// fn unguarded when the lock can't be taken, e.g. at SessionEnd for a
+// session that never wrote a status file.
+func withSessionLock(dir, sessionID string, fn func() error) error {
+	f, err := os.OpenFile(filepath.Join(dir, sessionID+".lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fn()
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fn()
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return fn()
+}
+
+// applyHook writes the session state for one normalized hook event. It
+// returns the written session and how long it spent in its previous status,
+// or nil when nothing was written.
+func applyHook(repo, wt string, h harness.Harness, in harness.NormalizedHook, destFile string) (*SessionStatus, time.Duration, error) {
+	if isSessionEnd(in) {
+		// Pick up tokens from a turn that ended without a Stop, e.g. an
+		// interrupted one, before the session is archived.
+		if ss := readSession(destFile); ss.SessionID != "" {
+			ss.TranscriptPath = nonEmpty(in.TranscriptPath, ss.TranscriptPath)
+			ss.Timestamp = time.Now().UTC()
+			if recordUsage(repo, wt, h, &ss) {
+				_ = writeSession(destFile, ss)
+			}
+		}
+		_ = archiveSession(repo, wt, h.ID(), in.SessionID, time.Now())
 		_ = removeSessionArtifacts(repo, wt, h.ID(), in.SessionID)
-		return nil
+		return nil, 0, nil
 	}
 
 	status, skip := computeStatus(h.ID(), in, destFile)
 	if skip {
-		return nil
-	}
-
-	if err := os.MkdirAll(destDir, 0o755); err != nil {
-		return fmt.Errorf("mkdir status dir: %w", err)
+		return nil, 0, nil
 	}
 
 	now := time.Now().UTC()
 	prev := readSession(destFile)
 
+	toolUse := in.ToolUse || in.EventName == "PreToolUse" || in.EventName == "preToolUse"
 	toolCount := prev.ToolCount
-	if in.EventName == "PreToolUse" || in.EventName == "preToolUse" {
+	if toolUse {
 		toolCount++
 	}
 
@@ -86,7 +145,7 @@ func HandleHook(r io.Reader, harnessIDs ...string) error {
 	}
 
 	toolField := ""
-	if in.EventName == "PreToolUse" || in.EventName == "preToolUse" || in.EventName == "PermissionRequest" {
+	if toolUse || in.EventName == "PermissionRequest" {
 		toolField = in.ToolName
 	}
 
@@ -106,21 +165,43 @@ func HandleHook(r io.Reader, harnessIDs ...string) error {
 		Timestamp:      now,
 		StartTime:      startTime,
 		Worktree:       wt,
+		TranscriptPath: nonEmpty(in.TranscriptPath, prev.TranscriptPath),
+		Usage:          prev.Usage,
+		CostUSD:        prev.CostUSD,
+	}
+	// Transcripts are re-read in full, so only at the end of a turn rather
+	// than on every tool call or prompt.
+	if status == StatusDone {
+		recordUsage(repo, wt, h, &session)
 	}
 	if err := writeSession(destFile, session); err != nil {
-		return fmt.Errorf("write session: %w", err)
+		return nil, 0, fmt.Errorf("write session: %w", err)
 	}
 
-	appendTimeline(TimelinePathForHarness(repo, wt, h.ID(), in.SessionID), status, now)
+	timelinePath := TimelinePathForHarness(repo, wt, h.ID(), in.SessionID)
+	// The previous status change marks how long the session spent in the
+	// status it is leaving, reported to notification sinks.
+	prevChange := lastTimelineEntry(timelinePath).Time
+	if prevChange.IsZero() {
+		prevChange = startTime
+	}
+	appendTimeline(timelinePath, status, now)
+	if toolUse && in.ToolName != "" {
+		appendTool(ToolsPathForHarness(repo, wt, h.ID(), in.SessionID), in.ToolName, now)
+	}
+	updatePending(PendingPathForHarness(repo, wt, h.ID(), in.SessionID), in, toolField, status, now)
 
-	fireNotifications(repo, wt)
-	return nil
+	return &session, now.Sub(prevChange), nil
 }
 
 // computeStatus returns the new status for this event plus a skip flag.
 // Skip=true means the event should not touch the status file
 // (e.g. Notification arriving while session is already DONE or BUSY).
 func computeStatus(harnessID string, in harness.NormalizedHook, destFile string) (Status, bool) {
+	if in.MappedStatus != "" {
+		return Status(in.MappedStatus), false
+	}
+
 	if harnessID == harness.CodexID {
 		switch in.EventName {
 		case "UserPromptSubmit", "PreToolUse", "PostToolUse":
@@ -269,8 +350,7 @@ func appendFileList(path, filePath string) {
 // appendTimeline appends a JSONL entry only when the status changes relative
 // to the last recorded entry. Best-effort.
 func appendTimeline(path string, status Status, ts time.Time) {
-	last := lastTimelineStatus(path)
-	if last == status {
+	if lastTimelineEntry(path).Status == status {
 		return
 	}
 	entry, err := json.Marshal(TimelineEntry{Status: status, Time: ts})
@@ -286,10 +366,10 @@ func appendTimeline(path string, status Status, ts time.Time) {
 	f.Write([]byte("\n"))
 }
 
-func lastTimelineStatus(path string) Status {
+func lastTimelineEntry(path string) TimelineEntry {
 	f, err := os.Open(path)
 	if err != nil {
-		return ""
+		return TimelineEntry{}
 	}
 	defer f.Close()
 
@@ -299,13 +379,45 @@ func lastTimelineStatus(path string) Status {
 		lastLine = append(lastLine[:0], scanner.Bytes()...)
 	}
 	if len(lastLine) == 0 {
-		return ""
+		return TimelineEntry{}
 	}
 	var e TimelineEntry
 	if err := json.Unmarshal(lastLine, &e); err != nil {
-		return ""
+		return TimelineEntry{}
 	}
-	return e.Status
+	return e
+}
+
+// recordUsage refreshes session's cumulative token usage from its
+// transcript and appends whatever the session added since the last refresh
+// to the usage ledger, reporting whether anything was added. Unreadable
+// transcripts leave the previous totals.
+func recordUsage(repo, wt string, h harness.Harness, session *SessionStatus) bool {
+	reader, ok := h.(harness.UsageReader)
+	if !ok || session.TranscriptPath == "" {
+		return false
+	}
+	current, err := reader.ReadUsage(session.TranscriptPath)
+	if err != nil || len(current) == 0 {
+		return false
+	}
+	delta := current.Since(session.Usage)
+	session.Usage = current
+	session.CostUSD, _ = usage.Cost(current)
+	if len(delta) == 0 {
+		return false
+	}
+	cost, _ := usage.Cost(delta)
+	_ = usage.Append(usage.Record{
+		Timestamp: session.Timestamp,
+		Repo:      repo,
+		Worktree:  wt,
+		Harness:   session.Harness,
+		Session:   session.SessionID,
+		Usage:     delta,
+		CostUSD:   cost,
+	})
+	return true
 }
 
 // fireNotifications aggregates sessions for this worktree, detects transitions
@@ -313,48 +425,70 @@ func lastTimelineStatus(path string) Status {
 // transition detection happen inside the flock so concurrent hooks across
 // sibling sessions observe a consistent view of the state file — otherwise
 // two hooks could each read the prior state, each compute "BUSY→DONE", and
-// emit duplicate notifications.
-func fireNotifications(repo, wt string) {
+// emit duplicate notifications. session is the hook's session and duration
+// how long it spent in its previous status; both are reported to sinks.
+//
+// Desktop notifications fire on BUSY → non-BUSY transitions only, while
+// sinks see every status change so their status filters can match any
+// status.
+func fireNotifications(repo, wt string, session SessionStatus, duration time.Duration) {
 	key := repo + "/" + wt
 
-	var transitions []Transition
+	var transitions, changes []Transition
 	_ = withNotifyLock(func() error {
 		sessions := ReadAllSessions(repo, wt)
-		agg := AggregateStatus(sessions)
-		transitions = DetectTransitions(
-			map[string]Status{key: agg.Status},
-			NotifyStateFile(),
-		)
+		current := map[string]Status{key: AggregateStatus(sessions).Status}
+		prev := map[string]Status{}
+		if status, ok := loadTransitionState(NotifyStateFile())[key]; ok {
+			prev[key] = Status(status)
+		}
+		transitions = DetectTransitions(current, NotifyStateFile())
+		changes = DiffStatuses(prev, current)
 		return nil
 	})
-	if len(transitions) == 0 {
+	if len(changes) == 0 {
 		return
 	}
 
 	cfg := config.Load("")
-	if cfg.Notify.Desktop != nil && !*cfg.Notify.Desktop && cfg.Notify.Command == "" {
+	desktop := cfg.Notify.Desktop == nil || *cfg.Notify.Desktop
+	if !desktop && cfg.Notify.Command == "" && len(cfg.Notify.Sinks) == 0 {
 		return
 	}
 
-	for _, tr := range transitions {
-		if tr.Key != key {
+	for _, tr := range changes {
+		if !sinksWant(cfg.Notify.Sinks, string(tr.ToStatus)) {
 			continue
 		}
-		var body string
-		switch tr.ToStatus {
-		case StatusDone:
-			body = fmt.Sprintf("\u2705 %s finished", tr.Key)
-		case StatusWait:
-			body = fmt.Sprintf("\u23F3 %s needs input", tr.Key)
-		default:
-			continue
+		payload := notify.Payload{
+			Repo:            repo,
+			Worktree:        wt,
+			Harness:         session.Harness,
+			Session:         session.SessionID,
+			From:            string(tr.FromStatus),
+			To:              string(tr.ToStatus),
+			DurationSeconds: int64(duration.Seconds()),
+			Time:            session.Timestamp,
+			Message:         transitionMessage(tr),
 		}
+		if payload.Message == "" {
+			payload.Message = fmt.Sprintf("%s %s → %s", tr.Key, tr.FromStatus, tr.ToStatus)
+		}
+		if err := deliverSinks(payload); err != nil {
+			telemetry.CaptureException(err)
+		}
+	}
 
+	for _, tr := range transitions {
+		body := transitionMessage(tr)
+		if body == "" {
+			continue
+		}
 		var err error
 		switch {
 		case cfg.Notify.Command != "":
 			err = notify.SendCustom(cfg.Notify.Command, "willow", body)
-		case cfg.Notify.Desktop == nil || *cfg.Notify.Desktop:
+		case desktop:
 			err = notify.Send("willow", body)
 		}
 		if err != nil {
@@ -362,3 +496,45 @@ func fireNotifications(repo, wt string) {
 		}
 	}
 }
+
+func sinksWant(sinks []config.NotifySinkConfig, status string) bool {
+	for _, sink := range sinks {
+		if notify.SinkWants(sink, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverSinks hands p to a detached `ww notify deliver`, which posts it to
+// the configured sinks with retries. Webhooks that are slow or unreachable
+// then never hold up the agent's hook. Tests replace it to deliver inline.
+var deliverSinks = func(p notify.Payload) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("notify sinks: %w", err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("notify sinks: %w", err)
+	}
+	cmd := exec.Command(self, "notify", "deliver", string(data))
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("notify sinks: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// transitionMessage returns the notification text for transitions desktop
+// notifications fire on, or "" for the rest.
+func transitionMessage(tr Transition) string {
+	switch tr.ToStatus {
+	case StatusDone:
+		return fmt.Sprintf("\u2705 %s finished", tr.Key)
+	case StatusWait:
+		return fmt.Sprintf("\u23F3 %s needs input", tr.Key)
+	default:
+		return ""
+	}
+}
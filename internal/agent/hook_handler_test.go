@@ -2,12 +2,21 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/notify"
+	"github.com/iamrajjoshi/willow/internal/usage"
 )
 
 // setupWorktreeHome creates a fake willow base dir with a worktree at
@@ -190,13 +199,57 @@ func TestHandleHook_CursorSessionEndRemovesFiles(t *testing.T) {
 		t.Fatalf("HandleHook: %v", err)
 	}
 
-	for _, ext := range []string{".json", ".files", ".timeline"} {
+	for _, ext := range []string{".json", ".files", ".timeline", ".tools"} {
 		if _, err := os.Stat(filepath.Join(dir, "s1"+ext)); !os.IsNotExist(err) {
 			t.Errorf("%s should be removed", ext)
 		}
 	}
 }
 
+func TestHandleHook_CustomHarnessMapsEvents(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+	cfg := config.DefaultConfig()
+	cfg.Agent.Harnesses = map[string]config.AgentHarnessConfig{
+		"gemini": {
+			Command: "gemini",
+			Hooks: &config.HarnessHookConfig{
+				Events:     map[string]string{"BeforeTool": "BUSY", "AfterAgent": "DONE", "SessionEnd": "END"},
+				ToolEvents: []string{"BeforeTool"},
+				Fields:     config.HarnessFieldsConfig{FilePath: "tool_input.file_path"},
+			},
+		},
+	}
+	harness.RegisterCustom(cfg)
+	t.Cleanup(func() { harness.RegisterCustom(nil) })
+
+	send := func(raw string) {
+		t.Helper()
+		if err := HandleHook(strings.NewReader(raw), "gemini"); err != nil {
+			t.Fatalf("HandleHook: %v", err)
+		}
+	}
+
+	send(`{"session_id":"g1","hook_event_name":"BeforeTool","tool_name":"write_file","tool_input":{"file_path":"a.go"}}`)
+	got := readSession(SessionPath(repo, wt, "gemini", "g1"))
+	if got.Status != StatusBusy || got.ToolCount != 1 || got.Tool != "write_file" || got.Harness != "gemini" {
+		t.Fatalf("session after BeforeTool = %#v", got)
+	}
+	if files := ReadFilesTouched(repo, wt, "g1"); len(files) != 1 || files[0] != "a.go" {
+		t.Fatalf("files touched = %v, want [a.go]", files)
+	}
+
+	send(`{"session_id":"g1","hook_event_name":"Unmapped"}`)
+	send(`{"session_id":"g1","hook_event_name":"AfterAgent"}`)
+	if got := readSession(SessionPath(repo, wt, "gemini", "g1")); got.Status != StatusDone {
+		t.Fatalf("status = %q, want %q", got.Status, StatusDone)
+	}
+
+	send(`{"session_id":"g1","hook_event_name":"SessionEnd"}`)
+	if _, err := os.Stat(SessionPath(repo, wt, "gemini", "g1")); !os.IsNotExist(err) {
+		t.Fatal("END event should remove the session file")
+	}
+}
+
 func TestHandleHook_CursorTracksEditedFiles(t *testing.T) {
 	repo, wt := setupWorktreeHome(t)
 
@@ -225,6 +278,7 @@ func TestHandleHook_SessionEndRemovesFiles(t *testing.T) {
 	os.WriteFile(filepath.Join(dir, "s1.json"), []byte("{}"), 0o644)
 	os.WriteFile(filepath.Join(dir, "s1.files"), []byte("/x"), 0o644)
 	os.WriteFile(filepath.Join(dir, "s1.timeline"), []byte("{}"), 0o644)
+	os.WriteFile(filepath.Join(dir, "s1.tools"), []byte("{}"), 0o644)
 
 	in := HookInput{SessionID: "s1", HookEventName: "SessionEnd"}
 	raw, _ := json.Marshal(in)
@@ -239,6 +293,44 @@ func TestHandleHook_SessionEndRemovesFiles(t *testing.T) {
 	}
 }
 
+func TestHandleHook_SessionEndArchivesSession(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+
+	send := func(ev HookInput) {
+		raw, _ := json.Marshal(ev)
+		HandleHook(bytes.NewReader(raw))
+	}
+	send(HookInput{SessionID: "s1", HookEventName: "UserPromptSubmit"})
+	send(HookInput{SessionID: "s1", HookEventName: "PreToolUse", ToolName: "Edit", FilePath: "/x/main.go"})
+	send(HookInput{SessionID: "s1", HookEventName: "Stop"})
+	send(HookInput{SessionID: "s1", HookEventName: "SessionEnd"})
+
+	archived, err := ReadArchive(ArchiveReadOpts{Repo: repo})
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	if len(archived) != 1 {
+		t.Fatalf("archived %d sessions, want 1", len(archived))
+	}
+	a := archived[0]
+	if a.Worktree != wt || a.Harness != "claude" || a.Status != StatusDone || a.ToolCount != 1 {
+		t.Errorf("archived session = %+v", a)
+	}
+	if len(a.FilesTouched) != 1 || a.FilesTouched[0] != "/x/main.go" || len(a.Timeline) != 2 {
+		t.Errorf("archived files = %v, timeline = %v", a.FilesTouched, a.Timeline)
+	}
+	if a.StartTime.IsZero() || a.EndTime.Before(a.StartTime) {
+		t.Errorf("archived times = %v..%v", a.StartTime, a.EndTime)
+	}
+
+	if n, err := PruneArchive(time.Now().Add(time.Hour), false); err != nil || n != 1 {
+		t.Fatalf("PruneArchive = %d, %v; want 1 removed", n, err)
+	}
+	if archived, _ := ReadArchive(ArchiveReadOpts{}); len(archived) != 0 {
+		t.Errorf("archive still has %d sessions after prune", len(archived))
+	}
+}
+
 func TestHandleHook_NotificationRespectsBusy(t *testing.T) {
 	repo, wt := setupWorktreeHome(t)
 	// Prime session as BUSY
@@ -289,6 +381,80 @@ func TestHandleHook_TracksWriteFiles(t *testing.T) {
 	}
 }
 
+func TestHandleHook_RecordsLastTurnTools(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+
+	send := func(ev HookInput) {
+		raw, _ := json.Marshal(ev)
+		HandleHook(bytes.NewReader(raw))
+	}
+	send(HookInput{SessionID: "s1", HookEventName: "UserPromptSubmit"})
+	send(HookInput{SessionID: "s1", HookEventName: "PreToolUse", ToolName: "Read"})
+	send(HookInput{SessionID: "s1", HookEventName: "Stop"})
+	send(HookInput{SessionID: "s1", HookEventName: "UserPromptSubmit"})
+	send(HookInput{SessionID: "s1", HookEventName: "PreToolUse", ToolName: "Edit"})
+	send(HookInput{SessionID: "s1", HookEventName: "PreToolUse", ToolName: "Bash"})
+	send(HookInput{SessionID: "s1", HookEventName: "Stop"})
+
+	timeline, err := ReadTimeline(repo, wt, "s1", time.Time{})
+	if err != nil {
+		t.Fatalf("ReadTimeline: %v", err)
+	}
+	tools, err := ReadTools(repo, wt, "s1", LastTurnStart(timeline))
+	if err != nil {
+		t.Fatalf("ReadTools: %v", err)
+	}
+	var names []string
+	for _, e := range tools {
+		names = append(names, e.Tool)
+	}
+	if strings.Join(names, ",") != "Edit,Bash" {
+		t.Errorf("last turn tools = %v, want [Edit Bash]", names)
+	}
+}
+
+func TestHandleHook_RecordsPendingPermission(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+
+	send := func(raw string) {
+		HandleHook(strings.NewReader(raw))
+	}
+	send(`{"session_id":"s1","hook_event_name":"UserPromptSubmit"}`)
+	send(`{"session_id":"s1","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"rm -rf build"}}`)
+	send(`{"session_id":"s1","hook_event_name":"Notification","notification_type":"permission_prompt","message":"Claude needs your permission to use Bash"}`)
+
+	got := readSession(SessionPath(repo, wt, harness.ClaudeID, "s1"))
+	if got.Status != StatusWait {
+		t.Fatalf("status = %q, want WAIT after a permission prompt", got.Status)
+	}
+	p := ReadPending(repo, wt, harness.ClaudeID, "s1")
+	if p == nil {
+		t.Fatal("no pending tool recorded")
+	}
+	if p.Summary() != "Bash: rm -rf build" || p.Message != "Claude needs your permission to use Bash" {
+		t.Errorf("pending = %q / %q", p.Summary(), p.Message)
+	}
+	if !p.IsPermission() {
+		t.Errorf("pending kind = %q, want a permission prompt", p.Kind)
+	}
+
+	send(`{"session_id":"s1","hook_event_name":"PostToolUse","tool_name":"Bash"}`)
+	if p := ReadPending(repo, wt, harness.ClaudeID, "s1"); p != nil {
+		t.Errorf("pending tool should be cleared once the tool ran, got %+v", p)
+	}
+
+	// Questions and generic notifications also WAIT, but are not permission
+	// prompts.
+	send(`{"session_id":"s1","hook_event_name":"PreToolUse","tool_name":"AskUserQuestion","tool_input":{"questions":[]}}`)
+	if p := ReadPending(repo, wt, harness.ClaudeID, "s1"); p == nil || p.IsPermission() {
+		t.Errorf("AskUserQuestion pending = %+v, want a non-permission record", p)
+	}
+	send(`{"session_id":"s1","hook_event_name":"Notification","message":"Claude is waiting for your input"}`)
+	if p := ReadPending(repo, wt, harness.ClaudeID, "s1"); p == nil || p.IsPermission() {
+		t.Errorf("idle notification pending = %+v, want a non-permission record", p)
+	}
+}
+
 func TestHandleHook_TimelineDedupes(t *testing.T) {
 	repo, wt := setupWorktreeHome(t)
 
@@ -428,3 +594,219 @@ func TestResolveWorktree_UsesConfiguredBaseDir(t *testing.T) {
 		t.Fatalf("resolveWorktree() = (%q, %q), want (%q, %q)", repo, wt, "configrepo", "branch-x")
 	}
 }
+
+// deliverSinksInline replaces the detached sink delivery with a direct
+// SendSinks call so tests can observe payloads.
+func deliverSinksInline(t *testing.T, sinks []config.NotifySinkConfig) {
+	t.Helper()
+	prev := deliverSinks
+	deliverSinks = func(p notify.Payload) error {
+		return notify.SendSinks(context.Background(), sinks, p)
+	}
+	t.Cleanup(func() { deliverSinks = prev })
+}
+
+// collectSinkPayloads starts a webhook server and saves a global config
+// with one sink posting to it, filtered to statuses.
+func collectSinkPayloads(t *testing.T, statuses ...string) func() []notify.Payload {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		payloads []notify.Payload
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p notify.Payload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		payloads = append(payloads, p)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	sinks := []config.NotifySinkConfig{{Type: "webhook", URL: srv.URL, Statuses: statuses}}
+	cfg := &config.Config{Notify: config.NotifyConfig{Desktop: config.BoolPtr(false), Sinks: sinks}}
+	if err := config.Save(cfg, config.GlobalConfigPath()); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	deliverSinksInline(t, sinks)
+	return func() []notify.Payload {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]notify.Payload(nil), payloads...)
+	}
+}
+
+func TestHandleHook_DeliversTransitionsToSinks(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+	received := collectSinkPayloads(t)
+
+	for _, event := range []string{"UserPromptSubmit", "Stop"} {
+		raw, _ := json.Marshal(HookInput{SessionID: "s1", HookEventName: event})
+		if err := HandleHook(bytes.NewReader(raw)); err != nil {
+			t.Fatalf("HandleHook(%s): %v", event, err)
+		}
+	}
+
+	payloads := received()
+	// The default filter skips the transition into BUSY.
+	if len(payloads) != 1 {
+		t.Fatalf("sink received %d payloads, want 1: %+v", len(payloads), payloads)
+	}
+	p := payloads[0]
+	if p.Repo != repo || p.Worktree != wt || p.Harness != "claude" || p.Session != "s1" || p.From != "BUSY" || p.To != "DONE" {
+		t.Errorf("payload = %+v", p)
+	}
+	if !strings.Contains(p.Message, "finished") {
+		t.Errorf("message = %q, want finished text", p.Message)
+	}
+}
+
+func TestHandleHook_BusyFilteredSinkSeesEveryTransitionIntoBusy(t *testing.T) {
+	setupWorktreeHome(t)
+	received := collectSinkPayloads(t, "BUSY")
+
+	for _, event := range []string{"UserPromptSubmit", "Stop", "UserPromptSubmit", "PreToolUse"} {
+		raw, _ := json.Marshal(HookInput{SessionID: "s1", HookEventName: event})
+		if err := HandleHook(bytes.NewReader(raw)); err != nil {
+			t.Fatalf("HandleHook(%s): %v", event, err)
+		}
+	}
+
+	payloads := received()
+	if len(payloads) != 2 {
+		t.Fatalf("sink received %d payloads, want 2: %+v", len(payloads), payloads)
+	}
+	if payloads[0].From != string(StatusOffline) || payloads[1].From != "DONE" {
+		t.Errorf("payload sources = %q, %q; want %q, DONE", payloads[0].From, payloads[1].From, StatusOffline)
+	}
+	for _, p := range payloads {
+		if p.To != "BUSY" {
+			t.Errorf("payload to = %q, want BUSY", p.To)
+		}
+	}
+}
+
+func TestHandleHook_RecordsTranscriptUsage(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+
+	transcript := filepath.Join(t.TempDir(), "s1.jsonl")
+	writeLines := func(lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(transcript, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	turn1 := `{"type":"assistant","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":1000,"output_tokens":100}}}`
+	turn2 := `{"type":"assistant","requestId":"r2","message":{"id":"m2","model":"claude-sonnet-4-5","usage":{"input_tokens":500,"output_tokens":50}}}`
+	fire := func(event string) {
+		t.Helper()
+		raw, _ := json.Marshal(map[string]string{"session_id": "s1", "hook_event_name": event, "transcript_path": transcript})
+		if err := HandleHook(bytes.NewReader(raw)); err != nil {
+			t.Fatalf("HandleHook(%s): %v", event, err)
+		}
+	}
+
+	writeLines(turn1)
+	fire("UserPromptSubmit")
+	// Usage is only read when a turn ends, not while it waits on a prompt.
+	raw, _ := json.Marshal(map[string]string{"session_id": "s1", "hook_event_name": "Notification", "notification_type": "permission_prompt", "transcript_path": transcript})
+	if err := HandleHook(bytes.NewReader(raw)); err != nil {
+		t.Fatalf("HandleHook(Notification): %v", err)
+	}
+	if ss := readSession(SessionPath(repo, wt, "claude", "s1")); ss.Status != StatusWait || len(ss.Usage) != 0 {
+		t.Fatalf("session while waiting = %s with usage %#v, want WAIT and none", ss.Status, ss.Usage)
+	}
+	fire("Stop")
+	// A Stop with nothing new must not add a ledger record.
+	fire("Stop")
+	writeLines(turn1, turn2)
+	fire("Stop")
+
+	ss := readSession(SessionPath(repo, wt, "claude", "s1"))
+	if ss.TranscriptPath != transcript {
+		t.Errorf("TranscriptPath = %q", ss.TranscriptPath)
+	}
+	if got := ss.Usage["claude-sonnet-4-5"]; got != (harness.TokenUsage{Input: 1500, Output: 150}) {
+		t.Errorf("Usage = %#v", ss.Usage)
+	}
+	if ss.CostUSD <= 0 {
+		t.Errorf("CostUSD = %v, want > 0", ss.CostUSD)
+	}
+
+	records, err := usage.Read(usage.ReadOpts{})
+	if err != nil {
+		t.Fatalf("usage.Read: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ledger records = %d, want 2: %+v", len(records), records)
+	}
+	if records[1].Repo != repo || records[1].Worktree != wt || records[1].Usage["claude-sonnet-4-5"] != (harness.TokenUsage{Input: 500, Output: 50}) {
+		t.Errorf("second record = %+v", records[1])
+	}
+
+	// An interrupted turn never reaches Stop; SessionEnd records it before
+	// the session is archived.
+	turn3 := `{"type":"assistant","requestId":"r3","message":{"id":"m3","model":"claude-sonnet-4-5","usage":{"input_tokens":200,"output_tokens":20}}}`
+	writeLines(turn1, turn2, turn3)
+	fire("UserPromptSubmit")
+	fire("SessionEnd")
+	if records, _ := usage.Read(usage.ReadOpts{}); len(records) != 3 {
+		t.Fatalf("ledger records after SessionEnd = %d, want 3", len(records))
+	}
+	archived, err := ReadArchive(ArchiveReadOpts{Repo: repo})
+	if err != nil || len(archived) != 1 {
+		t.Fatalf("ReadArchive = %+v, %v", archived, err)
+	}
+	if got := archived[0].Usage["claude-sonnet-4-5"]; got != (harness.TokenUsage{Input: 1700, Output: 170}) {
+		t.Errorf("archived usage = %#v", archived[0].Usage)
+	}
+}
+
+func TestHandleHook_HoldsSessionLockThroughUsageAppend(t *testing.T) {
+	repo, wt := setupWorktreeHome(t)
+
+	transcript := filepath.Join(t.TempDir(), "s1.jsonl")
+	line := `{"type":"assistant","requestId":"r1","message":{"id":"m1","model":"claude-sonnet-4-5","usage":{"input_tokens":1000,"output_tokens":100}}}`
+	if err := os.WriteFile(transcript, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fire := func(event string) error {
+		raw, _ := json.Marshal(map[string]string{"session_id": "s1", "hook_event_name": event, "transcript_path": transcript})
+		return HandleHook(bytes.NewReader(raw))
+	}
+	if err := fire("UserPromptSubmit"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hook that overlaps another for the same session must wait for it,
+	// then see its usage and add nothing.
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go withSessionLock(SessionDir(repo, wt, harness.ClaudeID), "s1", func() error {
+		close(locked)
+		<-release
+		return nil
+	})
+	<-locked
+	done := make(chan error, 2)
+	go func() { done <- fire("Stop") }()
+	time.Sleep(50 * time.Millisecond)
+	if records, _ := usage.Read(usage.ReadOpts{}); len(records) != 0 {
+		t.Fatalf("Stop recorded usage while another hook held the session lock")
+	}
+	close(release)
+	go func() { done <- fire("Stop") }()
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := usage.Read(usage.ReadOpts{})
+	if err != nil {
+		t.Fatalf("usage.Read: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ledger records = %d, want 1", len(records))
+	}
+}
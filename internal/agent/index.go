@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+)
+
+// SessionIndex groups parsed sessions by worktree so callers that already hold
+// a snapshot (e.g. from the watcher or ww serve) can skip rescanning the
+// status directory.
+type SessionIndex struct {
+	byWorktree map[string][]*SessionStatus
+}
+
+func NewSessionIndex(sessions []SessionFileInfo) *SessionIndex {
+	idx := &SessionIndex{byWorktree: make(map[string][]*SessionStatus)}
+	for i := range sessions {
+		key := sessions[i].RepoName + "/" + sessions[i].WorktreeDir
+		ss := sessions[i].Session
+		idx.byWorktree[key] = append(idx.byWorktree[key], &ss)
+	}
+	for _, list := range idx.byWorktree {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Harness != list[j].Harness {
+				return list[i].Harness < list[j].Harness
+			}
+			return list[i].SessionID < list[j].SessionID
+		})
+	}
+	return idx
+}
+
+// ReadAllSessions returns the indexed sessions for a worktree. A nil index
+// falls back to reading the status directory.
+func (idx *SessionIndex) ReadAllSessions(repoName, worktreeDir string) []*SessionStatus {
+	if idx == nil {
+		return ReadAllSessions(repoName, worktreeDir)
+	}
+	return idx.byWorktree[repoName+"/"+worktreeDir]
+}
+
+// ReadSessionFile parses a single session status file, applying the same
+// defaults as ReadAllSessions. Returns nil if the file is missing or invalid.
+// Unlike ReadAllSessions it never deletes invalid files, since a watcher may
+// observe a file mid-write.
+func ReadSessionFile(repoName, worktreeDir, harnessID, sessionID string) *SessionStatus {
+	data, err := os.ReadFile(SessionPath(repoName, worktreeDir, harnessID, sessionID))
+	if err != nil {
+		return nil
+	}
+	var ss SessionStatus
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return nil
+	}
+	applySessionDefaults(&ss, harnessID, sessionID, worktreeDir)
+	return &ss
+}
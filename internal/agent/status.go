@@ -43,6 +43,11 @@ type SessionStatus struct {
 	TurnID         string    `json:"turn_id,omitempty"`
 	PermissionMode string    `json:"permission_mode,omitempty"`
 	Worktree       string    `json:"worktree,omitempty"`
+	// TranscriptPath, Usage, and CostUSD are refreshed from the harness
+	// transcript whenever a turn stops; Usage is cumulative per model.
+	TranscriptPath string        `json:"transcript_path,omitempty"`
+	Usage          harness.Usage `json:"usage,omitempty"`
+	CostUSD        float64       `json:"cost_usd,omitempty"`
 }
 
 func StatusDir() string {
@@ -225,7 +230,10 @@ type SessionFileInfo struct {
 	Session     SessionStatus
 }
 
+// RemoveSessionFileForSession archives and removes a session that ended
+// without reporting SessionEnd, e.g. because its tmux session died.
 func RemoveSessionFileForSession(repoName, worktreeDir string, session SessionStatus) error {
+	_ = archiveSession(repoName, worktreeDir, session.Harness, session.SessionID, time.Now())
 	return removeSessionArtifacts(repoName, worktreeDir, session.Harness, session.SessionID)
 }
 
@@ -248,6 +256,9 @@ func sessionArtifactPaths(repoName, worktreeDir, harnessID, sessionID string) []
 		SessionPath(repoName, worktreeDir, harnessID, sessionID),
 		FilesPathForHarness(repoName, worktreeDir, harnessID, sessionID),
 		TimelinePathForHarness(repoName, worktreeDir, harnessID, sessionID),
+		ToolsPathForHarness(repoName, worktreeDir, harnessID, sessionID),
+		PendingPathForHarness(repoName, worktreeDir, harnessID, sessionID),
+		filepath.Join(SessionDir(repoName, worktreeDir, harnessID), sessionID+".lock"),
 	}
 }
 
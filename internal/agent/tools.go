@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+)
+
+// ToolEntry is one tool call recorded by the hook handler.
+type ToolEntry struct {
+	Tool string    `json:"tool"`
+	Time time.Time `json:"t"`
+}
+
+func ToolsPathForHarness(repoName, worktreeDir, harnessID, sessionID string) string {
+	return filepath.Join(SessionDir(repoName, worktreeDir, harnessID), sessionID+".tools")
+}
+
+// ToolsPath returns the path to the tool-call JSONL file for a session.
+func ToolsPath(repoName, worktreeDir, sessionID string) string {
+	entries, err := os.ReadDir(StatusWorktreeDir(repoName, worktreeDir))
+	if err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := ToolsPathForHarness(repoName, worktreeDir, e.Name(), sessionID)
+			if fileExists(path) {
+				return path
+			}
+		}
+	}
+	return ToolsPathForHarness(repoName, worktreeDir, harness.ClaudeID, sessionID)
+}
+
+// ReadTools reads a session's tool calls made at or after since.
+func ReadTools(repoName, worktreeDir, sessionID string, since time.Time) ([]ToolEntry, error) {
+	f, err := os.Open(ToolsPath(repoName, worktreeDir, sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []ToolEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry ToolEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// LastTurnStart returns when the session last became BUSY, or the zero
+// time when its timeline has no BUSY entry.
+func LastTurnStart(timeline []TimelineEntry) time.Time {
+	for i := len(timeline) - 1; i >= 0; i-- {
+		if timeline[i].Status == StatusBusy {
+			return timeline[i].Time
+		}
+	}
+	return time.Time{}
+}
+
+// appendTool appends a tool call to the session's tool log. Best-effort.
+func appendTool(path, tool string, ts time.Time) {
+	entry, err := json.Marshal(ToolEntry{Tool: tool, Time: ts})
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(entry, '\n'))
+}
+
+// PendingPermission is the PendingTool kind for a permission prompt.
+const PendingPermission = "permission"
+
+// PendingTool is the tool call a session last asked to run, kept while the
+// agent may be waiting for permission so ww approve can show what it is
+// approving. Kind records what put the session in WAIT: PendingPermission
+// for a permission prompt, otherwise the hook event name (a question, a
+// plan, or a generic notification).
+type PendingTool struct {
+	Tool    string          `json:"tool,omitempty"`
+	Input   json.RawMessage `json:"input,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Kind    string          `json:"kind,omitempty"`
+	Since   time.Time       `json:"since"`
+}
+
+// IsPermission reports whether p records a permission prompt, the only
+// kind of wait that approve and deny keys are meant for.
+func (p *PendingTool) IsPermission() bool {
+	return p != nil && p.Kind == PendingPermission
+}
+
+func PendingPathForHarness(repoName, worktreeDir, harnessID, sessionID string) string {
+	return filepath.Join(SessionDir(repoName, worktreeDir, harnessID), sessionID+".pending")
+}
+
+// ReadPending returns the session's pending tool call, or nil.
+func ReadPending(repoName, worktreeDir, harnessID, sessionID string) *PendingTool {
+	data, err := os.ReadFile(PendingPathForHarness(repoName, worktreeDir, harnessID, sessionID))
+	if err != nil {
+		return nil
+	}
+	var p PendingTool
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+// Summary returns the tool name and its most telling argument, e.g.
+// "Bash: npm test".
+func (p *PendingTool) Summary() string {
+	tool := p.Tool
+	if tool == "" {
+		tool = "permission"
+	}
+	var args map[string]any
+	if json.Unmarshal(p.Input, &args) != nil {
+		return tool
+	}
+	for _, key := range []string{"command", "cmd", "file_path", "path", "url", "pattern", "query", "description"} {
+		if v, ok := args[key]; ok {
+			switch v := v.(type) {
+			case string:
+				return tool + ": " + v
+			case []any:
+				parts := make([]string, 0, len(v))
+				for _, s := range v {
+					parts = append(parts, fmt.Sprint(s))
+				}
+				return tool + ": " + strings.Join(parts, " ")
+			}
+		}
+	}
+	return tool
+}
+
+// updatePending records the tool a hook event is about to run, adds the
+// prompt message once the session waits, and clears the record when the
+// session moves on. Best-effort.
+func updatePending(path string, in harness.NormalizedHook, tool string, status Status, ts time.Time) {
+	kind := in.EventName
+	if in.PermissionPrompt || in.EventName == "PermissionRequest" {
+		kind = PendingPermission
+	}
+	var p PendingTool
+	switch {
+	case tool != "":
+		p = PendingTool{Tool: tool, Input: in.ToolInput, Since: ts}
+		if status == StatusWait {
+			p.Message = in.Message
+			p.Kind = kind
+		}
+	case status == StatusWait:
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, &p)
+		}
+		p.Message = in.Message
+		// A later notification doesn't clear a pending permission prompt.
+		if kind == PendingPermission || p.Kind == "" {
+			p.Kind = kind
+		}
+		if p.Since.IsZero() {
+			p.Since = ts
+		}
+	default:
+		_ = os.Remove(path)
+		return
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, append(data, '\n'), 0o644)
+}
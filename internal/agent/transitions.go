@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"syscall"
 )
 
@@ -68,6 +69,46 @@ func DetectTransitions(current map[string]Status, stateFile string) []Transition
 	return transitions
 }
 
+// WorktreeStatuses aggregates scanned sessions into the "repo/wtDir" → status
+// map that DetectTransitions consumes.
+func WorktreeStatuses(sessions []SessionFileInfo) map[string]Status {
+	grouped := make(map[string][]*SessionStatus)
+	for i := range sessions {
+		key := sessions[i].RepoName + "/" + sessions[i].WorktreeDir
+		grouped[key] = append(grouped[key], &sessions[i].Session)
+	}
+	statuses := make(map[string]Status, len(grouped))
+	for key, group := range grouped {
+		statuses[key] = AggregateStatus(group).Status
+	}
+	return statuses
+}
+
+// DiffStatuses returns every status change between prev and current, sorted
+// by key. Unlike DetectTransitions it keeps no state file and reports all
+// changes, not just BUSY→non-BUSY; keys missing from current go offline.
+func DiffStatuses(prev, current map[string]Status) []Transition {
+	var transitions []Transition
+	for key, status := range current {
+		from, ok := prev[key]
+		if !ok {
+			from = StatusOffline
+		}
+		if from != status {
+			transitions = append(transitions, Transition{Key: key, FromStatus: from, ToStatus: status})
+		}
+	}
+	for key, status := range prev {
+		if _, ok := current[key]; !ok && status != StatusOffline {
+			transitions = append(transitions, Transition{Key: key, FromStatus: status, ToStatus: StatusOffline})
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].Key < transitions[j].Key
+	})
+	return transitions
+}
+
 // TmuxStateFile returns the path to the tmux status bar's state file.
 func TmuxStateFile() string {
 	return filepath.Join(StatusDir(), "..", "tmux-states.json")
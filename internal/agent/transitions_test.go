@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 )
 
 // TestDetectTransitions_PreservesSiblingKeys ensures that when a single-hook
@@ -89,3 +90,45 @@ func TestFireNotifications_SingleTransitionUnderLock(t *testing.T) {
 		t.Errorf("observed %d transitions across racing goroutines, want exactly 1", count)
 	}
 }
+
+func TestDiffStatuses(t *testing.T) {
+	prev := map[string]Status{
+		"repo/a": StatusBusy,
+		"repo/b": StatusWait,
+		"repo/c": StatusDone,
+	}
+	current := map[string]Status{
+		"repo/a": StatusDone,
+		"repo/b": StatusWait,
+		"repo/d": StatusBusy,
+	}
+
+	got := DiffStatuses(prev, current)
+	want := []Transition{
+		{Key: "repo/a", FromStatus: StatusBusy, ToStatus: StatusDone},
+		{Key: "repo/c", FromStatus: StatusDone, ToStatus: StatusOffline},
+		{Key: "repo/d", FromStatus: StatusOffline, ToStatus: StatusBusy},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DiffStatuses() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("DiffStatuses()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWorktreeStatusesAggregatesPerWorktree(t *testing.T) {
+	now := time.Now()
+	sessions := []SessionFileInfo{
+		{RepoName: "repo", WorktreeDir: "a", Session: SessionStatus{SessionID: "1", Status: StatusDone, Timestamp: now}},
+		{RepoName: "repo", WorktreeDir: "a", Session: SessionStatus{SessionID: "2", Status: StatusBusy, Timestamp: now}},
+		{RepoName: "repo", WorktreeDir: "b", Session: SessionStatus{SessionID: "3", Status: StatusWait, Timestamp: now}},
+	}
+
+	got := WorktreeStatuses(sessions)
+	if got["repo/a"] != StatusBusy || got["repo/b"] != StatusWait || len(got) != 2 {
+		t.Fatalf("WorktreeStatuses() = %v", got)
+	}
+}
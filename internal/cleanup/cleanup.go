@@ -18,6 +18,12 @@ type Reason string
 const (
 	ReasonMergedPR     Reason = "merged-pr"
 	ReasonGoneUpstream Reason = "gone-upstream"
+
+	// Retention-policy reasons, enabled through the gc config.
+	ReasonInactive      Reason = "inactive"
+	ReasonStaleDetached Reason = "stale-detached"
+	ReasonTrashAge      Reason = "trash-age"
+	ReasonTrashQuota    Reason = "trash-quota"
 )
 
 type Candidate struct {
@@ -27,6 +33,7 @@ type Candidate struct {
 	Head            string
 	Path            string
 	WtDirName       string
+	Detached        bool
 	ExpectedBaseRef string
 	Reasons         []Reason
 }
@@ -56,8 +63,8 @@ func ScanRepo(repoName, bareDir string, opts ScanOptions) ([]Candidate, error) {
 }
 
 func CandidatesForWorktrees(repoName, bareDir string, wts []worktree.Worktree, opts ScanOptions) ([]Candidate, error) {
-	repoGit := &git.Git{Dir: bareDir, Verbose: opts.Verbose}
 	cfg := config.Load(bareDir)
+	repoGit := &git.Git{Dir: bareDir, Verbose: opts.Verbose, Remote: cfg.Upstream()}
 	st := stack.Load(bareDir)
 	baseBranch := repoGit.ResolveBaseBranch(cfg.BaseBranch)
 
@@ -78,25 +85,30 @@ func CandidatesForWorktrees(repoName, bareDir string, wts []worktree.Worktree, o
 			branchBases[wt.Branch] = parent
 		}
 	}
-	if len(branchNames) == 0 {
+	if len(branchNames) == 0 && cfg.GC.DetachedDays <= 0 {
 		return nil, nil
 	}
 
 	var mergedSet map[string]bool
-	if opts.RefreshPRState {
-		mergedSet = gh.MergedWorktreeSet(repoDir, baseBranch, branchHeads, branchBases)
-	} else {
-		mergedSet = gh.CachedMergedWorktreeSet(repoDir, baseBranch, branchHeads, branchBases)
-	}
+	upstreams := map[string]UpstreamStatus{}
+	if len(branchNames) > 0 {
+		if opts.RefreshPRState {
+			mergedSet = gh.MergedWorktreeSet(repoDir, baseBranch, branchHeads, branchBases)
+		} else {
+			mergedSet = gh.CachedMergedWorktreeSet(repoDir, baseBranch, branchHeads, branchBases)
+		}
 
-	upstreams, err := UpstreamStatuses(repoGit, branchNames)
-	if err != nil {
-		return nil, err
+		var err error
+		upstreams, err = UpstreamStatuses(repoGit, branchNames)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	now := policyNow()
 	var candidates []Candidate
 	for _, wt := range wts {
-		if wt.IsBare || wt.Detached || wt.Branch == "" {
+		if wt.IsBare || (!wt.Detached && wt.Branch == "") {
 			continue
 		}
 		var reasons []Reason
@@ -106,26 +118,33 @@ func CandidatesForWorktrees(repoName, bareDir string, wts []worktree.Worktree, o
 		if upstreams[wt.Branch].Gone {
 			reasons = append(reasons, ReasonGoneUpstream)
 		}
+		if reason := policyReason(repoName, wt, cfg.GC, baseBranch, now); reason != "" {
+			reasons = append(reasons, reason)
+		}
 		if len(reasons) == 0 {
 			continue
 		}
-		candidates = append(candidates, Candidate{
-			RepoName:        repoName,
-			BareDir:         bareDir,
-			Branch:          wt.Branch,
-			Head:            wt.Head,
-			Path:            wt.Path,
-			WtDirName:       filepath.Base(wt.Path),
-			ExpectedBaseRef: ExpectedBaseRef(repoGit, st, baseBranch, wt.Branch),
-			Reasons:         reasons,
-		})
+		candidate := Candidate{
+			RepoName:  repoName,
+			BareDir:   bareDir,
+			Branch:    wt.Branch,
+			Head:      wt.Head,
+			Path:      wt.Path,
+			WtDirName: filepath.Base(wt.Path),
+			Detached:  wt.Detached,
+			Reasons:   reasons,
+		}
+		if !wt.Detached {
+			candidate.ExpectedBaseRef = ExpectedBaseRef(repoGit, st, baseBranch, wt.Branch)
+		}
+		candidates = append(candidates, candidate)
 	}
 
 	sort.SliceStable(candidates, func(i, j int) bool {
 		if candidates[i].RepoName != candidates[j].RepoName {
 			return candidates[i].RepoName < candidates[j].RepoName
 		}
-		return candidates[i].Branch < candidates[j].Branch
+		return candidates[i].Name() < candidates[j].Name()
 	})
 	return candidates, nil
 }
@@ -175,17 +194,27 @@ func ExpectedBaseRef(repoGit *git.Git, st *stack.Stack, baseBranch, branch strin
 			if st.IsTracked(parent) && repoGit.LocalBranchExists(parent) {
 				return "refs/heads/" + parent
 			}
-			return "refs/remotes/origin/" + parent
+			return "refs/remotes/" + repoGit.RemoteRef(parent)
 		}
 	}
 	if baseBranch == "" {
 		return ""
 	}
-	return "refs/remotes/origin/" + baseBranch
+	return "refs/remotes/" + repoGit.RemoteRef(baseBranch)
 }
 
 func FilterSafe(candidates []Candidate) ([]Candidate, []Skip, error) {
-	stacks := make(map[string]*stack.Stack)
+	return FilterSafeWithStacks(candidates, nil)
+}
+
+// FilterSafeWithStacks is FilterSafe judged against the given stacks, keyed
+// by bare dir, so callers can check candidates against a planned reparent
+// before it is applied. Repos missing from planned use their saved stack.
+func FilterSafeWithStacks(candidates []Candidate, planned map[string]*stack.Stack) ([]Candidate, []Skip, error) {
+	stacks := make(map[string]*stack.Stack, len(planned))
+	for bareDir, st := range planned {
+		stacks[bareDir] = st
+	}
 	var safe []Candidate
 	var skipped []Skip
 
@@ -220,6 +249,10 @@ func SkipReason(candidate Candidate, st *stack.Stack) (string, error) {
 		return "", err
 	}
 
+	if !candidate.HasReason(ReasonMergedPR) && !candidate.HasReason(ReasonGoneUpstream) {
+		return policySkipReason(candidate, wtGit, children, dirty)
+	}
+
 	reachable := true
 	if !candidate.HasReason(ReasonMergedPR) {
 		reachable = false
@@ -286,11 +319,20 @@ func ReasonsString(reasons []Reason) string {
 	return strings.Join(parts, ", ")
 }
 
+// Name returns the name ww rm accepts for the candidate: its branch, or the
+// worktree directory when HEAD is detached.
+func (c Candidate) Name() string {
+	if c.Detached {
+		return c.WtDirName
+	}
+	return c.Branch
+}
+
 func Label(candidate Candidate, multiRepo bool) string {
 	if multiRepo {
-		return candidate.RepoName + "/" + candidate.Branch
+		return candidate.RepoName + "/" + candidate.Name()
 	}
-	return candidate.Branch
+	return candidate.Name()
 }
 
 func HasMultipleRepos(candidates []Candidate) bool {
@@ -0,0 +1,89 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/worktree"
+)
+
+var policyNow = time.Now
+
+const day = 24 * time.Hour
+
+// policyReason returns the retention-policy reason wt violates, or "" when
+// it is within policy. The base branch worktree is never inactive.
+func policyReason(repoName string, wt worktree.Worktree, policy config.GCConfig, baseBranch string, now time.Time) Reason {
+	days, reason := policy.InactiveDays, ReasonInactive
+	if wt.Detached {
+		days, reason = policy.DetachedDays, ReasonStaleDetached
+	} else if wt.Branch == baseBranch {
+		return ""
+	}
+	if days <= 0 {
+		return ""
+	}
+	last := LastActivity(repoName, wt.Path)
+	if last.IsZero() || now.Sub(last) < time.Duration(days)*day {
+		return ""
+	}
+	return reason
+}
+
+// LastActivity returns the latest of the worktree's HEAD commit, the last
+// write to its index (checkouts, staging, creation), and its agent
+// sessions' last updates. It is zero when none can be read.
+func LastActivity(repoName, wtPath string) time.Time {
+	var last time.Time
+	bump := func(t time.Time) {
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	wtGit := &git.Git{Dir: wtPath}
+	if out, err := wtGit.Run("log", "-1", "--format=%ct", "HEAD"); err == nil {
+		if secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64); err == nil {
+			bump(time.Unix(secs, 0))
+		}
+	}
+	if gitDir, err := wtGit.Run("rev-parse", "--absolute-git-dir"); err == nil {
+		if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+			bump(info.ModTime())
+		}
+	}
+	for _, ss := range agent.ReadAllSessions(repoName, filepath.Base(wtPath)) {
+		bump(ss.Timestamp)
+	}
+	return last
+}
+
+// policySkipReason applies the safeguards for candidates flagged only by a
+// retention policy. Their branches are usually not merged, so instead of
+// checking reachability from the base branch, every commit must exist on a
+// remote.
+func policySkipReason(candidate Candidate, wtGit *git.Git, children []string, dirty bool) (string, error) {
+	pushed := true
+	reason := "unpushed commits"
+	if candidate.Detached {
+		out, err := wtGit.Run("branch", "-r", "--contains", "HEAD", "--format=%(refname:short)")
+		if err != nil {
+			return "", err
+		}
+		pushed = strings.TrimSpace(out) != ""
+		reason = "commits not on any remote"
+	} else {
+		unpushed, err := wtGit.HasUnpushedCommits()
+		if err != nil {
+			return "", err
+		}
+		pushed = !unpushed
+	}
+	return skipReasonFromState(children, dirty, pushed, reason), nil
+}
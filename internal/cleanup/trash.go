@@ -0,0 +1,170 @@
+package cleanup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+// Layout of a trash entry written by ww rm. Entries trashed by older
+// versions are a bare worktree directory with no manifest.
+const (
+	TrashManifestFile = "manifest.json"
+	TrashWorktreeDir  = "worktree"
+	TrashGitDir       = "gitdir"
+	TrashStatusDir    = "status"
+)
+
+// TrashManifest records where a trashed worktree came from, so ww restore
+// can put it back.
+type TrashManifest struct {
+	Repo        string    `json:"repo"`
+	BareDir     string    `json:"bareDir"`
+	Path        string    `json:"path"`
+	Branch      string    `json:"branch,omitempty"`
+	Head        string    `json:"head"`
+	Detached    bool      `json:"detached,omitempty"`
+	StackParent string    `json:"stackParent,omitempty"`
+	RemovedAt   time.Time `json:"removedAt"`
+}
+
+// Name returns the branch, or the worktree directory when HEAD was
+// detached.
+func (m *TrashManifest) Name() string {
+	if m.Detached || m.Branch == "" {
+		return filepath.Base(m.Path)
+	}
+	return m.Branch
+}
+
+// WriteTrashManifest writes m into the trash entry at dir.
+func WriteTrashManifest(dir string, m *TrashManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, TrashManifestFile), append(data, '\n'), 0o644)
+}
+
+func readTrashManifest(dir string) *TrashManifest {
+	data, err := os.ReadFile(filepath.Join(dir, TrashManifestFile))
+	if err != nil {
+		return nil
+	}
+	var m TrashManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return &m
+}
+
+// TrashEntry is a removed worktree waiting in the trash dir. Manifest is
+// nil for entries that can't be restored.
+type TrashEntry struct {
+	Name     string
+	Path     string
+	Trashed  time.Time
+	Size     int64
+	Manifest *TrashManifest
+	Reasons  []Reason
+}
+
+// ScanTrash lists the entries in dir, oldest first. A missing dir has no
+// entries.
+func ScanTrash(dir string) ([]TrashEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash dir: %w", err)
+	}
+
+	entries := make([]TrashEntry, 0, len(dirEntries))
+	for _, e := range dirEntries {
+		path := filepath.Join(dir, e.Name())
+		entry := TrashEntry{
+			Name:    e.Name(),
+			Path:    path,
+			Trashed: trashedAt(e),
+			Size:    dirSize(path),
+		}
+		if e.IsDir() {
+			entry.Manifest = readTrashManifest(path)
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Trashed.Before(entries[j].Trashed)
+	})
+	return entries, nil
+}
+
+// trashedAt reads the removal time from the <unixnano>- prefix ww rm gives
+// trash entries, falling back to the entry's mtime.
+func trashedAt(e fs.DirEntry) time.Time {
+	if prefix, _, ok := strings.Cut(e.Name(), "-"); ok {
+		if nanos, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+			return time.Unix(0, nanos)
+		}
+	}
+	if info, err := e.Info(); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// ExpiredTrash returns the entries that violate policy, with their reasons:
+// those older than TrashDays, then the oldest of the rest until the trash
+// fits in TrashMaxMB. entries must be sorted oldest first.
+func ExpiredTrash(entries []TrashEntry, policy config.GCConfig, now time.Time) []TrashEntry {
+	var expired []TrashEntry
+	var kept []TrashEntry
+	var total int64
+	for _, e := range entries {
+		if policy.TrashDays > 0 && now.Sub(e.Trashed) >= time.Duration(policy.TrashDays)*day {
+			e.Reasons = []Reason{ReasonTrashAge}
+			expired = append(expired, e)
+			continue
+		}
+		kept = append(kept, e)
+		total += e.Size
+	}
+
+	if policy.TrashMaxMB > 0 {
+		budget := int64(policy.TrashMaxMB) << 20
+		for _, e := range kept {
+			if total <= budget {
+				break
+			}
+			e.Reasons = []Reason{ReasonTrashQuota}
+			expired = append(expired, e)
+			total -= e.Size
+		}
+	}
+	return expired
+}
@@ -0,0 +1,73 @@
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+func TestScanTrashOrdersByRemovalTime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for name, size := range map[string]int{
+		fmt.Sprintf("%d-newer", now.UnixNano()):                 10,
+		fmt.Sprintf("%d-older", now.Add(-time.Hour).UnixNano()): 20,
+		"unprefixed": 5,
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name, "sub"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "sub", "f"), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := now.Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "unprefixed"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ScanTrash(dir)
+	if err != nil {
+		t.Fatalf("ScanTrash: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].Name != "unprefixed" || entries[2].Size != 10 || entries[1].Size != 20 {
+		t.Errorf("entries = %+v, want unprefixed, older (20 bytes), newer (10 bytes)", entries)
+	}
+
+	if entries, err := ScanTrash(filepath.Join(dir, "missing")); err != nil || entries != nil {
+		t.Errorf("ScanTrash(missing) = %v, %v; want nil, nil", entries, err)
+	}
+}
+
+func TestExpiredTrashAppliesAgeThenQuota(t *testing.T) {
+	now := time.Now()
+	const mb = 1 << 20
+	entries := []TrashEntry{
+		{Name: "ancient", Trashed: now.AddDate(0, 0, -30), Size: 3 * mb},
+		{Name: "week", Trashed: now.AddDate(0, 0, -6), Size: 2 * mb},
+		{Name: "yesterday", Trashed: now.AddDate(0, 0, -1), Size: 2 * mb},
+		{Name: "today", Trashed: now, Size: 1 * mb},
+	}
+
+	expired := ExpiredTrash(entries, config.GCConfig{TrashDays: 14, TrashMaxMB: 4}, now)
+	if len(expired) != 2 {
+		t.Fatalf("expired = %+v, want ancient and week", expired)
+	}
+	if expired[0].Name != "ancient" || expired[0].Reasons[0] != ReasonTrashAge {
+		t.Errorf("expired[0] = %+v, want ancient (trash-age)", expired[0])
+	}
+	if expired[1].Name != "week" || expired[1].Reasons[0] != ReasonTrashQuota {
+		t.Errorf("expired[1] = %+v, want week (trash-quota)", expired[1])
+	}
+
+	if expired := ExpiredTrash(entries, config.GCConfig{TrashDays: 60}, now); len(expired) != 0 {
+		t.Errorf("expired = %+v, want none within policy", expired)
+	}
+}
@@ -0,0 +1,393 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	ierrors "github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/worktree"
+	"github.com/urfave/cli/v3"
+)
+
+type adoptWorktree struct {
+	Branch  string
+	Head    string
+	OldPath string
+	NewPath string
+}
+
+func (wt adoptWorktree) label() string {
+	if wt.Branch == "" {
+		return fmt.Sprintf("(detached %s)", worktree.ShortHead(wt.Head))
+	}
+	return wt.Branch
+}
+
+type adoptPlan struct {
+	Name         string
+	SourceDir    string
+	GitDir       string
+	BareDir      string
+	WorktreesDir string
+	Main         adoptWorktree
+	Linked       []adoptWorktree
+	Link         bool
+	HadIndex     bool
+}
+
+func adoptCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "adopt",
+		Usage: "Convert an existing clone into a willow-managed repo",
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "path",
+				UsageText: "<path>",
+			},
+			&cli.StringArg{
+				Name:      "name",
+				UsageText: "[name]",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "link",
+				Usage: "Leave a symlink at the original path pointing to the adopted worktree",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the planned moves without changing anything",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Skip confirmation",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.adopt")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			source := cmd.StringArg("path")
+			if source == "" {
+				return ierrors.Userf("path is required\n\nUsage: ww adopt <path> [name]")
+			}
+
+			plan, err := buildAdoptPlan(source, cmd.StringArg("name"), cmd.Bool("link"), flags.Verbose)
+			if err != nil {
+				return err
+			}
+
+			printAdoptPlan(u, plan)
+			if cmd.Bool("dry-run") {
+				return nil
+			}
+
+			if !cmd.Bool("yes") && !u.Confirm(fmt.Sprintf("Adopt %s as %s?", plan.SourceDir, plan.Name)) {
+				u.Info("Adopt cancelled.")
+				return nil
+			}
+
+			if err := executeAdoptPlan(plan, flags.Verbose); err != nil {
+				return err
+			}
+
+			u.Success(fmt.Sprintf("Adopted %s", u.Bold(plan.Name)))
+			u.Info(fmt.Sprintf("  bare repo:  %s", u.Dim(plan.BareDir)))
+			u.Info(fmt.Sprintf("  worktree:   %s", u.Dim(plan.Main.NewPath)))
+			if cwd, err := os.Getwd(); err != nil || pathWithin(plan.SourceDir, cwd) {
+				u.Info(fmt.Sprintf("\nYour shell is still in the old checkout. Run: cd %s", plan.Main.NewPath))
+			}
+			return nil
+		},
+	}
+}
+
+func buildAdoptPlan(source, name string, link, verbose bool) (*adoptPlan, error) {
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	source = comparablePath(source)
+
+	gitDir := filepath.Join(source, ".git")
+	info, err := os.Stat(gitDir)
+	switch {
+	case os.IsNotExist(err):
+		return nil, ierrors.Userf("%s is not a git checkout", source)
+	case err != nil:
+		return nil, err
+	case !info.IsDir():
+		return nil, ierrors.Userf("%s is a linked worktree; adopt its main checkout instead", source)
+	}
+
+	if name == "" {
+		name = filepath.Base(source)
+	}
+	plan := &adoptPlan{
+		Name:         name,
+		SourceDir:    source,
+		GitDir:       gitDir,
+		BareDir:      filepath.Join(config.ReposDir(), name+".git"),
+		WorktreesDir: filepath.Join(config.WorktreesDir(), name),
+		Link:         link,
+		HadIndex:     pathExists(filepath.Join(gitDir, "index")),
+	}
+
+	if pathExists(plan.BareDir) {
+		return nil, ierrors.Userf("repository %q already exists at %s", name, plan.BareDir)
+	}
+	if pathExists(plan.WorktreesDir) {
+		if empty, err := dirEmpty(plan.WorktreesDir); err != nil || !empty {
+			return nil, ierrors.Userf("worktree directory already exists and is not empty: %s", plan.WorktreesDir)
+		}
+	}
+	if pathsOverlap(source, config.WillowHome()) {
+		return nil, ierrors.Userf("%s overlaps the willow base %s", source, config.WillowHome())
+	}
+
+	for _, marker := range []string{"MERGE_HEAD", "CHERRY_PICK_HEAD", "REVERT_HEAD", "BISECT_LOG", "rebase-merge", "rebase-apply"} {
+		if pathExists(filepath.Join(gitDir, marker)) {
+			return nil, ierrors.Userf("%s has a merge, rebase, cherry-pick, or bisect in progress; finish or abort it first", source)
+		}
+	}
+	if pathExists(filepath.Join(gitDir, "modules")) {
+		return nil, ierrors.Userf("%s has submodules, which ww adopt does not support yet", source)
+	}
+
+	srcGit := &git.Git{Dir: source, Verbose: verbose}
+	head, err := srcGit.RevParse("HEAD")
+	if err != nil {
+		return nil, ierrors.Userf("%s has no commits to adopt", source)
+	}
+	plan.Main = adoptWorktree{Head: head, OldPath: source}
+	dirName := "detached-" + worktree.ShortHead(head)
+	if branch, err := srcGit.Run("symbolic-ref", "-q", "--short", "HEAD"); err == nil {
+		plan.Main.Branch = branch
+		dirName = worktreeDirName(branch)
+	}
+	plan.Main.NewPath = filepath.Join(plan.WorktreesDir, dirName)
+
+	wts, err := worktree.List(srcGit)
+	if err != nil {
+		return nil, fmt.Errorf("list worktrees: %w", err)
+	}
+	used := map[string]string{dirName: plan.Main.label()}
+	for _, wt := range wts {
+		if wt.IsBare || comparablePath(wt.Path) == source {
+			continue
+		}
+		if !pathExists(wt.Path) {
+			return nil, ierrors.Userf("worktree %s no longer exists; run git worktree prune first", wt.Path)
+		}
+		linked := adoptWorktree{Branch: wt.Branch, Head: wt.Head, OldPath: wt.Path}
+		dir := worktreeDirName(wt.Branch)
+		if wt.Detached {
+			linked.Branch = ""
+			dir = worktreeDirName(filepath.Base(wt.Path))
+		}
+		if other, ok := used[dir]; ok {
+			return nil, ierrors.Userf("worktrees %s and %s would both move to %s", other, linked.label(), dir)
+		}
+		used[dir] = linked.label()
+		linked.NewPath = filepath.Join(plan.WorktreesDir, dir)
+		plan.Linked = append(plan.Linked, linked)
+	}
+	return plan, nil
+}
+
+func printAdoptPlan(u interface{ Info(string) }, plan *adoptPlan) {
+	u.Info(fmt.Sprintf("Checkout:  %s", plan.SourceDir))
+	u.Info(fmt.Sprintf("Repo:      %s", plan.Name))
+	u.Info(fmt.Sprintf("Bare repo: %s", plan.BareDir))
+	u.Info("")
+	for _, wt := range append([]adoptWorktree{plan.Main}, plan.Linked...) {
+		u.Info(fmt.Sprintf("  %s", wt.label()))
+		u.Info(fmt.Sprintf("    from: %s", wt.OldPath))
+		u.Info(fmt.Sprintf("    to:   %s", wt.NewPath))
+	}
+	if plan.Link {
+		u.Info("")
+		u.Info(fmt.Sprintf("%s will become a symlink to %s", plan.SourceDir, plan.Main.NewPath))
+	}
+}
+
+// executeAdoptPlan converts the checkout in place: linked worktrees move
+// under worktrees/<name>/, the .git directory becomes repos/<name>.git, and
+// the main checkout becomes an ordinary linked worktree that keeps its
+// index. Branches, stashes, config, and hooks all live in the git dir and
+// move with it. Any failure undoes the completed steps in reverse.
+func executeAdoptPlan(plan *adoptPlan, verbose bool) (err error) {
+	var undo []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		var failures []string
+		for i := len(undo) - 1; i >= 0; i-- {
+			if uerr := undo[i](); uerr != nil {
+				failures = append(failures, uerr.Error())
+			}
+		}
+		if len(failures) > 0 {
+			err = fmt.Errorf("%w\n\nrollback was incomplete; %s may need manual repair:\n%s",
+				err, plan.SourceDir, strings.Join(prefixLines(failures, "  "), "\n"))
+		}
+	}()
+
+	srcGit := &git.Git{Dir: plan.SourceDir, Verbose: verbose}
+	bareGit := &git.Git{Dir: plan.BareDir, Verbose: verbose}
+
+	createdWorktreesDir := !pathExists(plan.WorktreesDir)
+	if err := os.MkdirAll(plan.WorktreesDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+	if createdWorktreesDir {
+		undo = append(undo, func() error { return os.Remove(plan.WorktreesDir) })
+	}
+
+	for _, wt := range plan.Linked {
+		if _, err := srcGit.Run("worktree", "move", wt.OldPath, wt.NewPath); err != nil {
+			return fmt.Errorf("failed to move worktree %s: %w", wt.OldPath, err)
+		}
+		undo = append(undo, func() error {
+			_, err := srcGit.Run("worktree", "move", wt.NewPath, wt.OldPath)
+			return err
+		})
+	}
+
+	linkedPaths := make([]string, 0, len(plan.Linked))
+	for _, wt := range plan.Linked {
+		linkedPaths = append(linkedPaths, wt.NewPath)
+	}
+	if err := moveDir(plan.GitDir, plan.BareDir); err != nil {
+		return fmt.Errorf("failed to move %s: %w", plan.GitDir, err)
+	}
+	undo = append(undo, func() error {
+		if err := moveDir(plan.BareDir, plan.GitDir); err != nil {
+			return err
+		}
+		if len(linkedPaths) == 0 {
+			return nil
+		}
+		_, err := srcGit.Run(append([]string{"worktree", "repair"}, linkedPaths...)...)
+		return err
+	})
+
+	if _, err := bareGit.Run("config", "--bool", "core.bare", "true"); err != nil {
+		return fmt.Errorf("failed to mark repo bare: %w", err)
+	}
+	undo = append(undo, func() error {
+		_, err := bareGit.Run("config", "--bool", "core.bare", "false")
+		return err
+	})
+	if len(linkedPaths) > 0 {
+		if _, err := bareGit.Run(append([]string{"worktree", "repair"}, linkedPaths...)...); err != nil {
+			return fmt.Errorf("failed to repair worktrees: %w", err)
+		}
+	}
+
+	// Register the main checkout as a linked worktree. git refuses to add
+	// into a non-empty directory, so add into an empty one of the same name
+	// and move its .git file into the checkout.
+	tmpParent, err := os.MkdirTemp("", "willow-adopt-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpParent)
+	tmpPath := filepath.Join(tmpParent, filepath.Base(plan.Main.NewPath))
+	addArgs := []string{"worktree", "add", "--no-checkout", tmpPath, plan.Main.Branch}
+	if plan.Main.Branch == "" {
+		addArgs = []string{"worktree", "add", "--no-checkout", "--detach", tmpPath, plan.Main.Head}
+	}
+	if _, err := bareGit.Run(addArgs...); err != nil {
+		return fmt.Errorf("failed to register worktree: %w", err)
+	}
+	adminDir, err := worktreeAdminDir(tmpPath)
+	if err != nil {
+		return err
+	}
+	undo = append(undo, func() error { return os.RemoveAll(adminDir) })
+
+	if plan.HadIndex {
+		if err := os.Rename(filepath.Join(plan.BareDir, "index"), filepath.Join(adminDir, "index")); err != nil {
+			return fmt.Errorf("failed to keep the index: %w", err)
+		}
+		undo = append(undo, func() error {
+			return os.Rename(filepath.Join(adminDir, "index"), filepath.Join(plan.BareDir, "index"))
+		})
+	}
+
+	dotGit := filepath.Join(plan.SourceDir, ".git")
+	if err := os.Rename(filepath.Join(tmpPath, ".git"), dotGit); err != nil {
+		return fmt.Errorf("failed to link checkout: %w", err)
+	}
+	undo = append(undo, func() error { return os.Remove(dotGit) })
+
+	if err := moveDir(plan.SourceDir, plan.Main.NewPath); err != nil {
+		return fmt.Errorf("failed to move %s: %w", plan.SourceDir, err)
+	}
+	undo = append(undo, func() error { return moveDir(plan.Main.NewPath, plan.SourceDir) })
+
+	if _, err := bareGit.Run("worktree", "repair", plan.Main.NewPath); err != nil {
+		return fmt.Errorf("failed to repair worktree: %w", err)
+	}
+	if !plan.HadIndex {
+		wtGit := &git.Git{Dir: plan.Main.NewPath, Verbose: verbose}
+		if _, err := wtGit.Run("read-tree", "HEAD"); err != nil {
+			return fmt.Errorf("failed to build index: %w", err)
+		}
+	}
+
+	if plan.Link {
+		if err := os.Symlink(plan.Main.NewPath, plan.SourceDir); err != nil {
+			return fmt.Errorf("failed to link %s: %w", plan.SourceDir, err)
+		}
+		undo = append(undo, func() error { return os.Remove(plan.SourceDir) })
+	}
+
+	if failures := validateAdoptPlan(plan, verbose); len(failures) > 0 {
+		return fmt.Errorf("adopted repo failed validation:\n%s", strings.Join(prefixLines(failures, "  "), "\n"))
+	}
+	return nil
+}
+
+// worktreeAdminDir reads the gitdir a linked worktree's .git file points to.
+func worktreeAdminDir(wtPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(wtPath, ".git"))
+	if err != nil {
+		return "", err
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("unexpected .git file in %s", wtPath)
+	}
+	return dir, nil
+}
+
+func validateAdoptPlan(plan *adoptPlan, verbose bool) []string {
+	var failures []string
+	bareGit := &git.Git{Dir: plan.BareDir, Verbose: verbose}
+	if out, err := bareGit.Run("rev-parse", "--is-bare-repository"); err != nil || out != "true" {
+		failures = append(failures, fmt.Sprintf("%s is not a bare repository", plan.BareDir))
+	}
+	for _, wt := range append([]adoptWorktree{plan.Main}, plan.Linked...) {
+		wtGit := &git.Git{Dir: wt.NewPath, Verbose: verbose}
+		top, err := wtGit.Run("rev-parse", "--show-toplevel")
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("worktree %s: git rev-parse failed: %v", wt.NewPath, err))
+			continue
+		}
+		if comparablePath(top) != comparablePath(wt.NewPath) {
+			failures = append(failures, fmt.Sprintf("worktree %s: git top-level resolved to %s", wt.NewPath, top))
+		}
+	}
+	return failures
+}
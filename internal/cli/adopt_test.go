@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+// setupAdoptCheckout returns a non-bare clone with a linked worktree, an
+// extra local branch, a stash, and a staged change.
+func setupAdoptCheckout(t *testing.T) (string, string) {
+	t.Helper()
+	setupTestEnv(t)
+	home := os.Getenv("HOME")
+	checkout := filepath.Join(home, "workdir")
+	wg := &git.Git{Dir: checkout}
+
+	mainBranch, err := wg.Run("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		t.Fatalf("symbolic-ref: %v", err)
+	}
+	if _, err := wg.Run("branch", "local-only"); err != nil {
+		t.Fatalf("git branch: %v", err)
+	}
+	if _, err := wg.Run("worktree", "add", filepath.Join(home, "feature-wt"), "-b", "feature/login"); err != nil {
+		t.Fatalf("git worktree add: %v", err)
+	}
+
+	readme := filepath.Join(checkout, "README.md")
+	if err := os.WriteFile(readme, []byte("# stashed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wg.Run("stash"); err != nil {
+		t.Fatalf("git stash: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(checkout, "staged.txt"), []byte("staged\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wg.Run("add", "staged.txt"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	return checkout, mainBranch
+}
+
+func TestAdoptConvertsCheckoutIntoWillowLayout(t *testing.T) {
+	checkout, mainBranch := setupAdoptCheckout(t)
+
+	if err := runApp("adopt", checkout, "myrepo", "--yes", "--link"); err != nil {
+		t.Fatalf("adopt: %v", err)
+	}
+
+	bareDir := filepath.Join(config.ReposDir(), "myrepo.git")
+	bareGit := &git.Git{Dir: bareDir}
+	if out, err := bareGit.Run("rev-parse", "--is-bare-repository"); err != nil || out != "true" {
+		t.Fatalf("is-bare = %q, %v", out, err)
+	}
+	if !bareGit.LocalBranchExists("local-only") {
+		t.Error("local branch local-only was lost")
+	}
+
+	mainWt := filepath.Join(config.WorktreesDir(), "myrepo", mainBranch)
+	mainGit := &git.Git{Dir: mainWt}
+	if out, err := mainGit.Run("stash", "list"); err != nil || !strings.Contains(out, "stash@{0}") {
+		t.Errorf("stash was lost: %q, %v", out, err)
+	}
+	if out, err := mainGit.Run("diff", "--cached", "--name-only"); err != nil || out != "staged.txt" {
+		t.Errorf("staged changes = %q, %v; want staged.txt", out, err)
+	}
+	if out, err := mainGit.Run("status", "--porcelain"); err != nil || out != "A  staged.txt" {
+		t.Errorf("status = %q, %v", out, err)
+	}
+
+	featureWt := filepath.Join(config.WorktreesDir(), "myrepo", "feature-login")
+	featureGit := &git.Git{Dir: featureWt}
+	if branch, err := featureGit.Run("symbolic-ref", "--short", "HEAD"); err != nil || branch != "feature/login" {
+		t.Errorf("feature worktree branch = %q, %v", branch, err)
+	}
+	if pathExists(filepath.Join(os.Getenv("HOME"), "feature-wt")) {
+		t.Error("linked worktree was not moved")
+	}
+
+	target, err := os.Readlink(checkout)
+	if err != nil || target != mainWt {
+		t.Errorf("link = %q, %v; want %s", target, err, mainWt)
+	}
+	if !config.IsWillowRepo(bareDir) {
+		t.Error("adopted repo is not recognised as a willow repo")
+	}
+}
+
+func TestAdoptDryRunChangesNothing(t *testing.T) {
+	checkout, mainBranch := setupAdoptCheckout(t)
+
+	out, err := captureStdout(t, func() error {
+		return runApp("adopt", checkout, "--dry-run")
+	})
+	if err != nil {
+		t.Fatalf("adopt --dry-run: %v", err)
+	}
+	for _, want := range []string{
+		filepath.Join(config.ReposDir(), "workdir.git"),
+		filepath.Join(config.WorktreesDir(), "workdir", mainBranch),
+		filepath.Join(config.WorktreesDir(), "workdir", "feature-login"),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan missing %q:\n%s", want, out)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(checkout, ".git")); err != nil || !info.IsDir() {
+		t.Fatalf("checkout .git changed: %v", err)
+	}
+	if pathExists(config.ReposDir()) {
+		t.Error("dry run created the repos directory")
+	}
+}
+
+func TestAdoptRollsBackOnFailure(t *testing.T) {
+	checkout, _ := setupAdoptCheckout(t)
+	home := os.Getenv("HOME")
+	wg := &git.Git{Dir: checkout}
+	if _, err := wg.Run("worktree", "add", filepath.Join(home, "locked-wt"), "-b", "locked"); err != nil {
+		t.Fatalf("git worktree add: %v", err)
+	}
+	// git worktree move refuses locked worktrees, so this fails after the
+	// first linked worktree has already moved.
+	if _, err := wg.Run("worktree", "lock", filepath.Join(home, "locked-wt")); err != nil {
+		t.Fatalf("git worktree lock: %v", err)
+	}
+
+	if err := runApp("adopt", checkout, "--yes"); err == nil {
+		t.Fatal("adopt should fail on a locked worktree")
+	}
+
+	if info, err := os.Stat(filepath.Join(checkout, ".git")); err != nil || !info.IsDir() {
+		t.Fatalf("checkout .git not restored: %v", err)
+	}
+	featureGit := &git.Git{Dir: filepath.Join(home, "feature-wt")}
+	if branch, err := featureGit.Run("symbolic-ref", "--short", "HEAD"); err != nil || branch != "feature/login" {
+		t.Fatalf("feature worktree not restored: %q, %v", branch, err)
+	}
+	if pathExists(filepath.Join(config.WorktreesDir(), "workdir")) {
+		t.Error("rollback left the worktrees directory behind")
+	}
+	if out, err := wg.Run("diff", "--cached", "--name-only"); err != nil || out != "staged.txt" {
+		t.Errorf("staged changes after rollback = %q, %v", out, err)
+	}
+}
+
+func TestAdoptRejectsLinkedWorktreeAndExistingRepo(t *testing.T) {
+	checkout, _ := setupAdoptCheckout(t)
+
+	err := runApp("adopt", filepath.Join(os.Getenv("HOME"), "feature-wt"), "--yes")
+	if err == nil || !strings.Contains(err.Error(), "linked worktree") {
+		t.Fatalf("adopt linked worktree error = %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(config.ReposDir(), "workdir.git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err = runApp("adopt", checkout, "--yes")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("adopt existing repo error = %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/errors"
 	"github.com/iamrajjoshi/willow/internal/git"
@@ -124,6 +126,12 @@ func NewApp() *cli.Command {
 		Usage:                 "A simple, opinionated git worktree manager",
 		Version:               version,
 		EnableShellCompletion: true,
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			// Custom harnesses live in global config so every command,
+			// including hooks fired from outside a repo, sees the same set.
+			harness.RegisterCustom(config.Load(""))
+			return ctx, nil
+		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "C",
@@ -142,21 +150,36 @@ func NewApp() *cli.Command {
 		},
 		Commands: []*cli.Command{
 			cloneCmd(),
+			adoptCmd(),
 			newCmd(),
 			promoteCmd(),
 			renameCmd(),
 			checkoutCmd(),
 			syncCmd(),
 			prCmd(),
+			remoteCmd(),
 			swCmd(),
 			rmCmd(),
+			restoreCmd(),
+			trashCmd(),
 			lsCmd(),
 			statusCmd(),
 			dashboardCmd(),
+			serveCmd(),
+			notifyCmd(),
 			logCmd(),
+			sessionsCmd(),
+			statsCmd(),
+			usageCmd(),
 			dispatchCmd(),
+			execCmd(),
+			reviewCmd(),
+			tellCmd(),
+			approveCmd(),
+			denyCmd(),
 			tmuxCmd(),
 			agentCmd(),
+			setupHooksCmd(),
 			setupCmd(),
 			codexSetupCmd(),
 			cursorSetupCmd(),
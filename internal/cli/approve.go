@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/tmux"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+)
+
+func approveCmd() *cli.Command {
+	return permissionCmd("approve", "Approve the permission prompt a worktree's agent is waiting on", true)
+}
+
+func denyCmd() *cli.Command {
+	cmd := permissionCmd("deny", "Deny the permission prompt a worktree's agent is waiting on", false)
+	cmd.Flags = append(cmd.Flags, &cli.StringFlag{
+		Name:    "message",
+		Aliases: []string{"m"},
+		Usage:   "Tell the agent what to do instead",
+	})
+	return cmd
+}
+
+// permissionCmd builds ww approve and ww deny, which differ only in the
+// keys they send.
+func permissionCmd(name, usage string, approve bool) *cli.Command {
+	return &cli.Command{
+		Name:          name,
+		Usage:         usage,
+		UsageText:     "ww " + name + " [flags] [worktree]",
+		ShellComplete: completeWorktreesWithFlag,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "target",
+				UsageText: "[worktree]",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.StringFlag{
+				Name:  "session",
+				Usage: "Agent session ID or prefix, when the worktree has several",
+			},
+			&cli.StringFlag{
+				Name:  "pane",
+				Usage: "tmux pane ID (e.g. %3) to answer in instead of finding the agent's pane",
+			},
+			&cli.BoolFlag{
+				Name:  "show",
+				Usage: "Only show the pending tool call",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Answer even when the agent is not waiting on a permission prompt",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli."+name)()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			rwt, err := resolveWorktreeTarget(g, cmd.String("repo"), cmd.StringArg("target"))
+			if err != nil {
+				return err
+			}
+			pt, err := findPermissionTarget(rwt.Repo.Name, rwt.Worktree.Path, cmd.String("session"), cmd.String("pane"))
+			if err != nil {
+				return err
+			}
+			fmt.Print(formatPendingTool(u, pt))
+			if cmd.Bool("show") {
+				return nil
+			}
+			if !cmd.Bool("force") {
+				if err := requirePermissionPrompt(pt); err != nil {
+					return err
+				}
+			}
+
+			cfg := config.Load(rwt.Repo.BareDir)
+			if err := answerPermission(cfg, pt, approve, strings.TrimSpace(cmd.String("message"))); err != nil {
+				return err
+			}
+			logPermissionAnswer(name, rwt.Repo.Name, rwt.Worktree.MatchName(), pt)
+			verb := "Approved"
+			if !approve {
+				verb = "Denied"
+			}
+			u.Success(fmt.Sprintf("%s %s for %s in %s", verb, pendingSummary(pt.Pending), sessionLabel(pt.Session), u.Bold(rwt.Worktree.DisplayName())))
+			return nil
+		},
+	}
+}
+
+// permissionTarget is a WAIT session, its pane, and the tool call it is
+// waiting on, if the hook handler recorded one.
+type permissionTarget struct {
+	agentTarget
+	Pending *agent.PendingTool
+}
+
+func findPermissionTarget(repoName, wtPath, sessionID, paneID string) (*permissionTarget, error) {
+	at, err := findAgentTarget(repoName, wtPath, sessionID, paneID, []agent.Status{agent.StatusWait}, "It has no permission prompt to answer.")
+	if err != nil {
+		return nil, err
+	}
+	return &permissionTarget{
+		agentTarget: *at,
+		Pending:     agent.ReadPending(repoName, filepath.Base(wtPath), at.Session.Harness, at.Session.SessionID),
+	}, nil
+}
+
+// requirePermissionPrompt refuses to answer a WAIT that isn't a permission
+// prompt: the approve key would pick a question's first answer or accept a
+// plan.
+func requirePermissionPrompt(pt *permissionTarget) error {
+	if pt.Pending.IsPermission() {
+		return nil
+	}
+	return errors.Userf("%s is waiting on input, not a permission prompt; answer it in its pane, or pass --force", sessionLabel(pt.Session))
+}
+
+func pendingSummary(p *agent.PendingTool) string {
+	if p == nil {
+		return "the pending prompt"
+	}
+	return p.Summary()
+}
+
+// formatPendingTool describes what the agent is waiting on: the prompt
+// message, the tool, and its arguments as indented JSON.
+func formatPendingTool(u *ui.UI, pt *permissionTarget) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s\n", agent.StatusIcon(agent.StatusWait), u.Bold(sessionLabel(pt.Session)), u.Dim("in pane "+pt.Pane.ID))
+	p := pt.Pending
+	if p == nil {
+		b.WriteString(u.Dim("  No tool call recorded; the prompt is shown in the agent's pane.") + "\n")
+		return b.String()
+	}
+	if p.Message != "" {
+		fmt.Fprintf(&b, "  %s\n", p.Message)
+	}
+	if p.Tool != "" {
+		fmt.Fprintf(&b, "  %s %s\n", u.Yellow(p.Tool), u.Dim("("+agent.TimeSince(p.Since)+")"))
+	}
+	var indented bytes.Buffer
+	if len(p.Input) > 0 && json.Indent(&indented, p.Input, "  ", "  ") == nil {
+		fmt.Fprintf(&b, "  %s\n", indented.String())
+	}
+	return b.String()
+}
+
+// answerPermission sends the harness's approve or deny keys to the agent's
+// pane. A deny message is typed as a follow-up prompt once the prompt has
+// closed.
+func answerPermission(cfg *config.Config, pt *permissionTarget, approve bool, message string) error {
+	h, err := harness.MustGet(pt.Session.Harness)
+	if err != nil {
+		return err
+	}
+	keys := h.PermissionKeys(harness.OverridesFor(cfg, h.ID()))
+	send := keys.Approve
+	if !approve {
+		send = keys.Deny
+	}
+	if err := tmux.SendKeys(pt.Pane.ID, send...); err != nil {
+		return fmt.Errorf("failed to answer prompt: %w", err)
+	}
+	if approve || message == "" {
+		return nil
+	}
+	time.Sleep(tellSubmitDelay)
+	return sendTell(&pt.agentTarget, message)
+}
+
+func logPermissionAnswer(action, repoName, branch string, pt *permissionTarget) {
+	meta := map[string]string{"agent": pt.Session.Harness}
+	if pt.Pending != nil {
+		meta["tool"] = truncatePrompt(pt.Pending.Summary())
+	}
+	_ = log.Append(log.Event{Action: action, Repo: repoName, Branch: branch, Metadata: meta})
+}
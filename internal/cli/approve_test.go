@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestApproveAndDenyAnswerWaitingAgent(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "approverepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "approverepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "approverepo")
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+
+	binDir := t.TempDir()
+	tmuxLog := filepath.Join(t.TempDir(), "tmux.log")
+	writeTestExecutable(t, binDir, "tmux", "#!/bin/sh\n"+
+		"printf '%s\\n' \"$*\" >> "+shellQuote(tmuxLog)+"\n"+
+		"if [ \"$1\" = list-panes ]; then\n"+
+		"  printf '%%1\\tapproverepo/feature\\t"+featureDir+"\\tzsh\\n'\n"+
+		"  printf '%%2\\tapproverepo/feature\\t"+featureDir+"\\tclaude\\n'\n"+
+		"fi\n")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	prevDelay := tellSubmitDelay
+	tellSubmitDelay = 0
+	t.Cleanup(func() { tellSubmitDelay = prevDelay })
+
+	writeActiveSessionFile(t, "approverepo", "feature", "sess1", agent.StatusBusy)
+	if err := runApp("approve", "feature"); err == nil || !strings.Contains(err.Error(), "not WAIT") {
+		t.Fatalf("approve on busy agent error = %v", err)
+	}
+
+	writeActiveSessionFile(t, "approverepo", "feature", "sess1", agent.StatusWait)
+	pendingPath := agent.PendingPathForHarness("approverepo", "feature", "claude", "sess1")
+	question := `{"tool":"AskUserQuestion","input":{"questions":[]},"kind":"PreToolUse"}`
+	if err := os.WriteFile(pendingPath, []byte(question), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runApp("approve", "feature"); err == nil || !strings.Contains(err.Error(), "not a permission prompt") {
+		t.Fatalf("approve on a question error = %v", err)
+	}
+	if strings.Contains(readTestFile(t, tmuxLog), "send-keys") {
+		t.Fatal("approve should not answer a question without --force")
+	}
+
+	pending := `{"tool":"Bash","input":{"command":"npm publish"},"message":"Claude needs your permission to use Bash","kind":"permission"}`
+	if err := os.WriteFile(pendingPath, []byte(pending), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("approve", "feature", "--show") })
+	if err != nil {
+		t.Fatalf("approve --show: %v", err)
+	}
+	for _, want := range []string{"Claude needs your permission to use Bash", "Bash", `"command": "npm publish"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("approve --show output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(readTestFile(t, tmuxLog), "send-keys") {
+		t.Fatal("approve --show should not answer the prompt")
+	}
+
+	if err := runApp("approve", "feature"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if err := runApp("deny", "feature", "--message", "use the dry run instead"); err != nil {
+		t.Fatalf("deny: %v", err)
+	}
+	logText := readTestFile(t, tmuxLog)
+	for _, want := range []string{
+		"send-keys -t %2 Enter",
+		"send-keys -t %2 Escape",
+		"-- use the dry run instead",
+	} {
+		if !strings.Contains(logText, want) {
+			t.Errorf("tmux log missing %q:\n%s", want, logText)
+		}
+	}
+
+	logOut, err := captureStdout(t, func() error { return runApp("log", "--json") })
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if !strings.Contains(logOut, `"action": "approve"`) || !strings.Contains(logOut, "Bash: npm publish") {
+		t.Errorf("approve not in activity log:\n%s", logOut)
+	}
+
+	if err := os.WriteFile(pendingPath, []byte(question), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := strings.Count(readTestFile(t, tmuxLog), "send-keys -t %2 Enter")
+	if err := runApp("approve", "feature", "--force"); err != nil {
+		t.Fatalf("approve --force: %v", err)
+	}
+	if got := strings.Count(readTestFile(t, tmuxLog), "send-keys -t %2 Enter"); got != before+1 {
+		t.Errorf("approve --force should answer the question, Enter sent %d more times", got-before)
+	}
+}
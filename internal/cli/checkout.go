@@ -144,30 +144,30 @@ func checkoutCmd() *cli.Command {
 			if shouldFetch {
 				done = tr.StartCtx(ctx, "git fetch")
 				if cdOnly {
-					fmt.Fprintf(os.Stderr, "Fetching from origin...\n")
-					repoGit.RunStream(os.Stderr, "fetch", "--no-tags", "--progress", "origin")
+					fmt.Fprintf(os.Stderr, "Fetching from %s...\n", strings.Join(configRemotes(cfg), ", "))
+					repoGit.RunStream(os.Stderr, fetchRemotesArgs(cfg, "--no-tags", "--progress")...)
 				} else {
-					_ = u.Spin("Fetching from origin", func() error {
-						_, err := repoGit.Run("fetch", "--no-tags", "origin")
+					_ = u.Spin(fmt.Sprintf("Fetching from %s", strings.Join(configRemotes(cfg), ", ")), func() error {
+						_, err := repoGit.Run(fetchRemotesArgs(cfg, "--no-tags")...)
 						return err
 					})
 				}
 				done()
 			}
 
-			if repoGit.RemoteBranchExists(branch) {
+			if remoteWithBranch(repoGit, cfg, branch) != "" {
 				dirName := worktreeDirName(branch)
 				wtPath := filepath.Join(config.WorktreesDir(), repo.Name, dirName)
 
 				done = tr.StartCtx(ctx, "git worktree add (existing)")
 				if cdOnly {
 					fmt.Fprintf(os.Stderr, "Creating worktree for existing branch %s...\n", branch)
-					if _, err := repoGit.RunStream(os.Stderr, "worktree", "add", wtPath, branch); err != nil {
+					if err := addWorktree(repoGit, cfg, os.Stderr, wtPath, existingBranchArgs(repoGit, cfg, wtPath, branch)...); err != nil {
 						return fmt.Errorf("failed to create worktree: %w", err)
 					}
 				} else {
 					u.Info(fmt.Sprintf("Creating worktree for existing branch %s...", u.Bold(branch)))
-					if _, err := repoGit.Run("worktree", "add", wtPath, branch); err != nil {
+					if err := addWorktree(repoGit, cfg, nil, wtPath, existingBranchArgs(repoGit, cfg, wtPath, branch)...); err != nil {
 						return fmt.Errorf("failed to create worktree: %w", err)
 					}
 				}
@@ -194,7 +194,7 @@ func checkoutCmd() *cli.Command {
 			done()
 
 			localBase := repoGit.LocalBranchExists(baseBranch)
-			gitRef := "origin/" + baseBranch
+			gitRef := cfg.Upstream() + "/" + baseBranch
 			if localBase {
 				gitRef = baseBranch
 			}
@@ -205,12 +205,12 @@ func checkoutCmd() *cli.Command {
 			done = tr.StartCtx(ctx, "git worktree add (new)")
 			if cdOnly {
 				fmt.Fprintf(os.Stderr, "Creating worktree %s from %s...\n", branch, gitRef)
-				if _, err := repoGit.RunStream(os.Stderr, "worktree", "add", wtPath, "-b", branch, gitRef); err != nil {
+				if err := addWorktree(repoGit, cfg, os.Stderr, wtPath, wtPath, "-b", branch, gitRef); err != nil {
 					return fmt.Errorf("failed to create worktree: %w", err)
 				}
 			} else {
 				u.Info(fmt.Sprintf("Creating worktree %s from %s...", u.Bold(branch), u.Bold(gitRef)))
-				if _, err := repoGit.Run("worktree", "add", wtPath, "-b", branch, gitRef); err != nil {
+				if err := addWorktree(repoGit, cfg, nil, wtPath, wtPath, "-b", branch, gitRef); err != nil {
 					return fmt.Errorf("failed to create worktree: %w", err)
 				}
 			}
@@ -35,6 +35,26 @@ func cloneCmd() *cli.Command {
 				Name:  "force",
 				Usage: "Remove existing repo and re-clone from scratch",
 			},
+			&cli.BoolFlag{
+				Name:  "blobless",
+				Usage: "Partial clone without file contents (--filter=blob:none); blobs download on demand",
+			},
+			&cli.BoolFlag{
+				Name:  "treeless",
+				Usage: "Partial clone without trees or blobs (--filter=tree:0); smallest, slowest for history",
+			},
+			&cli.IntFlag{
+				Name:  "depth",
+				Usage: "Shallow clone with history truncated to N commits",
+			},
+			&cli.StringSliceFlag{
+				Name:  "sparse",
+				Usage: "Sparse-checkout cone directory applied to every worktree (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "upstream",
+				Usage: "When cloning a fork, URL of the canonical repo to add as the upstream remote",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			flags := parseFlags(cmd)
@@ -57,6 +77,11 @@ func cloneCmd() *cli.Command {
 			worktreesDir := filepath.Join(config.WorktreesDir(), name)
 			force := cmd.Bool("force")
 
+			mode, err := cloneModeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
 			if _, err := os.Stat(bareDir); err == nil {
 				if !force {
 					return errors.Userf("repository %q already exists at %s\n\nRun with --force to remove it and re-clone", name, bareDir)
@@ -99,7 +124,7 @@ func cloneCmd() *cli.Command {
 
 			done := tr.StartCtx(ctx, "git clone --bare")
 			if err := u.Spin(fmt.Sprintf("Cloning %s into %s", url, u.Bold(bareDir)), func() error {
-				_, err := g.Run("clone", "--bare", url, bareDir)
+				_, err := g.Run(cloneArgs(mode, url, bareDir)...)
 				return err
 			}); err != nil {
 				cleanup()
@@ -115,13 +140,35 @@ func cloneCmd() *cli.Command {
 				return fmt.Errorf("failed to configure fetch refs: %w", err)
 			}
 
-			done = tr.StartCtx(ctx, "git fetch origin")
-			if err := u.Spin("Fetching latest from origin", func() error {
-				_, err := repoGit.Run("fetch", "origin")
+			// A fork clone pushes to origin and takes base branches and PRs
+			// from upstream.
+			local := &config.Config{Clone: mode}
+			if upstreamURL := cmd.String("upstream"); upstreamURL != "" {
+				if err := addRemote(repoGit, "upstream", upstreamURL); err != nil {
+					cleanup()
+					return err
+				}
+				local.UpstreamRemote = "upstream"
+			}
+
+			// Persist the clone mode and remotes so every later fetch and
+			// worktree keeps the same shape.
+			if !mode.IsZero() || local.UpstreamRemote != "" {
+				if err := config.Save(local, config.LocalConfigPath(bareDir)); err != nil {
+					cleanup()
+					return fmt.Errorf("failed to save repo config: %w", err)
+				}
+			}
+			cfg := config.Load(bareDir)
+
+			done = tr.StartCtx(ctx, "git fetch")
+			remotes := strings.Join(configRemotes(cfg), ", ")
+			if err := u.Spin("Fetching latest from "+remotes, func() error {
+				_, err := repoGit.Run(fetchRemotesArgs(cfg)...)
 				return err
 			}); err != nil {
 				cleanup()
-				return fmt.Errorf("failed to fetch from origin: %w", err)
+				return fmt.Errorf("failed to fetch from %s: %w", remotes, err)
 			}
 			done()
 
@@ -134,14 +181,13 @@ func cloneCmd() *cli.Command {
 			wtPath := filepath.Join(worktreesDir, defaultBranch)
 			u.Info(fmt.Sprintf("Creating worktree %s at %s...", u.Bold(defaultBranch), wtPath))
 			done = tr.StartCtx(ctx, "git worktree add")
-			if _, err := repoGit.Run("worktree", "add", wtPath, defaultBranch); err != nil {
+			if err := addWorktree(repoGit, cfg, nil, wtPath, wtPath, defaultBranch); err != nil {
 				cleanup()
 				return fmt.Errorf("failed to create initial worktree: %w", err)
 			}
 			done()
 
 			done = tr.StartCtx(ctx, "post-checkout hook")
-			cfg := config.Load(bareDir)
 			runPostCheckoutHook(cfg.PostCheckoutHook, wtPath, u, false)
 			done()
 
@@ -153,6 +199,30 @@ func cloneCmd() *cli.Command {
 	}
 }
 
+// cloneModeFromFlags builds the clone mode requested on the command line.
+func cloneModeFromFlags(cmd *cli.Command) (config.CloneConfig, error) {
+	var mode config.CloneConfig
+	if cmd.Bool("blobless") && cmd.Bool("treeless") {
+		return mode, errors.Userf("--blobless and --treeless cannot be combined")
+	}
+	if cmd.Bool("blobless") {
+		mode.Filter = config.FilterBlobless
+	}
+	if cmd.Bool("treeless") {
+		mode.Filter = config.FilterTreeless
+	}
+	if cmd.Int("depth") < 0 {
+		return mode, errors.Userf("--depth must be a positive number")
+	}
+	mode.Depth = int(cmd.Int("depth"))
+	for _, s := range cmd.StringSlice("sparse") {
+		if s = strings.Trim(strings.TrimSpace(s), "/"); s != "" {
+			mode.Sparse = append(mode.Sparse, s)
+		}
+	}
+	return mode, nil
+}
+
 // repoNameFromURL extracts the repository name from a git URL.
 // Handles both SSH (git@github.com:org/repo.git) and HTTPS (https://github.com/org/repo.git).
 func repoNameFromURL(url string) string {
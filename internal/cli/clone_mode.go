@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+// fetchArgs builds a git fetch invocation that keeps a shallow or partial
+// clone in the shape recorded in the repo's clone config. Without the
+// explicit --depth, fetching a new branch would pull its full history.
+func fetchArgs(cfg *config.Config, args ...string) []string {
+	out := []string{"fetch"}
+	if cfg != nil && cfg.Clone.Depth > 0 {
+		out = append(out, "--depth", strconv.Itoa(cfg.Clone.Depth))
+	}
+	if cfg != nil && cfg.Clone.Filter != "" {
+		out = append(out, "--filter="+cfg.Clone.Filter)
+	}
+	return append(out, args...)
+}
+
+// cloneArgs builds the git clone --bare invocation for a clone mode.
+func cloneArgs(mode config.CloneConfig, url, bareDir string) []string {
+	args := []string{"clone", "--bare"}
+	if mode.Filter != "" {
+		args = append(args, "--filter="+mode.Filter)
+	}
+	if mode.Depth > 0 {
+		// --depth implies --single-branch; willow needs every branch.
+		args = append(args, "--depth", strconv.Itoa(mode.Depth), "--no-single-branch")
+	}
+	return append(args, url, bareDir)
+}
+
+// addWorktree runs git worktree add with args (which include wtPath),
+// streaming git's stderr to stderr when it is non-nil. With sparse-checkout
+// patterns configured, the worktree is created without a checkout and only
+// populated once the cone is set, so files outside it are never written —
+// or, in a blobless clone, downloaded.
+func addWorktree(repoGit *git.Git, cfg *config.Config, stderr io.Writer, wtPath string, args ...string) error {
+	sparse := cfg != nil && len(cfg.Clone.Sparse) > 0
+	full := []string{"worktree", "add"}
+	if sparse {
+		full = append(full, "--no-checkout")
+	}
+	full = append(full, args...)
+
+	var err error
+	if stderr != nil {
+		_, err = repoGit.RunStream(stderr, full...)
+	} else {
+		_, err = repoGit.Run(full...)
+	}
+	if err != nil || !sparse {
+		return err
+	}
+	return applySparseCheckout(&git.Git{Dir: wtPath, Verbose: repoGit.Verbose}, cfg.Clone.Sparse)
+}
+
+func applySparseCheckout(wtGit *git.Git, patterns []string) error {
+	if _, err := wtGit.Run(append([]string{"sparse-checkout", "set", "--cone"}, patterns...)...); err != nil {
+		return fmt.Errorf("failed to set sparse-checkout: %w", err)
+	}
+	if _, err := wtGit.Run("read-tree", "-mu", "HEAD"); err != nil {
+		return fmt.Errorf("failed to populate sparse worktree: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,14 @@
 package cli
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
 
 func TestRepoNameFromURL(t *testing.T) {
 	tests := []struct {
@@ -35,3 +43,96 @@ func TestRepoNameFromURL(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchArgsFollowCloneMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		clone config.CloneConfig
+		want  string
+	}{
+		{"full", config.CloneConfig{}, "fetch --no-tags origin"},
+		{"blobless", config.CloneConfig{Filter: config.FilterBlobless}, "fetch --filter=blob:none --no-tags origin"},
+		{"shallow treeless", config.CloneConfig{Filter: config.FilterTreeless, Depth: 5}, "fetch --depth 5 --filter=tree:0 --no-tags origin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(fetchArgs(&config.Config{Clone: tt.clone}, "--no-tags", "origin"), " ")
+			if got != tt.want {
+				t.Errorf("fetchArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneArgsKeepAllBranchesWhenShallow(t *testing.T) {
+	got := strings.Join(cloneArgs(config.CloneConfig{Filter: config.FilterBlobless, Depth: 1}, "url", "dir"), " ")
+	want := "clone --bare --filter=blob:none --depth 1 --no-single-branch url dir"
+	if got != want {
+		t.Errorf("cloneArgs = %q, want %q", got, want)
+	}
+}
+
+func TestCloneShallowSparsePersistsMode(t *testing.T) {
+	origin := setupTestEnv(t)
+	workdir := filepath.Join(os.Getenv("HOME"), "workdir")
+	wg := &git.Git{Dir: workdir}
+	for _, f := range []string{"app/main.go", "docs/guide.md"} {
+		os.MkdirAll(filepath.Join(workdir, filepath.Dir(f)), 0o755)
+		os.WriteFile(filepath.Join(workdir, f), []byte(f+"\n"), 0o644)
+	}
+	if _, err := wg.Run("add", "."); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if _, err := wg.Run("commit", "-m", "add dirs"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+	if _, err := wg.Run("push", "origin", "HEAD"); err != nil {
+		t.Fatalf("git push: %v", err)
+	}
+
+	// --depth is ignored for plain-path clones, so use a file:// URL.
+	if err := runApp("clone", "file://"+origin, "testrepo", "--depth", "1", "--sparse", "app/"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+
+	bareDir := filepath.Join(config.ReposDir(), "testrepo.git")
+	local, err := config.LoadFile(config.LocalConfigPath(bareDir))
+	if err != nil {
+		t.Fatalf("load willow.json: %v", err)
+	}
+	if local.Clone.Depth != 1 || len(local.Clone.Sparse) != 1 || local.Clone.Sparse[0] != "app" {
+		t.Fatalf("clone config = %+v", local.Clone)
+	}
+	bareGit := &git.Git{Dir: bareDir}
+	if out, err := bareGit.Run("rev-parse", "--is-shallow-repository"); err != nil || out != "true" {
+		t.Errorf("is-shallow = %q, %v", out, err)
+	}
+
+	// A worktree from ww new, which fetches, keeps the shallow, sparse shape.
+	wtRoot := filepath.Join(config.WorktreesDir(), "testrepo")
+	entries, _ := os.ReadDir(wtRoot)
+	os.Chdir(filepath.Join(wtRoot, entries[0].Name()))
+	if err := runApp("new", "feature"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	for _, dir := range []string{entries[0].Name(), "feature"} {
+		wt := filepath.Join(wtRoot, dir)
+		if !pathExists(filepath.Join(wt, "app", "main.go")) || !pathExists(filepath.Join(wt, "README.md")) {
+			t.Errorf("%s is missing files inside the sparse cone", dir)
+		}
+		if pathExists(filepath.Join(wt, "docs")) {
+			t.Errorf("%s checked out docs/ outside the sparse cone", dir)
+		}
+	}
+	if out, err := bareGit.Run("rev-list", "--count", "origin/"+entries[0].Name()); err != nil || out != "1" {
+		t.Errorf("history after fetch = %q commits, %v; want 1", out, err)
+	}
+}
+
+func TestCloneRejectsBlobAndTreelessTogether(t *testing.T) {
+	origin := setupTestEnv(t)
+	err := runApp("clone", origin, "testrepo", "--blobless", "--treeless")
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -88,13 +88,21 @@ func configShowCmd() *cli.Command {
 			printField("baseDir", formatStringValue(merged.BaseDir), baseDirSource(global.BaseDir))
 			printField("baseBranch", formatStringValue(merged.BaseBranch), fieldSource(local.BaseBranch, global.BaseBranch, def.BaseBranch))
 			printField("branchPrefix", formatStringValue(merged.BranchPrefix), fieldSource(local.BranchPrefix, global.BranchPrefix, def.BranchPrefix))
+			printField("upstreamRemote", formatStringValue(merged.UpstreamRemote), fieldSource(local.UpstreamRemote, global.UpstreamRemote, def.UpstreamRemote))
+			printField("pushRemote", formatStringValue(merged.PushRemote), fieldSource(local.PushRemote, global.PushRemote, def.PushRemote))
 			printField("postCheckoutHook", formatStringValue(merged.PostCheckoutHook), fieldSource(local.PostCheckoutHook, global.PostCheckoutHook, def.PostCheckoutHook))
-			printField("setup", formatStringSliceValue(merged.Setup), fieldSourceSlice(local.Setup, global.Setup, def.Setup))
+			printField("setup", formatSetupValue(merged.Setup), fieldSourceSlice(local.Setup, global.Setup, def.Setup))
 			printField("teardown", formatStringSliceValue(merged.Teardown), fieldSourceSlice(local.Teardown, global.Teardown, def.Teardown))
+			printField("copy", formatStringSliceValue(merged.Copy), fieldSourceSlice(local.Copy, global.Copy, def.Copy))
+			printField("symlink", formatStringSliceValue(merged.Symlink), fieldSourceSlice(local.Symlink, global.Symlink, def.Symlink))
+			printField("copyFrom", formatStringValue(merged.CopyFrom), fieldSource(local.CopyFrom, global.CopyFrom, def.CopyFrom))
+			printField("env", formatStringSliceValue(merged.EnvList()), fieldSourceMap(local.Env, global.Env))
+			printField("profiles", formatStringSliceValue(merged.ProfileNames()), fieldSourceMap(local.Profiles, global.Profiles))
 			printField("defaults.fetch", formatBoolPtrValue(merged.Defaults.Fetch), fieldSourceBoolPtr(local.Defaults.Fetch, global.Defaults.Fetch, def.Defaults.Fetch))
 			printField("defaults.autoSetupRemote", formatBoolPtrValue(merged.Defaults.AutoSetupRemote), fieldSourceBoolPtr(local.Defaults.AutoSetupRemote, global.Defaults.AutoSetupRemote, def.Defaults.AutoSetupRemote))
 			printField("notify.desktop", formatBoolPtrValue(merged.Notify.Desktop), fieldSourceBoolPtr(local.Notify.Desktop, global.Notify.Desktop, def.Notify.Desktop))
 			printField("notify.command", formatStringValue(merged.Notify.Command), fieldSource(local.Notify.Command, global.Notify.Command, def.Notify.Command))
+			printField("notify.sinks", formatSinkSliceValue(merged.Notify.Sinks), fieldSourceSlice(local.Notify.Sinks, global.Notify.Sinks, def.Notify.Sinks))
 			printField("agent.default", formatStringValue(merged.Agent.Default), fieldSource(local.Agent.Default, global.Agent.Default, def.Agent.Default))
 			printField("tmux.reloadInterval", formatIntValue(merged.Tmux.ReloadInterval), fieldSource(local.Tmux.ReloadInterval, global.Tmux.ReloadInterval, def.Tmux.ReloadInterval))
 			printField("tmux.notification", formatBoolPtrValue(merged.Tmux.Notification), fieldSourceBoolPtr(local.Tmux.Notification, global.Tmux.Notification, def.Tmux.Notification))
@@ -103,6 +111,16 @@ func configShowCmd() *cli.Command {
 			printField("tmux.switcherPreview", formatBoolPtrValue(merged.Tmux.SwitcherPreview), fieldSourceBoolPtr(local.Tmux.SwitcherPreview, global.Tmux.SwitcherPreview, def.Tmux.SwitcherPreview))
 			printField("tmux.layout", formatStringSliceValue(merged.Tmux.Layout), fieldSourceSlice(local.Tmux.Layout, global.Tmux.Layout, def.Tmux.Layout))
 			printField("tmux.panes", formatPaneSliceValue(merged.Tmux.Panes), fieldSourceSlice(local.Tmux.Panes, global.Tmux.Panes, def.Tmux.Panes))
+			printField("clone.filter", formatStringValue(merged.Clone.Filter), fieldSource(local.Clone.Filter, global.Clone.Filter, def.Clone.Filter))
+			printField("clone.depth", formatIntValue(merged.Clone.Depth), fieldSource(local.Clone.Depth, global.Clone.Depth, def.Clone.Depth))
+			printField("clone.sparse", formatStringSliceValue(merged.Clone.Sparse), fieldSourceSlice(local.Clone.Sparse, global.Clone.Sparse, def.Clone.Sparse))
+			printField("gc.inactiveDays", formatIntValue(merged.GC.InactiveDays), fieldSource(local.GC.InactiveDays, global.GC.InactiveDays, def.GC.InactiveDays))
+			printField("gc.detachedDays", formatIntValue(merged.GC.DetachedDays), fieldSource(local.GC.DetachedDays, global.GC.DetachedDays, def.GC.DetachedDays))
+			printField("gc.trashDays", formatIntValue(merged.GC.TrashDays), fieldSource(local.GC.TrashDays, global.GC.TrashDays, def.GC.TrashDays))
+			printField("gc.trashMaxMB", formatIntValue(merged.GC.TrashMaxMB), fieldSource(local.GC.TrashMaxMB, global.GC.TrashMaxMB, def.GC.TrashMaxMB))
+			printField("gc.sessionDays", formatIntValue(merged.GC.SessionDays), fieldSource(local.GC.SessionDays, global.GC.SessionDays, def.GC.SessionDays))
+			printField("ports.count", formatIntValue(merged.Ports.Count), fieldSource(local.Ports.Count, global.Ports.Count, def.Ports.Count))
+			printField("ports.start", formatIntValue(merged.Ports.StartPort()), fieldSource(local.Ports.Start, global.Ports.Start, def.Ports.Start))
 			printField("telemetry", formatBoolPtrValue(merged.Telemetry), fieldSourceBoolPtr(local.Telemetry, global.Telemetry, def.Telemetry))
 
 			if warnings := merged.Validate(); len(warnings) > 0 {
@@ -285,6 +303,17 @@ func formatStringSliceValue(v []string) string {
 	return fmt.Sprintf("%v", v)
 }
 
+func formatSetupValue(v []config.SetupStep) string {
+	if v == nil {
+		return "[]"
+	}
+	commands := make([]string, len(v))
+	for i, s := range v {
+		commands[i] = s.String()
+	}
+	return fmt.Sprintf("%v", commands)
+}
+
 func formatPaneSliceValue(v []config.PaneConfig) string {
 	if len(v) == 0 {
 		return "[]"
@@ -292,6 +321,13 @@ func formatPaneSliceValue(v []config.PaneConfig) string {
 	return fmt.Sprintf("[%d panes]", len(v))
 }
 
+func formatSinkSliceValue(v []config.NotifySinkConfig) string {
+	if len(v) == 0 {
+		return "[]"
+	}
+	return fmt.Sprintf("[%d sinks]", len(v))
+}
+
 func formatIntValue(v int) string {
 	return fmt.Sprintf("%d", v)
 }
@@ -322,7 +358,17 @@ func fieldSourceBoolPtr(localVal, globalVal, defaultVal *bool) string {
 }
 
 type configSliceElem interface {
-	string | config.PaneConfig
+	string | config.SetupStep | config.PaneConfig | config.NotifySinkConfig
+}
+
+func fieldSourceMap[K comparable, V any](localVal, globalVal map[K]V) string {
+	if localVal != nil {
+		return "local"
+	}
+	if globalVal != nil {
+		return "global"
+	}
+	return "default"
 }
 
 func fieldSourceSlice[T configSliceElem](localVal, globalVal, _ []T) string {
@@ -13,12 +13,12 @@ func dashboardCmd() *cli.Command {
 	return &cli.Command{
 		Name:    "dashboard",
 		Aliases: []string{"dash", "d"},
-		Usage:   "Live overview of all worktrees across repos",
+		Usage:   "Interactive overview of all worktrees across repos",
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:    "interval",
 				Aliases: []string{"i"},
-				Usage:   "Refresh interval in seconds",
+				Usage:   "Refresh interval in seconds for git state (agent status updates instantly)",
 				Value:   2,
 			},
 		},
@@ -27,6 +27,7 @@ func dashboardCmd() *cli.Command {
 			interval := time.Duration(cmd.Int("interval")) * time.Second
 			return dashboard.Run(ctx, dashboard.Config{
 				RefreshInterval: interval,
+				Switch:          ensureTmuxSession,
 			})
 		},
 	}
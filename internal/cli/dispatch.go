@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/iamrajjoshi/willow/internal/agent/harness"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/errors"
 	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/tmux"
 	"github.com/iamrajjoshi/willow/internal/trace"
 	"github.com/iamrajjoshi/willow/internal/ui"
 	"github.com/urfave/cli/v3"
@@ -52,7 +55,21 @@ func dispatchCmd() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "agent",
-				Usage: "Agent harness to launch (claude, codex, or cursor; default from agent.default)",
+				Usage: "Agent harness to launch (claude, codex, cursor, or a custom harness; default from agent.default)",
+			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Aliases: []string{"p"},
+				Usage:   "Create the worktree from a named profile in config",
+			},
+			&cli.StringFlag{
+				Name:  "batch",
+				Usage: "Dispatch every task in a YAML or JSONL file, each in its own tmux session",
+			},
+			&cli.IntFlag{
+				Name:  "wait",
+				Usage: "With --batch, seconds to wait for agent sessions to report in",
+				Value: 30,
 			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -62,8 +79,12 @@ func dispatchCmd() *cli.Command {
 			u := flags.NewUI()
 
 			prompt := cmd.StringArg("prompt")
-			if prompt == "" {
-				return errors.Userf("prompt is required\n\nUsage: ww dispatch <prompt> [flags]")
+			batchFile := cmd.String("batch")
+			if batchFile != "" && prompt != "" {
+				return errors.Userf("--batch cannot be combined with a prompt argument")
+			}
+			if batchFile == "" && prompt == "" {
+				return errors.Userf("prompt is required\n\nUsage: ww dispatch <prompt> [flags]\n       ww dispatch --batch <file> [flags]")
 			}
 
 			var bareDir string
@@ -80,7 +101,24 @@ func dispatchCmd() *cli.Command {
 				}
 			}
 			repoName := repoNameFromDir(bareDir)
-			cfg := config.Load(bareDir)
+			cfg, err := applyProfileFlag(config.Load(bareDir), cmd.String("profile"))
+			if err != nil {
+				return err
+			}
+			if batchFile != "" {
+				if cmd.String("name") != "" {
+					return errors.Userf("--name cannot be used with --batch; set name per task")
+				}
+				return dispatchBatch(ctx, u, bareDir, cfg, batchFile, batchOptions{
+					base:    cmd.String("base"),
+					agent:   cmd.String("agent"),
+					yolo:    cmd.Bool("yolo"),
+					noFetch: cmd.Bool("no-fetch"),
+					verbose: g.Verbose,
+					wait:    time.Duration(cmd.Int("wait")) * time.Second,
+				})
+			}
+
 			agentID := cmd.String("agent")
 			if agentID == "" {
 				agentID = harness.DefaultID(cfg)
@@ -104,6 +142,9 @@ func dispatchCmd() *cli.Command {
 			if base := cmd.String("base"); base != "" {
 				args = append(args, "--base", base)
 			}
+			if cfg.Profile != "" {
+				args = append(args, "--profile", cfg.Profile)
+			}
 			if cmd.Bool("no-fetch") {
 				args = append(args, "--no-fetch")
 			}
@@ -145,12 +186,51 @@ func dispatchForeground(u *ui.UI, wtPath, branch, prompt string, h harness.Harne
 
 	cmd := exec.Command(launch.Command, launch.Args...)
 	cmd.Dir = wtPath
+	if env := worktreeEnv(cfg, wtPath, u); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// startDispatchSession opens a tmux session in wtPath and types the agent
+// launch into it. The prompt is passed through a file so tmux send-keys never
+// has to carry (or quote) the prompt text itself.
+func startDispatchSession(wtPath, prompt string, h harness.Harness, cfg *config.Config, yolo bool) (string, error) {
+	wtDir := filepath.Base(wtPath)
+	repoName := filepath.Base(filepath.Dir(wtPath))
+
+	sessName := tmux.SessionNameForWorktree(repoName, wtDir)
+
+	promptFile := filepath.Join(config.WillowHome(), "prompts", repoName, wtDir+".prompt")
+	if err := os.MkdirAll(filepath.Dir(promptFile), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create prompts dir: %w", err)
+	}
+	if err := os.WriteFile(promptFile, []byte(prompt), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write prompt file: %w", err)
+	}
+
+	cfg = withWorktreeProfile(cfg, wtPath, nil)
+	if err := tmux.NewSession(sessName, wtPath, worktreeEnv(cfg, wtPath, nil), cfg.Tmux.Layout, cfg.Tmux.Panes); err != nil {
+		return "", fmt.Errorf("failed to create tmux session: %w", err)
+	}
+
+	promptArg := fmt.Sprintf(`"$(cat %s)"`, shellQuote(promptFile))
+	agentCmd := h.BuildShellLaunch(harness.ShellLaunchOptions{
+		PromptArg:    promptArg,
+		PromptArgRaw: true,
+		Yolo:         yolo,
+		Overrides:    harness.OverridesFor(cfg, h.ID()),
+	})
+	agentCmd = fmt.Sprintf("%s; rm -f %s", agentCmd, shellQuote(promptFile))
+	if err := tmux.SendKeys(sessName, agentCmd, "Enter"); err != nil {
+		return "", fmt.Errorf("failed to send agent command: %w", err)
+	}
+	return sessName, nil
+}
+
 var slugRe = regexp.MustCompile(`[^a-z0-9-]`)
 
 func slugify(s string) string {
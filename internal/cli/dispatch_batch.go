@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/parallel"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// batchTask is one entry in a ww dispatch --batch file. Empty fields fall
+// back to the command-line flags. Yolo is a pointer so a task can set
+// yolo: false to opt out of --yolo.
+type batchTask struct {
+	Name   string `yaml:"name" json:"name"`
+	Base   string `yaml:"base" json:"base"`
+	Prompt string `yaml:"prompt" json:"prompt"`
+	Agent  string `yaml:"agent" json:"agent"`
+	Yolo   *bool  `yaml:"yolo" json:"yolo"`
+}
+
+type batchOptions struct {
+	base    string
+	agent   string
+	yolo    bool
+	noFetch bool
+	verbose bool
+	wait    time.Duration
+}
+
+type batchResult struct {
+	task     batchTask
+	harness  harness.Harness
+	path     string
+	tmux     string
+	sessions []*agent.SessionStatus
+	err      error
+}
+
+const batchPollInterval = 500 * time.Millisecond
+
+// loadBatchTasks reads a batch file. .jsonl and .ndjson files hold one task
+// object per line; anything else is parsed as a YAML list of tasks (which
+// also accepts a JSON array).
+func loadBatchTasks(path string) ([]batchTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Userf("failed to read batch file: %v", err)
+	}
+
+	var tasks []batchTask
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for n := 1; scanner.Scan(); n++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.DisallowUnknownFields()
+			var t batchTask
+			if err := dec.Decode(&t); err != nil {
+				return nil, errors.Userf("%s line %d: %v", path, n, err)
+			}
+			tasks = append(tasks, t)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.Userf("failed to read batch file: %v", err)
+		}
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&tasks); err != nil && err != io.EOF {
+			return nil, errors.Userf("%s: %v", path, err)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return nil, errors.Userf("%s has no tasks", path)
+	}
+	return tasks, nil
+}
+
+// prepareBatchTasks fills in defaults and resolves each task's harness, so
+// a bad entry fails the batch before any worktree is created.
+func prepareBatchTasks(tasks []batchTask, opts batchOptions, cfg *config.Config) ([]batchResult, error) {
+	results := make([]batchResult, 0, len(tasks))
+	names := map[string]int{}
+	for i, t := range tasks {
+		n := i + 1
+		t.Prompt = strings.TrimSpace(t.Prompt)
+		if t.Prompt == "" {
+			return nil, errors.Userf("task %d: prompt is required", n)
+		}
+		if t.Name == "" {
+			t.Name = "dispatch--" + slugify(t.Prompt)
+		}
+		if prev, ok := names[t.Name]; ok {
+			return nil, errors.Userf("task %d: name %q is already used by task %d", n, t.Name, prev)
+		}
+		names[t.Name] = n
+		if t.Base == "" {
+			t.Base = opts.base
+		}
+		if t.Agent == "" {
+			t.Agent = opts.agent
+		}
+		if t.Agent == "" {
+			t.Agent = harness.DefaultID(cfg)
+		}
+		if t.Yolo == nil {
+			t.Yolo = config.BoolPtr(opts.yolo)
+		}
+
+		h, err := harness.MustGet(t.Agent)
+		if err != nil {
+			return nil, errors.Userf("task %d: %v", n, err)
+		}
+		results = append(results, batchResult{task: t, harness: h})
+	}
+	return results, nil
+}
+
+func dispatchBatch(ctx context.Context, u *ui.UI, bareDir string, cfg *config.Config, file string, opts batchOptions) error {
+	defer trace.Span(ctx, "cli.dispatch.batch")()
+
+	tasks, err := loadBatchTasks(file)
+	if err != nil {
+		return err
+	}
+	results, err := prepareBatchTasks(tasks, opts, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errors.Userf("tmux not found — --batch launches each agent in its own tmux session")
+	}
+	checked := map[string]bool{}
+	for _, r := range results {
+		if checked[r.harness.ID()] {
+			continue
+		}
+		checked[r.harness.ID()] = true
+		launch := r.harness.BuildLaunch(harness.LaunchOptions{Overrides: harness.OverridesFor(cfg, r.harness.ID())})
+		if _, err := exec.LookPath(launch.Command); err != nil {
+			return errors.Userf("%q CLI not found — install %s first", launch.Command, r.harness.DisplayName())
+		}
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find willow binary: %w", err)
+	}
+	repoName := repoNameFromDir(bareDir)
+
+	// Fetch once up front; the parallel ww new calls then skip fetching so
+	// they don't race each other updating the same refs.
+	if *cfg.Defaults.Fetch && !opts.noFetch {
+		repoGit := &git.Git{Dir: bareDir, Verbose: opts.verbose}
+		if err := u.Spin("Fetching latest branches", func() error {
+			_, err := repoGit.Run(fetchRemotesArgs(cfg, "--no-tags")...)
+			return err
+		}); err != nil {
+			u.Warn(fmt.Sprintf("Failed to fetch: %v", err))
+		}
+	}
+
+	u.Info(fmt.Sprintf("Dispatching %d tasks to %s...", len(results), u.Bold(repoName)))
+	// git worktree add records the new branch's upstream in the bare repo's
+	// shared config, and concurrent writers fail to lock it. Create the
+	// worktrees one at a time and launch only the agents in parallel.
+	for i := range results {
+		results[i] = createBatchWorktree(self, repoName, cfg, results[i])
+	}
+	results = parallel.Map(results, func(_ int, r batchResult) batchResult {
+		if r.err != nil {
+			return r
+		}
+		return launchBatchAgent(repoName, cfg, r)
+	})
+
+	if opts.wait > 0 {
+		_ = u.Spin("Waiting for agent sessions to report in", func() error {
+			waitForBatchSessions(results, repoName, opts.wait)
+			return nil
+		})
+	}
+
+	for _, line := range formatBatchLines(u, results, termfmt.TerminalWidth()) {
+		u.Info(line)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Userf("%d of %d tasks failed", failed, len(results))
+	}
+	return nil
+}
+
+func createBatchWorktree(self, repoName string, cfg *config.Config, r batchResult) batchResult {
+	args := []string{"new", "--cd", "--repo", repoName, "--no-fetch"}
+	if cfg.Profile != "" {
+		args = append(args, "--profile", cfg.Profile)
+	}
+	if r.task.Base != "" {
+		args = append(args, "--base", r.task.Base)
+	}
+	args = append(args, "--", r.task.Name)
+
+	var stderr bytes.Buffer
+	newCmd := exec.Command(self, args...)
+	newCmd.Stderr = &stderr
+	out, err := newCmd.Output()
+	if err != nil {
+		r.err = fmt.Errorf("failed to create worktree: %s", lastOutputLine(stderr.String(), err))
+		return r
+	}
+	r.path = strings.TrimSpace(string(out))
+	if r.path == "" {
+		r.err = fmt.Errorf("no path returned from willow new")
+	}
+	return r
+}
+
+func launchBatchAgent(repoName string, cfg *config.Config, r batchResult) batchResult {
+	meta := map[string]string{"prompt": truncatePrompt(r.task.Prompt), "agent": r.harness.ID(), "batch": "true"}
+	_ = log.Append(log.Event{Action: "dispatch", Repo: repoName, Branch: r.task.Name, Metadata: meta})
+
+	r.tmux, r.err = startDispatchSession(r.path, r.task.Prompt, r.harness, cfg, *r.task.Yolo)
+	return r
+}
+
+// waitForBatchSessions polls the status dir until every launched task has
+// a session from its harness, or wait elapses.
+func waitForBatchSessions(results []batchResult, repoName string, wait time.Duration) {
+	deadline := time.Now().Add(wait)
+	for {
+		pending := false
+		for i := range results {
+			r := &results[i]
+			if r.err != nil || len(r.sessions) > 0 {
+				continue
+			}
+			for _, ss := range agent.ReadAllSessions(repoName, filepath.Base(r.path)) {
+				if ss.Harness == r.harness.ID() {
+					r.sessions = append(r.sessions, ss)
+				}
+			}
+			if len(r.sessions) == 0 {
+				pending = true
+			}
+		}
+		if !pending || !time.Now().Before(deadline) {
+			return
+		}
+		time.Sleep(batchPollInterval)
+	}
+}
+
+func formatBatchLines(u *ui.UI, results []batchResult, width int) []string {
+	type row struct {
+		name, agent, session, tmux string
+	}
+	rows := []row{{name: "NAME", agent: "AGENT", session: "SESSION", tmux: "TMUX"}}
+	for _, r := range results {
+		session := "pending"
+		switch {
+		case r.err != nil:
+			session = "failed"
+		case len(r.sessions) > 0:
+			ids := make([]string, len(r.sessions))
+			for i, ss := range r.sessions {
+				ids[i] = agent.ShortSessionID(ss.SessionID)
+			}
+			session = strings.Join(ids, ",")
+		}
+		rows = append(rows, row{name: r.task.Name, agent: r.harness.ID(), session: session, tmux: r.tmux})
+	}
+
+	nameW, agentW, sessW := 0, 0, 0
+	for _, r := range rows {
+		nameW = max(nameW, termfmt.VisibleWidth(r.name))
+		agentW = max(agentW, termfmt.VisibleWidth(r.agent))
+		sessW = max(sessW, termfmt.VisibleWidth(r.session))
+	}
+	fixed := 2 + 2 + agentW + 2 + sessW
+	if available := termfmt.Width(width) - fixed; available < nameW {
+		nameW = max(1, available)
+	}
+
+	format := func(r row, session string) string {
+		return fmt.Sprintf("  %s  %-*s  %s  %s", termfmt.FitRight(r.name, nameW), agentW, r.agent,
+			session+strings.Repeat(" ", sessW-termfmt.VisibleWidth(r.session)), r.tmux)
+	}
+	lines := []string{"", u.Bold(strings.TrimRight(format(rows[0], rows[0].session), " "))}
+	for i, r := range rows[1:] {
+		session := r.session
+		switch {
+		case results[i].err != nil:
+			session = u.Red(session)
+		case len(results[i].sessions) == 0:
+			session = u.Dim(session)
+		}
+		lines = append(lines, strings.TrimRight(format(r, session), " "))
+	}
+	for _, r := range results {
+		if r.err != nil {
+			lines = append(lines, u.Red(fmt.Sprintf("  %s: %v", r.task.Name, r.err)))
+		}
+	}
+	return lines
+}
+
+func lastOutputLine(out string, fallback error) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return fallback.Error()
+}
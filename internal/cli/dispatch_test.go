@@ -2,11 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/iamrajjoshi/willow/internal/agent"
 	"github.com/iamrajjoshi/willow/internal/agent/harness"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/ui"
@@ -195,6 +199,31 @@ func TestDispatchCmdRunsCursorWithYolo(t *testing.T) {
 	}
 }
 
+func TestDispatchCmdRunsCustomHarness(t *testing.T) {
+	home := setupTmuxCommandHome(t, "repo")
+	writeGlobalConfigFile(t, `{"telemetry":false,"agent":{"harnesses":{"aider":{
+		"command":"aider","displayName":"Aider","promptFlag":"--message","yoloArgs":["--yes-always"]
+	}}}}`)
+	helperLog := filepath.Join(t.TempDir(), "willow-helper.log")
+	t.Setenv("WILLOW_TEST_HELPER_PROCESS", "willow")
+	t.Setenv("WILLOW_TEST_HELPER_WT_ROOT", filepath.Join(home, ".willow", "worktrees"))
+	t.Setenv("WILLOW_TEST_HELPER_LOG", helperLog)
+	t.Cleanup(func() { harness.RegisterCustom(nil) })
+
+	binDir := t.TempDir()
+	aiderLog := filepath.Join(t.TempDir(), "aider.log")
+	writeTestExecutable(t, binDir, "aider", "#!/bin/sh\nprintf 'args=%s\\n' \"$*\" >> "+shellQuote(aiderLog)+"\n")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := runApp("dispatch", "Fix auth", "--repo", "repo", "--name", "dispatch-aider", "--agent", "aider", "--yolo"); err != nil {
+		t.Fatalf("dispatch command failed: %v", err)
+	}
+
+	if got := readTestFile(t, aiderLog); !strings.Contains(got, "args=--yes-always --message Fix auth") {
+		t.Fatalf("aider log = %q", got)
+	}
+}
+
 func TestDispatchForegroundRunsClaudeInWorktree(t *testing.T) {
 	binDir := t.TempDir()
 	logPath := filepath.Join(t.TempDir(), "agent.log")
@@ -238,3 +267,192 @@ func TestDispatchForegroundMissingClaude(t *testing.T) {
 		t.Fatalf("error = %v, want missing claude message", err)
 	}
 }
+
+func TestLoadBatchTasksYAMLAndJSONL(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "tasks.yaml")
+	if err := os.WriteFile(yamlPath, []byte(`- name: fix-login
+  base: main
+  prompt: Fix the login bug
+  agent: codex
+  yolo: true
+- prompt: |
+    Add retries
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := loadBatchTasks(yamlPath)
+	if err != nil {
+		t.Fatalf("loadBatchTasks(yaml): %v", err)
+	}
+	want := batchTask{Name: "fix-login", Base: "main", Prompt: "Fix the login bug", Agent: "codex", Yolo: config.BoolPtr(true)}
+	if len(tasks) != 2 || !reflect.DeepEqual(tasks[0], want) || tasks[1].Prompt != "Add retries\n" || tasks[1].Yolo != nil {
+		t.Fatalf("yaml tasks = %+v", tasks)
+	}
+
+	jsonlPath := filepath.Join(dir, "tasks.jsonl")
+	if err := os.WriteFile(jsonlPath, []byte(`{"name":"a","prompt":"one"}`+"\n\n"+`{"prompt":"two","agent":"cursor"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = loadBatchTasks(jsonlPath)
+	if err != nil {
+		t.Fatalf("loadBatchTasks(jsonl): %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "a" || tasks[1].Agent != "cursor" {
+		t.Fatalf("jsonl tasks = %+v", tasks)
+	}
+
+	badPath := filepath.Join(dir, "bad.jsonl")
+	if err := os.WriteFile(badPath, []byte(`{"prompt":"x","branch":"typo"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBatchTasks(badPath); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("unknown field error = %v, want line 1 error", err)
+	}
+}
+
+func TestPrepareBatchTasksAppliesDefaults(t *testing.T) {
+	cfg := config.DefaultConfig()
+	results, err := prepareBatchTasks([]batchTask{
+		{Prompt: "Fix the login bug"},
+		{Name: "retries", Prompt: "Add retries", Agent: "codex", Base: "develop", Yolo: config.BoolPtr(false)},
+	}, batchOptions{base: "main", yolo: true}, cfg)
+	if err != nil {
+		t.Fatalf("prepareBatchTasks: %v", err)
+	}
+	first := results[0].task
+	if first.Name != "dispatch--fix-the-login-bug" || first.Base != "main" || !*first.Yolo || results[0].harness.ID() != "claude" {
+		t.Fatalf("first task = %+v harness=%s", first, results[0].harness.ID())
+	}
+	// yolo: false in the task overrides --yolo.
+	if second := results[1].task; second.Base != "develop" || *second.Yolo || results[1].harness.ID() != "codex" {
+		t.Fatalf("second task = %+v harness=%s", second, results[1].harness.ID())
+	}
+
+	_, err = prepareBatchTasks([]batchTask{{Name: "x", Prompt: "a"}, {Name: "x", Prompt: "b"}}, batchOptions{}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "already used by task 1") {
+		t.Fatalf("duplicate name error = %v", err)
+	}
+	_, err = prepareBatchTasks([]batchTask{{Name: "x"}}, batchOptions{}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "task 1: prompt is required") {
+		t.Fatalf("missing prompt error = %v", err)
+	}
+}
+
+func TestDispatchCmdBatchLaunchesTmuxSessions(t *testing.T) {
+	home := setupTmuxCommandHome(t, "repo")
+	tmuxLog := installFakeTmuxForCLI(t)
+	helperLog := filepath.Join(t.TempDir(), "willow-helper.log")
+	t.Setenv("WILLOW_TEST_HELPER_PROCESS", "willow")
+	t.Setenv("WILLOW_TEST_HELPER_WT_ROOT", filepath.Join(home, ".willow", "worktrees"))
+	t.Setenv("WILLOW_TEST_HELPER_LOG", helperLog)
+
+	binDir := t.TempDir()
+	writeTestExecutable(t, binDir, "claude", "#!/bin/sh\nexit 0\n")
+	writeTestExecutable(t, binDir, "codex", "#!/bin/sh\nexit 0\n")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// The first task's agent has already reported in.
+	writeActiveSessionFile(t, "repo", "fix-login", "abcdef123456", agent.StatusBusy)
+
+	batchPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	if err := os.WriteFile(batchPath, []byte(`- name: fix-login
+  prompt: Fix the login bug
+  yolo: true
+- name: add-retries
+  base: develop
+  prompt: Add retries
+  agent: codex
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runApp("dispatch", "--batch", batchPath, "--repo", "repo", "--no-fetch", "--wait", "0")
+	})
+	if err != nil {
+		t.Fatalf("dispatch --batch failed: %v", err)
+	}
+
+	helperText := readTestFile(t, helperLog)
+	for _, want := range []string{
+		"new --cd --repo repo --no-fetch -- fix-login",
+		"new --cd --repo repo --no-fetch --base develop -- add-retries",
+	} {
+		if !strings.Contains(helperText, want) {
+			t.Fatalf("helper log missing %q:\n%s", want, helperText)
+		}
+	}
+	tmuxText := readTestFile(t, tmuxLog)
+	for _, want := range []string{
+		"new-session -d -s repo/fix-login",
+		"new-session -d -s repo/add-retries",
+		"'codex' \"$(cat",
+		"--dangerously-skip-permissions",
+	} {
+		if !strings.Contains(tmuxText, want) {
+			t.Fatalf("tmux log missing %q:\n%s", want, tmuxText)
+		}
+	}
+	for _, want := range []string{"NAME", "fix-login", "add-retries", "pending", "repo/add-retries"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDispatchCmdBatchCreatesWorktreesWithRealGit(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "batchrepo"); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+	installFakeTmuxForCLI(t)
+	binDir := t.TempDir()
+	writeTestExecutable(t, binDir, "claude", "#!/bin/sh\nexit 0\n")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("WILLOW_TEST_HELPER_PROCESS", "app")
+
+	var batch strings.Builder
+	names := []string{"task-a", "task-b", "task-c", "task-d", "task-e", "task-f"}
+	for _, name := range names {
+		fmt.Fprintf(&batch, "- name: %s\n  prompt: Work on %s\n", name, name)
+	}
+	batchPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	if err := os.WriteFile(batchPath, []byte(batch.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return runApp("dispatch", "--batch", batchPath, "--repo", "batchrepo", "--no-fetch", "--wait", "0")
+	}); err != nil {
+		t.Fatalf("dispatch --batch failed: %v", err)
+	}
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(config.WorktreesDir(), "batchrepo", name)); err != nil {
+			t.Errorf("worktree %s missing: %v", name, err)
+		}
+	}
+}
+
+func TestWaitForBatchSessionsCollectsHarnessSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeActiveSessionFile(t, "repo", "fix-login", "abcdef123456", agent.StatusBusy)
+
+	results := []batchResult{
+		{task: batchTask{Name: "fix-login"}, harness: harness.Claude{}, path: filepath.Join(home, "fix-login")},
+		{task: batchTask{Name: "broken"}, harness: harness.Claude{}, err: os.ErrNotExist},
+	}
+	waitForBatchSessions(results, "repo", time.Second)
+	if len(results[0].sessions) != 1 || results[0].sessions[0].SessionID != "abcdef123456" {
+		t.Fatalf("sessions = %+v", results[0].sessions)
+	}
+
+	lines := strings.Join(formatBatchLines(&ui.UI{}, results, 120), "\n")
+	for _, want := range []string{agent.ShortSessionID("abcdef123456"), "failed", "broken: file does not exist"} {
+		if !strings.Contains(lines, want) {
+			t.Fatalf("summary missing %q:\n%s", want, lines)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/parallel"
+	"github.com/iamrajjoshi/willow/internal/ports"
+	"github.com/iamrajjoshi/willow/internal/stack"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+)
+
+func execCmd() *cli.Command {
+	stopAfterCommand := 1
+	return &cli.Command{
+		Name:         "exec",
+		Usage:        "Run a command in every selected worktree",
+		UsageText:    "ww exec [flags] [--] <command> [args...]",
+		StopOnNthArg: &stopAfterCommand,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.StringSliceFlag{
+				Name:  "status",
+				Usage: "Only worktrees with an agent in this status: BUSY, WAIT, DONE, or IDLE (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "stack",
+				Usage: "Only this branch and its stacked descendants",
+			},
+			&cli.IntFlag{
+				Name:    "jobs",
+				Aliases: []string{"j"},
+				Value:   parallel.DefaultLimit,
+				Usage:   "Worktrees to run in at once",
+			},
+			&cli.BoolFlag{
+				Name:  "group",
+				Usage: "Print each worktree's output as one block when it finishes instead of prefixing lines",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output results as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.exec")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			args := cmd.Args().Slice()
+			if len(args) > 0 && args[0] == "--" {
+				args = args[1:]
+			}
+			if len(args) == 0 {
+				return errors.Userf("command is required\n\nUsage: ww exec [flags] [--] <command> [args...]")
+			}
+			statuses, err := parseExecStatuses(cmd.StringSlice("status"))
+			if err != nil {
+				return err
+			}
+
+			repos, err := resolveRepos(g, cmd.String("repo"))
+			if err != nil {
+				return err
+			}
+			targets := filterExecTargets(collectAllWorktrees(repos, g.Verbose), statuses, cmd.String("stack"))
+			if len(targets) == 0 {
+				u.Info("No worktrees match.")
+				return nil
+			}
+
+			mode := execPrefix
+			if cmd.Bool("group") {
+				mode = execGroup
+			}
+			if cmd.Bool("json") {
+				mode = execQuiet
+			}
+			results := runExec(ctx, targets, args, cmd.Int("jobs"), mode, os.Stdout, len(repos) > 1)
+
+			failed := 0
+			for _, r := range results {
+				if r.ExitCode != 0 {
+					failed++
+				}
+			}
+			if cmd.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else {
+				u.Info("")
+				for _, line := range formatExecSummary(u, results) {
+					u.Info(line)
+				}
+			}
+			if failed > 0 {
+				return errors.Userf("command failed in %d of %d worktrees", failed, len(results))
+			}
+			return nil
+		},
+	}
+}
+
+var execStatuses = []agent.Status{agent.StatusBusy, agent.StatusWait, agent.StatusDone, agent.StatusIdle}
+
+func parseExecStatuses(values []string) (map[agent.Status]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	out := map[agent.Status]bool{}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			s := agent.Status(strings.ToUpper(strings.TrimSpace(part)))
+			valid := false
+			for _, known := range execStatuses {
+				valid = valid || s == known
+			}
+			if !valid {
+				return nil, errors.Userf("unknown status %q (use BUSY, WAIT, DONE, or IDLE)", part)
+			}
+			out[s] = true
+		}
+	}
+	return out, nil
+}
+
+// filterExecTargets keeps worktrees with a session in one of statuses and,
+// when stackRoot is set, on stackRoot or one of its stacked descendants.
+func filterExecTargets(rwts []repoWorktree, statuses map[agent.Status]bool, stackRoot string) []repoWorktree {
+	stacks := map[string]map[string]bool{}
+	var out []repoWorktree
+	for _, rwt := range rwts {
+		if stackRoot != "" {
+			inStack, ok := stacks[rwt.Repo.BareDir]
+			if !ok {
+				inStack = map[string]bool{}
+				for _, b := range stack.Load(rwt.Repo.BareDir).SubtreeSort(stackRoot) {
+					inStack[b] = true
+				}
+				stacks[rwt.Repo.BareDir] = inStack
+			}
+			if rwt.Worktree.Detached || !inStack[rwt.Worktree.Branch] {
+				continue
+			}
+		}
+		if statuses != nil && !worktreeHasStatus(rwt, statuses) {
+			continue
+		}
+		out = append(out, rwt)
+	}
+	return out
+}
+
+func worktreeHasStatus(rwt repoWorktree, statuses map[agent.Status]bool) bool {
+	for _, ss := range agent.ReadAllSessions(rwt.Repo.Name, filepath.Base(rwt.Worktree.Path)) {
+		if statuses[agent.EffectiveStatus(ss.Status, ss.Timestamp)] {
+			return true
+		}
+	}
+	return false
+}
+
+type execMode int
+
+const (
+	execPrefix execMode = iota // stream output with a [worktree] prefix on every line
+	execGroup                  // print each worktree's output as a block when it finishes
+	execQuiet                  // only capture output
+)
+
+type execResult struct {
+	Repo       string `json:"repo"`
+	Worktree   string `json:"worktree"`
+	Path       string `json:"path"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	Output     string `json:"output"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (r execResult) label(withRepo bool) string {
+	if withRepo {
+		return r.Repo + "/" + r.Worktree
+	}
+	return r.Worktree
+}
+
+// runExec runs args in each target with at most jobs running at once. A
+// single argument runs through sh -c so it can use pipes and globs.
+func runExec(ctx context.Context, targets []repoWorktree, args []string, jobs int, mode execMode, out io.Writer, withRepo bool) []execResult {
+	var mu sync.Mutex
+	cfgs := map[string]*config.Config{}
+	for _, t := range targets {
+		if _, ok := cfgs[t.Repo.BareDir]; !ok {
+			cfgs[t.Repo.BareDir] = config.Load(t.Repo.BareDir)
+		}
+	}
+	portReg := ports.Load()
+
+	return parallel.MapLimit(targets, jobs, func(_ int, t repoWorktree) execResult {
+		res := execResult{Repo: t.Repo.Name, Worktree: t.Worktree.MatchName(), Path: t.Worktree.Path}
+		label := res.label(withRepo)
+
+		var c *exec.Cmd
+		if len(args) == 1 {
+			c = exec.CommandContext(ctx, "sh", "-c", args[0])
+		} else {
+			c = exec.CommandContext(ctx, args[0], args[1:]...)
+		}
+		c.Dir = t.Worktree.Path
+		cfg := withWorktreeProfile(cfgs[t.Repo.BareDir], t.Worktree.Path, nil)
+		c.Env = append(os.Environ(), existingWorktreeEnv(cfg, portReg, t.Worktree.Path)...)
+
+		var buf bytes.Buffer
+		var prefixed *prefixWriter
+		if mode == execPrefix {
+			prefixed = &prefixWriter{mu: &mu, out: out, prefix: "[" + label + "] "}
+			c.Stdout = io.MultiWriter(&buf, prefixed)
+		} else {
+			c.Stdout = &buf
+		}
+		c.Stderr = c.Stdout
+
+		start := time.Now()
+		err := c.Run()
+		res.DurationMS = time.Since(start).Milliseconds()
+		if prefixed != nil {
+			prefixed.Flush()
+		}
+		res.Output = buf.String()
+		if err != nil {
+			res.ExitCode = -1
+			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 {
+				res.ExitCode = exitErr.ExitCode()
+			} else {
+				res.Error = err.Error()
+			}
+		}
+
+		if mode == execGroup {
+			mu.Lock()
+			fmt.Fprintf(out, "── %s ──\n%s", label, res.Output)
+			if res.Output != "" && !strings.HasSuffix(res.Output, "\n") {
+				fmt.Fprintln(out)
+			}
+			mu.Unlock()
+		}
+		return res
+	})
+}
+
+// prefixWriter writes whole lines to out, each starting with prefix. Writers
+// sharing mu never interleave within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.mu.Lock()
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf[:i])
+		w.mu.Unlock()
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush writes a trailing partial line.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.mu.Lock()
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+	w.mu.Unlock()
+	w.buf = nil
+}
+
+func formatExecSummary(u *ui.UI, results []execResult) []string {
+	withRepo := false
+	for _, r := range results {
+		withRepo = withRepo || r.Repo != results[0].Repo
+	}
+	nameW := len("WORKTREE")
+	for _, r := range results {
+		nameW = max(nameW, termfmt.VisibleWidth(r.label(withRepo)))
+	}
+
+	lines := []string{u.Bold(fmt.Sprintf("   %-*s  %4s  %s", nameW, "WORKTREE", "EXIT", "TIME"))}
+	for _, r := range results {
+		icon := u.Green("✔")
+		if r.ExitCode != 0 {
+			icon = u.Red("✘")
+		}
+		elapsed := (time.Duration(r.DurationMS) * time.Millisecond).Round(100 * time.Millisecond)
+		line := fmt.Sprintf(" %s %-*s  %4d  %s", icon, nameW, r.label(withRepo), r.ExitCode, elapsed)
+		if r.Error != "" {
+			line += "  " + u.Dim(r.Error)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/ports"
+)
+
+func TestExecRunsInEachWorktree(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "execrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "execrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "execrepo")
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := runApp("new", "child", "--base", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new child: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("exec", "--", "sh", "-c", "basename $PWD") })
+	if err != nil {
+		t.Fatalf("exec: %v\n%s", err, out)
+	}
+	for _, want := range []string{"[feature] feature", "[child] child", "[" + mainBranch + "] " + mainBranch} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = captureStdout(t, func() error {
+		return runApp("exec", "--json", "--stack", "feature", "--", `test "$(basename $PWD)" = feature`)
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 worktrees") {
+		t.Fatalf("exec error = %v, want one failure of two", err)
+	}
+	var results []execResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("parse exec --json: %v\n%s", err, out)
+	}
+	exits := map[string]int{}
+	for _, r := range results {
+		exits[r.Worktree] = r.ExitCode
+	}
+	if len(exits) != 2 || exits["feature"] != 0 || exits["child"] != 1 {
+		t.Errorf("exit codes = %v, want feature 0 and child 1", exits)
+	}
+}
+
+func TestExecFiltersByAgentStatus(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "execrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "execrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "execrepo")
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	for _, name := range []string{"busy", "done"} {
+		if err := runApp("new", name, "--no-fetch"); err != nil {
+			t.Fatalf("new %s: %v", name, err)
+		}
+	}
+	writeActiveSessionFile(t, "execrepo", "busy", "s1", agent.StatusBusy)
+	writeActiveSessionFile(t, "execrepo", "done", "s2", agent.StatusDone)
+
+	out, err := captureStdout(t, func() error { return runApp("exec", "--status", "done", "--json", "--", "true") })
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	var results []execResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("parse exec --json: %v\n%s", err, out)
+	}
+	if len(results) != 1 || results[0].Worktree != "done" {
+		t.Errorf("results = %+v, want only done", results)
+	}
+
+	if err := runApp("exec", "--status", "sleeping", "--", "true"); err == nil || !strings.Contains(err.Error(), "unknown status") {
+		t.Errorf("exec --status sleeping error = %v", err)
+	}
+}
+
+func TestExecExportsExistingPortsWithoutAllocating(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "execports"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "execports.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "execports")
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "plain", "--no-fetch"); err != nil {
+		t.Fatalf("new plain: %v", err)
+	}
+	cfg := `{"ports": {"count": 1, "start": 47100}}`
+	if err := os.WriteFile(filepath.Join(bareDir, "willow.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runApp("new", "served", "--no-fetch"); err != nil {
+		t.Fatalf("new served: %v", err)
+	}
+	served, ok := ports.Load().Lookup(filepath.Join(wtRoot, "served"))
+	if !ok {
+		t.Fatal("served has no port allocation")
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("exec", "--", `echo "port=$WILLOW_PORT"`) })
+	if err != nil {
+		t.Fatalf("exec: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, fmt.Sprintf("[served] port=%d", served.Base)) || !strings.Contains(out, "[plain] port=\n") {
+		t.Errorf("exec output:\n%s", out)
+	}
+	if n := len(ports.Load().Allocations); n != 1 {
+		t.Errorf("exec left %d port allocations, want 1", n)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/iamrajjoshi/willow/internal/cleanup"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/stack"
 	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
 	"github.com/iamrajjoshi/willow/internal/worktree"
 	"github.com/urfave/cli/v3"
 )
@@ -23,6 +26,11 @@ func gcCmd() *cli.Command {
 				Name:  "prune",
 				Usage: "Interactively remove safe stale worktrees",
 			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Remove without prompting (with --prune), e.g. from cron",
+			},
 			&cli.BoolFlag{
 				Name:  "dry-run",
 				Usage: "Show what would be cleaned up without removing anything",
@@ -44,26 +52,14 @@ func gcCmd() *cli.Command {
 			dryRun := cmd.Bool("dry-run")
 			prune := cmd.Bool("prune")
 
-			trashDir := config.TrashDir()
-			entries, err := os.ReadDir(trashDir)
-			if err != nil {
-				if os.IsNotExist(err) {
-					u.Info("No trash to clean up.")
-				} else {
-					return fmt.Errorf("failed to read trash dir: %w", err)
-				}
-			} else if len(entries) == 0 {
-				u.Info("No trash to clean up.")
-			} else if dryRun {
-				u.Info(fmt.Sprintf("Would clean up %d trash entries", len(entries)))
-			} else {
-				for _, e := range entries {
-					path := filepath.Join(trashDir, e.Name())
-					if err := os.RemoveAll(path); err != nil {
-						u.Warn(fmt.Sprintf("Failed to remove %s: %v", e.Name(), err))
-					}
+			globalGC := config.Load("").GC
+			if err := gcTrash(u, globalGC, dryRun); err != nil {
+				return err
+			}
+			if globalGC.SessionDays > 0 {
+				if err := pruneSessions(u, time.Now().AddDate(0, 0, -globalGC.SessionDays), dryRun); err != nil {
+					return err
 				}
-				u.Success(fmt.Sprintf("Cleaned up %d trash entries", len(entries)))
 			}
 
 			repos, err := gcRepos(cmd.String("repo"))
@@ -71,15 +67,24 @@ func gcCmd() *cli.Command {
 				return err
 			}
 			var candidates []cleanup.Candidate
+			var reparents []gcReparent
+			planned := make(map[string]*stack.Stack)
 			for _, repo := range repos {
 				cfg := config.Load(repo.BareDir)
-				repoGit := &git.Git{Dir: repo.BareDir, Verbose: flags.Verbose}
+				repoGit := &git.Git{Dir: repo.BareDir, Verbose: flags.Verbose, Remote: cfg.Upstream()}
 				if *cfg.Defaults.Fetch && !cmd.Bool("no-fetch") {
-					if _, err := repoGit.Run("fetch", "--no-tags", "--prune", "origin"); err != nil {
+					if _, err := repoGit.Run(fetchRemotesArgs(cfg, "--no-tags", "--prune")...); err != nil {
 						u.Warn(fmt.Sprintf("Skipping remote refresh for %s: %v", repo.Name, err))
 					}
 				}
 
+				if prune {
+					if r, ok := planGCReparent(repoGit, repo.BareDir, u); ok {
+						reparents = append(reparents, r)
+						planned[repo.BareDir] = r.stack
+					}
+				}
+
 				repoCandidates, err := cleanup.ScanRepo(repo.Name, repo.BareDir, cleanup.ScanOptions{
 					RefreshPRState: true,
 					Verbose:        flags.Verbose,
@@ -91,13 +96,17 @@ func gcCmd() *cli.Command {
 				candidates = append(candidates, repoCandidates...)
 			}
 
-			if len(candidates) == 0 {
+			if len(candidates) == 0 && len(reparents) == 0 {
 				u.Info("No stale worktrees found.")
 				return nil
 			}
 
 			multiRepo := cleanup.HasMultipleRepos(candidates)
-			u.Info(fmt.Sprintf("\nFound %d stale worktree(s):", len(candidates)))
+			if len(candidates) == 0 {
+				u.Info("No stale worktrees found.")
+			} else {
+				u.Info(fmt.Sprintf("\nFound %d stale worktree(s):", len(candidates)))
+			}
 			for _, c := range candidates {
 				u.Info(fmt.Sprintf("  %s (%s)", cleanup.Label(c, multiRepo), c.ReasonString()))
 			}
@@ -105,13 +114,15 @@ func gcCmd() *cli.Command {
 			if !prune {
 				u.Info("\nTo remove these, run:")
 				for _, c := range candidates {
-					u.Info(fmt.Sprintf("  willow rm %s --repo %s", c.Branch, c.RepoName))
+					u.Info(fmt.Sprintf("  willow rm %s --repo %s", c.Name(), c.RepoName))
 				}
 				u.Info("\nOr re-run with --prune to interactively remove the safe subset.")
 				return nil
 			}
 
-			safe, skipped, err := cleanup.FilterSafe(candidates)
+			// Judge candidates against the stacks as they will be after
+			// reparenting, so merged parents don't count as having children.
+			safe, skipped, err := cleanup.FilterSafeWithStacks(candidates, planned)
 			if err != nil {
 				return err
 			}
@@ -121,37 +132,68 @@ func gcCmd() *cli.Command {
 					u.Info(fmt.Sprintf("  %s (%s)", cleanup.Label(skip.Candidate, multiRepo), skip.Reason))
 				}
 			}
+			moves := 0
+			if len(reparents) > 0 && !dryRun {
+				u.Info("\nStacked branches to reparent and restack:")
+			}
+			for _, r := range reparents {
+				for _, m := range r.moved {
+					if dryRun {
+						u.Info(fmt.Sprintf("Would reparent %s: %s merged, move onto %s", m.Branch, m.From, m.To))
+					} else {
+						u.Info(fmt.Sprintf("  %s: %s merged, move onto %s", m.Branch, m.From, m.To))
+					}
+					moves++
+				}
+			}
 
 			if dryRun {
 				u.Info(fmt.Sprintf("\nDry run: would remove %d safe stale worktree(s).", len(safe)))
 				return nil
 			}
 
-			if len(safe) == 0 {
+			if len(safe) == 0 && moves == 0 {
 				u.Info("\nNo safe stale worktrees to remove.")
 				return nil
 			}
 
-			fmt.Fprintf(os.Stderr, "\nRemove %d safe stale worktree(s)? [y/N] ", len(safe))
-			var answer string
-			fmt.Fscanf(os.Stdin, "%s", &answer)
-			if answer != "y" && answer != "Y" {
-				u.Info("Aborted.")
-				return nil
+			if !cmd.Bool("yes") {
+				var actions []string
+				if moves > 0 {
+					actions = append(actions, fmt.Sprintf("reparent %d stacked branch(es)", moves))
+				}
+				if len(safe) > 0 {
+					actions = append(actions, fmt.Sprintf("remove %d safe stale worktree(s)", len(safe)))
+				}
+				prompt := strings.Join(actions, " and ")
+				fmt.Fprintf(os.Stderr, "\n%s? [y/N] ", strings.ToUpper(prompt[:1])+prompt[1:])
+				var answer string
+				fmt.Fscanf(os.Stdin, "%s", &answer)
+				if answer != "y" && answer != "Y" {
+					u.Info("Aborted.")
+					return nil
+				}
+			}
+
+			// Reparent before removing: the restack rebases children with the
+			// merged parent as upstream, so its branch must still exist.
+			for _, r := range reparents {
+				reparentAndRestack(r.repoGit, r.bareDir, u)
 			}
 
 			tr := trace.FromContext(ctx)
 			for _, c := range safe {
-				u.Info(fmt.Sprintf("Removing %s from %s...", c.Branch, c.RepoName))
+				u.Info(fmt.Sprintf("Removing %s from %s...", c.Name(), c.RepoName))
 				repoGit := &git.Git{Dir: c.BareDir, Verbose: flags.Verbose}
 				cfg := config.Load(c.BareDir)
 				wt := worktree.Worktree{
-					Branch: c.Branch,
-					Path:   c.Path,
-					Head:   c.Head,
+					Branch:   c.Branch,
+					Path:     c.Path,
+					Head:     c.Head,
+					Detached: c.Detached,
 				}
 				if err := removeWorktree(ctx, tr, u, repoGit, &wt, c.BareDir, cfg, true, false, flags.Verbose); err != nil {
-					u.Warn(fmt.Sprintf("Failed to remove %s: %v", c.Branch, err))
+					u.Warn(fmt.Sprintf("Failed to remove %s: %v", c.Name(), err))
 				}
 			}
 
@@ -160,6 +202,79 @@ func gcCmd() *cli.Command {
 	}
 }
 
+// gcReparent is a planned move of children off merged stack parents in one
+// repo, shown with the stale candidates and applied only once confirmed.
+type gcReparent struct {
+	repoGit *git.Git
+	bareDir string
+	moved   []reparentedBranch
+	stack   *stack.Stack
+}
+
+// planGCReparent finds children of merged stack parents without changing
+// anything. The returned stack has the moves applied in memory only.
+func planGCReparent(repoGit *git.Git, bareDir string, u *ui.UI) (gcReparent, bool) {
+	st := stack.Load(bareDir)
+	if st.IsEmpty() {
+		return gcReparent{}, false
+	}
+	moved, err := reparentMergedParents(repoGit, bareDir, st, reparentOptions{RefreshPRState: true, DryRun: true}, u)
+	if err != nil {
+		u.Warn(fmt.Sprintf("Skipping reparent for %s: %v", repoNameFromDir(bareDir), err))
+		return gcReparent{}, false
+	}
+	if len(moved) == 0 {
+		return gcReparent{}, false
+	}
+	for _, m := range moved {
+		st.SetParent(m.Branch, m.To)
+	}
+	return gcReparent{repoGit: repoGit, bareDir: bareDir, moved: moved, stack: st}, true
+}
+
+// gcTrash empties the trash, or with a trash retention policy configured,
+// removes only the entries that are too old or push it over its size
+// budget.
+func gcTrash(u *ui.UI, policy config.GCConfig, dryRun bool) error {
+	entries, err := cleanup.ScanTrash(config.TrashDir())
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		u.Info("No trash to clean up.")
+		return nil
+	}
+
+	if policy.HasTrashPolicy() {
+		entries = cleanup.ExpiredTrash(entries, policy, time.Now())
+		if len(entries) == 0 {
+			u.Info("No trash past its retention policy.")
+			return nil
+		}
+	}
+
+	if dryRun {
+		u.Info(fmt.Sprintf("Would clean up %d trash entries", len(entries)))
+		for _, e := range entries {
+			if len(e.Reasons) > 0 {
+				u.Info(fmt.Sprintf("  %s (%s)", e.Name, cleanup.ReasonsString(e.Reasons)))
+			}
+		}
+		return nil
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if err := os.RemoveAll(e.Path); err != nil {
+			u.Warn(fmt.Sprintf("Failed to remove %s: %v", e.Name, err))
+			continue
+		}
+		removed++
+	}
+	u.Success(fmt.Sprintf("Cleaned up %d trash entries", removed))
+	return nil
+}
+
 func gcRepos(repoFlag string) ([]repoInfo, error) {
 	if repoFlag != "" {
 		bareDir, err := config.ResolveRepo(repoFlag)
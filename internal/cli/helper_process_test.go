@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,9 +10,17 @@ import (
 )
 
 func TestMain(m *testing.M) {
-	if os.Getenv("WILLOW_TEST_HELPER_PROCESS") == "willow" {
+	switch os.Getenv("WILLOW_TEST_HELPER_PROCESS") {
+	case "willow":
 		willowTestHelperProcess()
 		return
+	case "app":
+		// Run the real CLI so subprocesses exercise actual git.
+		if err := NewApp().Run(context.Background(), os.Args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
 	os.Exit(m.Run())
 }
@@ -2145,6 +2145,87 @@ func TestGcPruneDeletesOnlySafeGoneUpstreamWorktrees(t *testing.T) {
 	}
 }
 
+func TestGcPruneYesAppliesRetentionPolicies(t *testing.T) {
+	origin := setupTestEnv(t)
+	home, _ := os.UserHomeDir()
+
+	if err := runApp("clone", origin, "gcpolicy"); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "gcpolicy.git")
+	if err := config.Save(&config.Config{GC: config.GCConfig{InactiveDays: 30, DetachedDays: 30}}, config.LocalConfigPath(bareDir)); err != nil {
+		t.Fatalf("save repo config: %v", err)
+	}
+	if err := config.Save(&config.Config{GC: config.GCConfig{TrashDays: 7}}, config.GlobalConfigPath()); err != nil {
+		t.Fatalf("save global config: %v", err)
+	}
+
+	worktreeRoot := filepath.Join(home, ".willow", "worktrees", "gcpolicy")
+	mainDir := filepath.Join(worktreeRoot, firstWorktreeDir(t, worktreeRoot))
+	os.Chdir(mainDir)
+	old := time.Now().AddDate(0, 0, -60)
+	t.Setenv("GIT_COMMITTER_DATE", old.Format(time.RFC3339))
+
+	var paths []string
+	for _, branch := range []string{"old-pushed", "old-unpushed"} {
+		if err := runApp("new", branch, "--no-fetch"); err != nil {
+			t.Fatalf("new %s: %v", branch, err)
+		}
+		wtPath := filepath.Join(worktreeRoot, branch)
+		commitFile(t, wtPath, branch+".txt", branch+"\n", "add "+branch)
+		paths = append(paths, wtPath)
+	}
+	if _, err := (&git.Git{Dir: paths[0]}).Run("push", "-u", "origin", "old-pushed"); err != nil {
+		t.Fatalf("push old-pushed: %v", err)
+	}
+	if err := runApp("new", "old-detached", "--detach", "--ref", "old-pushed", "--no-fetch"); err != nil {
+		t.Fatalf("new --detach: %v", err)
+	}
+	paths = append(paths, filepath.Join(worktreeRoot, "old-detached"))
+	for _, wtPath := range paths {
+		gitDir, err := (&git.Git{Dir: wtPath}).Run("rev-parse", "--absolute-git-dir")
+		if err != nil {
+			t.Fatalf("rev-parse --absolute-git-dir: %v", err)
+		}
+		if err := os.Chtimes(filepath.Join(gitDir, "index"), old, old); err != nil {
+			t.Fatalf("age index: %v", err)
+		}
+	}
+
+	trashDir := config.TrashDir()
+	oldTrash := filepath.Join(trashDir, fmt.Sprintf("%d-stale", old.UnixNano()))
+	newTrash := filepath.Join(trashDir, fmt.Sprintf("%d-recent", time.Now().UnixNano()))
+	for _, dir := range []string{oldTrash, newTrash} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runApp("gc", "--repo", "gcpolicy", "--prune", "--yes", "--no-fetch")
+	})
+	if err != nil {
+		t.Fatalf("gc --prune --yes failed: %v", err)
+	}
+	for _, want := range []string{"old-pushed (inactive)", "old-detached (stale-detached)", "old-unpushed (unpushed commits)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("gc output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, filepath.Base(mainDir)+" (inactive)") {
+		t.Errorf("base branch worktree should never be inactive:\n%s", out)
+	}
+	if pathExists(paths[0]) || pathExists(paths[2]) {
+		t.Error("inactive pushed and stale detached worktrees should be removed")
+	}
+	if !pathExists(paths[1]) {
+		t.Error("inactive worktree with unpushed commits should remain")
+	}
+	if pathExists(oldTrash) || !pathExists(newTrash) {
+		t.Error("only trash older than trashDays should be removed")
+	}
+}
+
 func createGoneUpstreamWorktree(t *testing.T, home, origin, repoName, branch string, mergeToBase, dirty bool) string {
 	t.Helper()
 
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/localfiles"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/worktree"
+)
+
+// applyLocalFiles copies and symlinks the untracked files matched by the
+// copy and symlink config into a new worktree. Failures only warn: a missing
+// .env should not undo the worktree.
+func applyLocalFiles(cfg *config.Config, u *ui.UI, wtPath string, verbose bool) {
+	if len(cfg.Copy) == 0 && len(cfg.Symlink) == 0 {
+		return
+	}
+	src, err := localFilesSource(cfg, &git.Git{Dir: wtPath, Verbose: verbose})
+	if err != nil {
+		u.Warn(fmt.Sprintf("Skipping copy/symlink: %v", err))
+		return
+	}
+	if src == wtPath {
+		return
+	}
+
+	res, err := localfiles.Apply(src, wtPath, cfg.Copy, cfg.Symlink)
+	if n := len(res.Copied) + len(res.Linked); n > 0 {
+		u.Info(fmt.Sprintf("Copied %d and linked %d local paths from %s", len(res.Copied), len(res.Linked), filepath.Base(src)))
+	}
+	if verbose {
+		for _, rel := range res.Skipped {
+			u.Info(u.Dim(fmt.Sprintf("  skipped %s (already in worktree)", rel)))
+		}
+	}
+	if err != nil {
+		u.Warn(fmt.Sprintf("Some local files were not applied:\n%v", err))
+	}
+}
+
+// localFilesSource returns the worktree named by copyFrom, or else the one
+// on the repo's base branch.
+func localFilesSource(cfg *config.Config, wtGit *git.Git) (string, error) {
+	bareDir, err := wtGit.BareRepoDir()
+	if err != nil {
+		return "", err
+	}
+	repoGit := &git.Git{Dir: bareDir, Verbose: wtGit.Verbose}
+	wts, err := worktree.List(repoGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	wts = filterBareWorktrees(wts)
+
+	if cfg.CopyFrom != "" {
+		wt, err := findWorktree(wts, cfg.CopyFrom)
+		if err != nil {
+			return "", fmt.Errorf("copyFrom: %w", err)
+		}
+		return wt.Path, nil
+	}
+	base := repoGit.ResolveBaseBranch(cfg.BaseBranch)
+	for _, wt := range wts {
+		if wt.Branch == base {
+			return wt.Path, nil
+		}
+	}
+	return "", fmt.Errorf("no worktree on %s to copy from (set copyFrom)", base)
+}
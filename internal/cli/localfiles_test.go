@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestNewCopiesAndLinksLocalFilesBeforeSetup(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "filesrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "filesrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "filesrepo")
+	mainDir := filepath.Join(wtRoot, mainBranch)
+
+	if err := os.WriteFile(filepath.Join(mainDir, ".env"), []byte("TOKEN=main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(mainDir, ".cache", "deps"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"copy": [".env*"], "symlink": [".cache"], "setup": ["cp .env setup-saw-env"]}`
+	if err := os.WriteFile(filepath.Join(bareDir, "willow.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chdir(mainDir)
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	if data, err := os.ReadFile(filepath.Join(featureDir, ".env")); err != nil || string(data) != "TOKEN=main\n" {
+		t.Errorf(".env = %q, %v", data, err)
+	}
+	if !pathExists(filepath.Join(featureDir, "setup-saw-env")) {
+		t.Error("setup ran before .env was copied")
+	}
+	if link, err := os.Readlink(filepath.Join(featureDir, ".cache")); err != nil || link != filepath.Join(mainDir, ".cache") {
+		t.Errorf(".cache link = %q, %v", link, err)
+	}
+
+	// copyFrom takes files from another worktree instead.
+	if err := os.WriteFile(filepath.Join(featureDir, ".env"), []byte("TOKEN=feature\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg = `{"copy": [".env"], "copyFrom": "feature"}`
+	if err := os.WriteFile(filepath.Join(bareDir, "willow.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runApp("new", "other", "--no-fetch"); err != nil {
+		t.Fatalf("new other: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wtRoot, "other", ".env")); string(data) != "TOKEN=feature\n" {
+		t.Errorf("copyFrom .env = %q, want the feature worktree's", data)
+	}
+}
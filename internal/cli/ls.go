@@ -99,7 +99,7 @@ func listWorktrees(ctx context.Context, flags Flags, cmd *cli.Command, repoGit *
 	if cmd.Bool("json") {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(filtered)
+		return enc.Encode(lsJSONRows(filtered))
 	}
 
 	repoName := repoNameFromDir(repoGit.Dir)
@@ -107,6 +107,23 @@ func listWorktrees(ctx context.Context, flags Flags, cmd *cli.Command, repoGit *
 	return nil
 }
 
+type lsJSONRow struct {
+	worktree.Worktree
+	Ports []int `json:"ports,omitempty"`
+}
+
+func lsJSONRows(worktrees []worktree.Worktree) []lsJSONRow {
+	allocated := worktreePorts()
+	rows := make([]lsJSONRow, len(worktrees))
+	for i, wt := range worktrees {
+		rows[i] = lsJSONRow{Worktree: wt}
+		if a, ok := allocated[wt.Path]; ok {
+			rows[i].Ports = a.Ports()
+		}
+	}
+	return rows
+}
+
 type repoListRow struct {
 	repo        string
 	count       int
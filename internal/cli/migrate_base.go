@@ -284,7 +284,7 @@ func discoverMigrateRepos(sourceBase, destBase string, verbose bool) ([]migrateR
 }
 
 func executeMigrateBasePlan(plan *migrateBasePlan, verbose bool) error {
-	if err := moveWillowBase(plan.SourceBase, plan.DestBase); err != nil {
+	if err := moveDir(plan.SourceBase, plan.DestBase); err != nil {
 		return err
 	}
 
@@ -386,24 +386,26 @@ func saveMigratedBaseDir(destBase string) error {
 	return config.Save(cfg, config.GlobalConfigPath())
 }
 
-func moveWillowBase(sourceBase, destBase string) error {
-	if err := os.MkdirAll(filepath.Dir(destBase), 0o755); err != nil {
+// moveDir renames source to dest, falling back to copy-and-delete when they
+// are on different filesystems.
+func moveDir(source, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
 		return err
 	}
 
-	destExists := pathExists(destBase)
+	destExists := pathExists(dest)
 	if !destExists {
-		if err := os.Rename(sourceBase, destBase); err == nil {
+		if err := os.Rename(source, dest); err == nil {
 			return nil
 		} else if !isCrossDeviceError(err) {
 			return err
 		}
 	}
 
-	if err := copyDir(sourceBase, destBase); err != nil {
+	if err := copyDir(source, dest); err != nil {
 		return err
 	}
-	return os.RemoveAll(sourceBase)
+	return os.RemoveAll(source)
 }
 
 func isCrossDeviceError(err error) bool {
@@ -93,7 +93,7 @@ func TestCopyFileCopiesContentsAndMode(t *testing.T) {
 	}
 }
 
-func TestMoveWillowBaseCopiesWhenDestinationExists(t *testing.T) {
+func TestMoveDirCopiesWhenDestinationExists(t *testing.T) {
 	source := filepath.Join(t.TempDir(), "source")
 	dest := filepath.Join(t.TempDir(), "dest")
 	if err := os.MkdirAll(source, 0o755); err != nil {
@@ -106,8 +106,8 @@ func TestMoveWillowBaseCopiesWhenDestinationExists(t *testing.T) {
 		t.Fatalf("mkdir dest: %v", err)
 	}
 
-	if err := moveWillowBase(source, dest); err != nil {
-		t.Fatalf("moveWillowBase: %v", err)
+	if err := moveDir(source, dest); err != nil {
+		t.Fatalf("moveDir: %v", err)
 	}
 	if _, err := os.Stat(source); !os.IsNotExist(err) {
 		t.Fatalf("source should be removed, stat err = %v", err)
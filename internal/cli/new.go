@@ -14,6 +14,7 @@ import (
 	"github.com/iamrajjoshi/willow/internal/fzf"
 	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/ports"
 	"github.com/iamrajjoshi/willow/internal/stack"
 	"github.com/iamrajjoshi/willow/internal/trace"
 	"github.com/iamrajjoshi/willow/internal/ui"
@@ -33,11 +34,16 @@ func detachedWorktreeDirName(name string) (string, error) {
 	return dirName, nil
 }
 
-func runHooks(commands []string, dir string, u *ui.UI, stdout *os.File) error {
+// runHooks runs each command with sh in dir, adding env to the inherited
+// environment.
+func runHooks(commands []string, dir string, env []string, u *ui.UI, stdout *os.File) error {
 	for _, c := range commands {
 		u.Info(fmt.Sprintf("  → %s", c))
 		sh := exec.Command("sh", "-c", c)
 		sh.Dir = dir
+		if len(env) > 0 {
+			sh.Env = append(os.Environ(), env...)
+		}
 		sh.Stdout = stdout
 		sh.Stderr = os.Stderr
 		if err := sh.Run(); err != nil {
@@ -109,7 +115,8 @@ func resolveDetachedRef(ctx context.Context, tr *trace.Tracer, cmd *cli.Command,
 	done()
 
 	localBase := explicitBase && repoGit.LocalBranchExists(baseBranch)
-	gitRef := "origin/" + baseBranch
+	upstream := cfg.Upstream()
+	gitRef := upstream + "/" + baseBranch
 	if localBase {
 		gitRef = baseBranch
 	}
@@ -118,18 +125,18 @@ func resolveDetachedRef(ctx context.Context, tr *trace.Tracer, cmd *cli.Command,
 	if shouldFetch {
 		done = tr.StartCtx(ctx, "git fetch detached ref")
 		if cdOnly {
-			fmt.Fprintf(os.Stderr, "Fetching %s from origin...\n", baseBranch)
-			if _, err := repoGit.RunStream(os.Stderr, "fetch", "--no-tags", "--progress", "origin", baseBranch); err != nil {
+			fmt.Fprintf(os.Stderr, "Fetching %s from %s...\n", baseBranch, upstream)
+			if _, err := repoGit.RunStream(os.Stderr, fetchArgs(cfg, "--no-tags", "--progress", upstream, baseBranch)...); err != nil {
 				done()
-				return "", "", fmt.Errorf("failed to fetch origin/%s: %w", baseBranch, err)
+				return "", "", fmt.Errorf("failed to fetch %s: %w", gitRef, err)
 			}
 		} else {
-			if err := u.Spin(fmt.Sprintf("Fetching %s from origin", u.Bold(baseBranch)), func() error {
-				_, err := repoGit.Run("fetch", "--no-tags", "origin", baseBranch)
+			if err := u.Spin(fmt.Sprintf("Fetching %s from %s", u.Bold(baseBranch), upstream), func() error {
+				_, err := repoGit.Run(fetchArgs(cfg, "--no-tags", upstream, baseBranch)...)
 				return err
 			}); err != nil {
 				done()
-				return "", "", fmt.Errorf("failed to fetch origin/%s: %w", baseBranch, err)
+				return "", "", fmt.Errorf("failed to fetch %s: %w", gitRef, err)
 			}
 		}
 		done()
@@ -186,6 +193,11 @@ func newCmd() *cli.Command {
 				Name:  "pr",
 				Usage: "GitHub PR number or URL",
 			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Aliases: []string{"p"},
+				Usage:   "Create the worktree from a named profile in config",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			flags := parseFlags(cmd)
@@ -216,7 +228,10 @@ func newCmd() *cli.Command {
 			done()
 
 			done = tr.StartCtx(ctx, "load config")
-			cfg := config.Load(bareDir)
+			cfg, err := applyProfileFlag(config.Load(bareDir), cmd.String("profile"))
+			if err != nil {
+				return err
+			}
 			done()
 
 			repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose}
@@ -262,12 +277,12 @@ func newCmd() *cli.Command {
 				done = tr.StartCtx(ctx, "git worktree add detached")
 				if cdOnly {
 					fmt.Fprintf(os.Stderr, "Creating detached worktree %s at %s...\n", name, refLabel)
-					if _, err := repoGit.RunStream(os.Stderr, "worktree", "add", "--detach", wtPath, ref); err != nil {
+					if err := addWorktree(repoGit, cfg, os.Stderr, wtPath, "--detach", wtPath, ref); err != nil {
 						return fmt.Errorf("failed to create detached worktree: %w", err)
 					}
 				} else {
 					u.Info(fmt.Sprintf("Creating detached worktree %s at %s...", u.Bold(name), u.Bold(refLabel)))
-					if _, err := repoGit.Run("worktree", "add", "--detach", wtPath, ref); err != nil {
+					if err := addWorktree(repoGit, cfg, nil, wtPath, "--detach", wtPath, ref); err != nil {
 						return fmt.Errorf("failed to create detached worktree: %w", err)
 					}
 				}
@@ -311,15 +326,15 @@ func newCmd() *cli.Command {
 			if existing && branch == "" {
 				shouldFetch := *cfg.Defaults.Fetch && !cmd.Bool("no-fetch")
 				if shouldFetch {
-					if err := u.Spin("Fetching latest branches from origin", func() error {
-						_, err := repoGit.Run("fetch", "--no-tags", "origin")
+					if err := u.Spin("Fetching latest branches", func() error {
+						_, err := repoGit.Run(fetchRemotesArgs(cfg, "--no-tags")...)
 						return err
 					}); err != nil {
 						u.Warn(fmt.Sprintf("Failed to fetch: %v", err))
 					}
 				}
 				done = tr.StartCtx(ctx, "pick existing branch")
-				branch, err = pickExistingBranch(repoGit)
+				branch, err = pickExistingBranch(repoGit, cfg)
 				if err != nil {
 					return err
 				}
@@ -338,16 +353,15 @@ func newCmd() *cli.Command {
 				if shouldFetch {
 					done = tr.StartCtx(ctx, "git fetch branch")
 					if cdOnly {
-						fmt.Fprintf(os.Stderr, "Fetching %s from origin...\n", branch)
-						if _, err := repoGit.RunStream(os.Stderr, "fetch", "--no-tags", "--progress", "origin", branch); err != nil {
-							return fmt.Errorf("failed to fetch origin/%s: %w", branch, err)
+						fmt.Fprintf(os.Stderr, "Fetching %s...\n", branch)
+						if err := fetchBranch(repoGit, cfg, os.Stderr, branch); err != nil {
+							return fmt.Errorf("failed to fetch %s: %w", branch, err)
 						}
 					} else {
-						if err := u.Spin(fmt.Sprintf("Fetching %s from origin", u.Bold(branch)), func() error {
-							_, err := repoGit.Run("fetch", "--no-tags", "origin", branch)
-							return err
+						if err := u.Spin(fmt.Sprintf("Fetching %s", u.Bold(branch)), func() error {
+							return fetchBranch(repoGit, cfg, nil, branch)
 						}); err != nil {
-							return fmt.Errorf("failed to fetch origin/%s: %w", branch, err)
+							return fmt.Errorf("failed to fetch %s: %w", branch, err)
 						}
 					}
 					done()
@@ -359,12 +373,12 @@ func newCmd() *cli.Command {
 				done = tr.StartCtx(ctx, "git worktree add")
 				if cdOnly {
 					fmt.Fprintf(os.Stderr, "Creating worktree for existing branch %s...\n", branch)
-					if _, err := repoGit.RunStream(os.Stderr, "worktree", "add", wtPath, branch); err != nil {
+					if err := addWorktree(repoGit, cfg, os.Stderr, wtPath, existingBranchArgs(repoGit, cfg, wtPath, branch)...); err != nil {
 						return fmt.Errorf("failed to create worktree: %w", err)
 					}
 				} else {
 					u.Info(fmt.Sprintf("Creating worktree for existing branch %s...", u.Bold(branch)))
-					if _, err := repoGit.Run("worktree", "add", wtPath, branch); err != nil {
+					if err := addWorktree(repoGit, cfg, nil, wtPath, existingBranchArgs(repoGit, cfg, wtPath, branch)...); err != nil {
 						return fmt.Errorf("failed to create worktree: %w", err)
 					}
 				}
@@ -392,9 +406,11 @@ func newCmd() *cli.Command {
 			done()
 
 			// Only explicit --base can select a local stack parent. Auto-detected
-			// bases use origin/<base> because bare repos expose branch refs locally.
+			// bases use <upstream>/<base> because bare repos expose branch refs
+			// locally.
 			localBase := explicitBase && repoGit.LocalBranchExists(baseBranch)
-			gitRef := "origin/" + baseBranch
+			upstream := cfg.Upstream()
+			gitRef := upstream + "/" + baseBranch
 			if localBase {
 				gitRef = baseBranch
 			}
@@ -403,16 +419,16 @@ func newCmd() *cli.Command {
 			if shouldFetch {
 				done = tr.StartCtx(ctx, "git fetch")
 				if cdOnly {
-					fmt.Fprintf(os.Stderr, "Fetching %s from origin...\n", baseBranch)
-					if _, err := repoGit.RunStream(os.Stderr, "fetch", "--no-tags", "--progress", "origin", baseBranch); err != nil {
-						return fmt.Errorf("failed to fetch origin/%s: %w", baseBranch, err)
+					fmt.Fprintf(os.Stderr, "Fetching %s from %s...\n", baseBranch, upstream)
+					if _, err := repoGit.RunStream(os.Stderr, fetchArgs(cfg, "--no-tags", "--progress", upstream, baseBranch)...); err != nil {
+						return fmt.Errorf("failed to fetch %s: %w", gitRef, err)
 					}
 				} else {
-					if err := u.Spin(fmt.Sprintf("Fetching %s from origin", u.Bold(baseBranch)), func() error {
-						_, err := repoGit.Run("fetch", "--no-tags", "origin", baseBranch)
+					if err := u.Spin(fmt.Sprintf("Fetching %s from %s", u.Bold(baseBranch), upstream), func() error {
+						_, err := repoGit.Run(fetchArgs(cfg, "--no-tags", upstream, baseBranch)...)
 						return err
 					}); err != nil {
-						return fmt.Errorf("failed to fetch origin/%s: %w", baseBranch, err)
+						return fmt.Errorf("failed to fetch %s: %w", gitRef, err)
 					}
 				}
 				done()
@@ -424,12 +440,12 @@ func newCmd() *cli.Command {
 			done = tr.StartCtx(ctx, "git worktree add")
 			if cdOnly {
 				fmt.Fprintf(os.Stderr, "Creating worktree %s from %s...\n", branch, gitRef)
-				if _, err := repoGit.RunStream(os.Stderr, "worktree", "add", wtPath, "-b", branch, gitRef); err != nil {
+				if err := addWorktree(repoGit, cfg, os.Stderr, wtPath, wtPath, "-b", branch, gitRef); err != nil {
 					return fmt.Errorf("failed to create worktree: %w", err)
 				}
 			} else {
 				u.Info(fmt.Sprintf("Creating worktree %s from %s...", u.Bold(branch), u.Bold(gitRef)))
-				if _, err := repoGit.Run("worktree", "add", wtPath, "-b", branch, gitRef); err != nil {
+				if err := addWorktree(repoGit, cfg, nil, wtPath, wtPath, "-b", branch, gitRef); err != nil {
 					return fmt.Errorf("failed to create worktree: %w", err)
 				}
 			}
@@ -463,6 +479,12 @@ func finishDetachedWorktree(ctx context.Context, tr *trace.Tracer, cfg *config.C
 }
 
 func finishWorktreeWithOptions(ctx context.Context, tr *trace.Tracer, cfg *config.Config, g *git.Git, u *ui.UI, wtPath, repoName, label string, opts finishWorktreeOptions, cdOnly bool) error {
+	if cfg.Profile != "" {
+		if err := saveWorktreeProfile(wtPath, cfg.Profile); err != nil {
+			u.Warn(fmt.Sprintf("Failed to record profile %s: %v", cfg.Profile, err))
+		}
+	}
+
 	done := tr.StartCtx(ctx, "post-checkout hook")
 	runPostCheckoutHook(cfg.PostCheckoutHook, wtPath, u, cdOnly)
 	done()
@@ -476,15 +498,20 @@ func finishWorktreeWithOptions(ctx context.Context, tr *trace.Tracer, cfg *confi
 	}
 	done()
 
+	done = tr.StartCtx(ctx, "local files")
+	applyLocalFiles(cfg, u, wtPath, g.Verbose)
+	done()
+
 	hookOut := os.Stdout
 	if cdOnly {
 		hookOut = os.Stderr
 	}
+	env := worktreeEnv(cfg, wtPath, u)
 
 	done = tr.StartCtx(ctx, "setup hooks")
 	if len(cfg.Setup) > 0 {
 		u.Info("Running setup hooks...")
-		if err := runHooks(cfg.Setup, wtPath, u, hookOut); err != nil {
+		if err := runSetup(cfg, u, wtPath, env, hookOut, false); err != nil {
 			return err
 		}
 	}
@@ -499,7 +526,7 @@ func finishWorktreeWithOptions(ctx context.Context, tr *trace.Tracer, cfg *confi
 	if len(cfg.Tmux.Panes) > 0 && !cdOnly {
 		for i, p := range cfg.Tmux.Panes {
 			if p.Command != "" {
-				if err := runHooks([]string{p.Command}, wtPath, u, hookOut); err != nil {
+				if err := runHooks([]string{p.Command}, wtPath, env, u, hookOut); err != nil {
 					return errors.User(fmt.Errorf("pane %d command failed: %w", i, err))
 				}
 			}
@@ -511,6 +538,9 @@ func finishWorktreeWithOptions(ctx context.Context, tr *trace.Tracer, cfg *confi
 	if opts.BaseBranch != "" {
 		meta["base"] = opts.BaseBranch
 	}
+	if cfg.Profile != "" {
+		meta["profile"] = cfg.Profile
+	}
 	if opts.Detached {
 		meta["detached"] = "true"
 		if opts.Ref != "" {
@@ -531,11 +561,14 @@ func finishWorktreeWithOptions(ctx context.Context, tr *trace.Tracer, cfg *confi
 	}
 	u.Info(fmt.Sprintf("  path:   %s", u.Dim(wtPath)))
 	if opts.BaseBranch != "" {
-		u.Info(fmt.Sprintf("  base:   %s", u.Dim("origin/"+opts.BaseBranch)))
+		u.Info(fmt.Sprintf("  base:   %s", u.Dim(cfg.Upstream()+"/"+opts.BaseBranch)))
 	}
 	if opts.Ref != "" {
 		u.Info(fmt.Sprintf("  ref:    %s", u.Dim(opts.Ref)))
 	}
+	if a, ok := ports.Load().Lookup(wtPath); ok && cfg.Ports.Count > 0 {
+		u.Info(fmt.Sprintf("  ports:  %s", u.Dim(a.Range())))
+	}
 	return nil
 }
 
@@ -571,12 +604,12 @@ func resolvePRRef(input, bareDir string) (string, error) {
 	return branch, nil
 }
 
-func pickExistingBranch(repoGit *git.Git) (string, error) {
-	return pickExistingBranchWithQuery(repoGit, "")
+func pickExistingBranch(repoGit *git.Git, cfg *config.Config) (string, error) {
+	return pickExistingBranchWithQuery(repoGit, cfg, "")
 }
 
-func pickExistingBranchWithQuery(repoGit *git.Git, query string) (string, error) {
-	remoteBranches, err := repoGit.RemoteBranches()
+func pickExistingBranchWithQuery(repoGit *git.Git, cfg *config.Config, query string) (string, error) {
+	remoteBranches, err := checkoutRemoteBranches(repoGit, cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to list remote branches: %w", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/ui"
 )
@@ -46,7 +47,7 @@ func TestRunHooksRunsCommandsInDirectory(t *testing.T) {
 
 	var buf bytes.Buffer
 	u := &ui.UI{Out: &buf}
-	if err := runHooks([]string{"printf 'hello\\n'", "pwd"}, dir, u, stdout); err != nil {
+	if err := runHooks([]string{"printf 'hello\\n'", "pwd"}, dir, nil, u, stdout); err != nil {
 		t.Fatalf("runHooks: %v", err)
 	}
 	if err := stdout.Close(); err != nil {
@@ -73,7 +74,7 @@ func TestRunHooksStopsOnFailure(t *testing.T) {
 	}
 	defer stdout.Close()
 
-	err = runHooks([]string{"exit 7"}, t.TempDir(), &ui.UI{}, stdout)
+	err = runHooks([]string{"exit 7"}, t.TempDir(), nil, &ui.UI{}, stdout)
 	if err == nil {
 		t.Fatal("runHooks should return failed hook error")
 	}
@@ -196,7 +197,7 @@ func TestPickExistingBranchReportsNoRemoteBranches(t *testing.T) {
 		t.Fatalf("init bare repo: %v", err)
 	}
 	repoGit := &git.Git{Dir: bareDir}
-	if got, err := pickExistingBranchWithQuery(repoGit, "main"); err == nil || got != "" || !strings.Contains(err.Error(), "no remote branches found") {
+	if got, err := pickExistingBranchWithQuery(repoGit, &config.Config{}, "main"); err == nil || got != "" || !strings.Contains(err.Error(), "no remote branches found") {
 		t.Fatalf("pickExistingBranchWithQuery = %q, %v; want no-remotes error", got, err)
 	}
 }
@@ -236,10 +237,10 @@ func TestPickExistingBranchWithQueryFiltersCurrentWorktrees(t *testing.T) {
 	}
 	t.Setenv("FZF_DEFAULT_OPTS", "--filter=remote-only")
 
-	if got, err := pickExistingBranch(repoGit); err != nil || got != "remote-only" {
+	if got, err := pickExistingBranch(repoGit, &config.Config{}); err != nil || got != "remote-only" {
 		t.Fatalf("pickExistingBranch = %q, %v; want remote-only, nil", got, err)
 	}
-	if got, err := pickExistingBranchWithQuery(repoGit, "remote"); err != nil || got != "remote-only" {
+	if got, err := pickExistingBranchWithQuery(repoGit, &config.Config{}, "remote"); err != nil || got != "remote-only" {
 		t.Fatalf("pickExistingBranchWithQuery = %q, %v; want remote-only, nil", got, err)
 	}
 }
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/notify"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/urfave/cli/v3"
+)
+
+func notifyCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "notify",
+		Usage: "Manage agent notification sinks",
+		Commands: []*cli.Command{
+			notifyTestCmd(),
+			notifyDeliverCmd(),
+		},
+	}
+}
+
+func notifyTestCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "test",
+		Usage: "Send a sample transition to each configured notification sink",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "sink",
+				Usage: "Only test the sink with this name",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "Target status for the sample transition (DONE, WAIT, BUSY, IDLE)",
+				Value: "DONE",
+			},
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Use a repo's local config by name",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.notify.test")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			bareDir := ""
+			if repoName := cmd.String("repo"); repoName != "" {
+				dir, err := config.ResolveRepo(repoName)
+				if err != nil {
+					return err
+				}
+				bareDir = dir
+			} else if dir, err := requireWillowRepo(flags.NewGit()); err == nil {
+				bareDir = dir
+			}
+			cfg := config.Load(bareDir)
+
+			sinks := cfg.Notify.Sinks
+			if name := cmd.String("sink"); name != "" {
+				sinks = nil
+				for _, sink := range cfg.Notify.Sinks {
+					if notify.SinkName(sink) == name {
+						sinks = append(sinks, sink)
+					}
+				}
+				if len(sinks) == 0 {
+					return errors.Userf("no notification sink named %q", name)
+				}
+			}
+			if len(sinks) == 0 {
+				return errors.Userf("no notification sinks configured\n\nAdd one under notify.sinks with 'ww config edit'.")
+			}
+
+			status := strings.ToUpper(cmd.String("status"))
+			payload := sampleNotifyPayload(bareDir, status)
+
+			failed := 0
+			for _, sink := range sinks {
+				label := fmt.Sprintf("%s (%s)", notify.SinkName(sink), sink.Type)
+				if err := notify.SendSink(ctx, sink, payload); err != nil {
+					u.Warn(fmt.Sprintf("  ✗ %s: %v", label, err))
+					failed++
+					continue
+				}
+				line := fmt.Sprintf("  %s %s", u.Green("✔"), label)
+				if !notify.SinkWants(sink, status) {
+					line += u.Dim(fmt.Sprintf(" (its status filter skips %s transitions)", status))
+				}
+				u.Info(line)
+			}
+
+			if failed > 0 {
+				return errors.Userf("%d of %d notification sink(s) failed", failed, len(sinks))
+			}
+			u.Success(fmt.Sprintf("Sent test notification to %d sink(s)", len(sinks)))
+			return nil
+		},
+	}
+}
+
+// notifyDeliverCmd is the hidden subcommand the agent hook spawns detached
+// to post a transition to the global config's sinks, so webhook timeouts
+// and retries happen off the hook path.
+func notifyDeliverCmd() *cli.Command {
+	return &cli.Command{
+		Name:   "deliver",
+		Usage:  "Post a transition payload to notification sinks (internal, used by the agent hook)",
+		Hidden: true,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "payload",
+				UsageText: "<payload-json>",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.notify.deliver")()
+			var payload notify.Payload
+			if err := json.Unmarshal([]byte(cmd.StringArg("payload")), &payload); err != nil {
+				return fmt.Errorf("decode notify payload: %w", err)
+			}
+			return notify.SendSinks(ctx, config.Load("").Notify.Sinks, payload)
+		},
+	}
+}
+
+func sampleNotifyPayload(bareDir, status string) notify.Payload {
+	repo := "willow"
+	if bareDir != "" {
+		repo = repoNameFromDir(bareDir)
+	}
+	return notify.Payload{
+		Repo:            repo,
+		Worktree:        "notify-test",
+		Harness:         "claude",
+		Session:         "test",
+		From:            "BUSY",
+		To:              status,
+		DurationSeconds: 42,
+		Time:            time.Now().UTC(),
+		Message:         fmt.Sprintf("🧪 willow test notification: %s/notify-test BUSY → %s", repo, status),
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/notify"
+)
+
+func TestNotifyTestSendsToEachSink(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var (
+		mu       sync.Mutex
+		payloads []notify.Payload
+		slack    []string
+	)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p notify.Payload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		payloads = append(payloads, p)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+	slackSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		slack = append(slack, body["text"])
+		mu.Unlock()
+	}))
+	defer slackSrv.Close()
+
+	writeGlobalConfigFile(t, fmt.Sprintf(`{"notify":{"sinks":[
+		{"name":"hook","type":"webhook","url":%q},
+		{"name":"chat","type":"slack","url":%q,"statuses":["DONE"]}
+	]}}`, webhook.URL, slackSrv.URL))
+
+	out, err := captureStdout(t, func() error { return runApp("notify", "test", "--status", "wait") })
+	if err != nil {
+		t.Fatalf("notify test: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "hook (webhook)") || !strings.Contains(out, "chat (slack)") {
+		t.Errorf("output should list both sinks:\n%s", out)
+	}
+	if !strings.Contains(out, "skips WAIT transitions") {
+		t.Errorf("output should note chat's filter excludes WAIT:\n%s", out)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 || payloads[0].To != "WAIT" || payloads[0].From != "BUSY" {
+		t.Errorf("webhook payloads = %+v", payloads)
+	}
+	if len(slack) != 1 || !strings.Contains(slack[0], "test notification") {
+		t.Errorf("slack messages = %v", slack)
+	}
+}
+
+func TestNotifyTestReportsFailures(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer broken.Close()
+	writeGlobalConfigFile(t, fmt.Sprintf(`{"notify":{"sinks":[{"name":"hook","type":"webhook","url":%q}]}}`, broken.URL))
+
+	out, err := captureStdout(t, func() error { return runApp("notify", "test", "--sink", "hook") })
+	if err == nil || !strings.Contains(err.Error(), "1 of 1") {
+		t.Fatalf("err = %v, want sink failure", err)
+	}
+	if !strings.Contains(out, "403") {
+		t.Errorf("output should include the HTTP status:\n%s", out)
+	}
+
+	if err := runApp("notify", "test", "--sink", "missing"); err == nil || !strings.Contains(err.Error(), `no notification sink named "missing"`) {
+		t.Errorf("unknown sink err = %v", err)
+	}
+}
+
+func TestNotifyTestWithoutSinks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := runApp("notify", "test"); err == nil || !strings.Contains(err.Error(), "no notification sinks configured") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestNotifyDeliverPostsPayloadToMatchingSinks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var (
+		mu       sync.Mutex
+		payloads []notify.Payload
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p notify.Payload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		payloads = append(payloads, p)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	writeGlobalConfigFile(t, fmt.Sprintf(`{"notify":{"sinks":[{"type":"webhook","url":%q,"statuses":["WAIT"]}]}}`, srv.URL))
+
+	for _, to := range []string{"DONE", "WAIT"} {
+		payload := fmt.Sprintf(`{"repo":"api","worktree":"auth","from":"BUSY","to":%q}`, to)
+		if err := runApp("notify", "deliver", payload); err != nil {
+			t.Fatalf("notify deliver %s: %v", to, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 || payloads[0].To != "WAIT" || payloads[0].Worktree != "auth" {
+		t.Errorf("payloads = %+v, want just the WAIT transition", payloads)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/ports"
+	"github.com/iamrajjoshi/willow/internal/ui"
+)
+
+// worktreeEnv returns the env for commands run in a worktree: the config's
+// env plus, when ports.count is set, the worktree's port block, allocated on
+// first use. An allocation failure warns when u is non-nil.
+func worktreeEnv(cfg *config.Config, wtPath string, u *ui.UI) []string {
+	env := cfg.EnvList()
+	if cfg.Ports.Count <= 0 {
+		return env
+	}
+	a, err := allocatePorts(cfg, wtPath)
+	if err != nil {
+		if u != nil {
+			u.Warn(fmt.Sprintf("Failed to allocate ports: %v", err))
+		}
+		return env
+	}
+	return append(env, a.Env()...)
+}
+
+// existingWorktreeEnv is worktreeEnv for commands that only visit a
+// worktree: it exports the ports reg already holds for wtPath and never
+// allocates a block.
+func existingWorktreeEnv(cfg *config.Config, reg *ports.Registry, wtPath string) []string {
+	env := cfg.EnvList()
+	if a, ok := reg.Lookup(wtPath); ok {
+		env = append(env, a.Env()...)
+	}
+	return env
+}
+
+func allocatePorts(cfg *config.Config, wtPath string) (ports.Allocation, error) {
+	// Worktrees live at <worktrees>/<repo>/<dir>.
+	repo := filepath.Base(filepath.Dir(wtPath))
+	var a ports.Allocation
+	err := ports.Update(func(r *ports.Registry) error {
+		var err error
+		a, err = r.Allocate(repo, wtPath, cfg.Ports.StartPort(), cfg.Ports.Count)
+		return err
+	})
+	return a, err
+}
+
+// worktreePorts returns the ports allocated to each worktree path.
+func worktreePorts() map[string]ports.Allocation {
+	out := map[string]ports.Allocation{}
+	for _, a := range ports.Load().Allocations {
+		out[a.Path] = a
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/ports"
+)
+
+func TestNewAllocatesPortsAndRmReleasesThem(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "portrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "portrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "portrepo")
+
+	cfg := `{"ports": {"count": 2, "start": 47000}, "setup": ["echo $WILLOW_PORT $WILLOW_PORT_1 > ports.txt"]}`
+	if err := os.WriteFile(filepath.Join(bareDir, "willow.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	for _, name := range []string{"one", "two"} {
+		if err := runApp("new", name, "--no-fetch"); err != nil {
+			t.Fatalf("new %s: %v", name, err)
+		}
+	}
+	oneDir, twoDir := filepath.Join(wtRoot, "one"), filepath.Join(wtRoot, "two")
+	one, ok := ports.Load().Lookup(oneDir)
+	if !ok || one.Count != 2 || one.Base < 47000 {
+		t.Fatalf("allocation for one = %+v, %v", one, ok)
+	}
+	two, _ := ports.Load().Lookup(twoDir)
+	if two.Base < one.Base+one.Count {
+		t.Errorf("blocks overlap: one=%+v two=%+v", one, two)
+	}
+	want := fmt.Sprintf("%d %d", one.Base, one.Base+1)
+	if data, _ := os.ReadFile(filepath.Join(oneDir, "ports.txt")); strings.TrimSpace(string(data)) != want {
+		t.Errorf("setup saw ports %q, want %q", data, want)
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("ls", "--json") })
+	if err != nil {
+		t.Fatalf("ls --json: %v", err)
+	}
+	var rows []struct {
+		Path  string `json:"path"`
+		Ports []int  `json:"ports"`
+	}
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("parse ls --json: %v\n%s", err, out)
+	}
+	found := false
+	for _, r := range rows {
+		if r.Path == oneDir {
+			found = len(r.Ports) == 2 && r.Ports[0] == one.Base
+		}
+	}
+	if !found {
+		t.Errorf("ls --json missing ports for one:\n%s", out)
+	}
+
+	out, err = captureStdout(t, func() error { return runApp("status", "--json") })
+	if err != nil {
+		t.Fatalf("status --json: %v", err)
+	}
+	if !strings.Contains(out, `"ports"`) {
+		t.Errorf("status --json missing ports:\n%s", out)
+	}
+
+	if err := runApp("rm", "one", "--force"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if _, ok := ports.Load().Lookup(oneDir); ok {
+		t.Error("rm did not release the port block")
+	}
+}
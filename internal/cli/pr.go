@@ -22,6 +22,7 @@ func prCmd() *cli.Command {
 		Usage: "GitHub pull request workflows",
 		Commands: []*cli.Command{
 			prCreateCmd(),
+			prUpdateStackCmd(),
 		},
 	}
 }
@@ -56,6 +57,7 @@ func prCreateCmd() *cli.Command {
 
 			cfg := config.Load(bareDir)
 			repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose}
+			pushRemote := cfg.Push()
 			currentGit := &git.Git{Dir: wtPath, Verbose: g.Verbose}
 
 			currentBranch, err := currentBranchName(currentGit)
@@ -92,12 +94,17 @@ func prCreateCmd() *cli.Command {
 					}
 				}
 
-				pushed, err := pushBranchIfNeeded(repoGit, branchPath, branch)
+				pushed, err := pushBranchIfNeeded(repoGit, pushRemote, branchPath, branch)
 				if err != nil {
 					return err
 				}
 				if pushed {
-					u.Info(fmt.Sprintf("    %s Pushed origin/%s", u.Dim("↑"), branch))
+					u.Info(fmt.Sprintf("    %s Pushed %s/%s", u.Dim("↑"), pushRemote, branch))
+				}
+
+				prRepo, head, err := forkPRTarget(repoGit, cfg, branch)
+				if err != nil {
+					return err
 				}
 
 				headOID, err := branchHeadOID(repoGit, branchPath, branch)
@@ -109,7 +116,7 @@ func prCreateCmd() *cli.Command {
 				if branchPath != "" {
 					lookupDir = branchPath
 				}
-				existingPR, err := gh.FindOpenPR(lookupDir, branch, headOID)
+				existingPR, err := gh.FindOpenPR(lookupDir, prRepo, branch, headOID)
 				if err != nil {
 					return err
 				}
@@ -123,7 +130,7 @@ func prCreateCmd() *cli.Command {
 					continue
 				}
 
-				url, err := gh.CreatePR(wtPath, base, branch, draft)
+				url, err := gh.CreatePR(wtPath, prRepo, base, head, draft)
 				if err != nil {
 					return err
 				}
@@ -256,8 +263,8 @@ func branchHeadOID(repoGit *git.Git, wtPath, branch string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
-func pushBranchIfNeeded(repoGit *git.Git, wtPath, branch string) (bool, error) {
-	needsPush, err := branchNeedsPush(repoGit, branch)
+func pushBranchIfNeeded(repoGit *git.Git, remote, wtPath, branch string) (bool, error) {
+	needsPush, err := branchNeedsPush(repoGit, remote, branch)
 	if err != nil {
 		return false, err
 	}
@@ -266,10 +273,10 @@ func pushBranchIfNeeded(repoGit *git.Git, wtPath, branch string) (bool, error) {
 	}
 
 	gitRunner := repoGit
-	args := []string{"push", "origin", branch}
+	args := []string{"push", remote, branch}
 	if wtPath != "" {
 		gitRunner = &git.Git{Dir: wtPath, Verbose: repoGit.Verbose}
-		args = []string{"push", "-u", "origin", branch}
+		args = []string{"push", "-u", remote, branch}
 	}
 
 	if _, err := gitRunner.Run(args...); err != nil {
@@ -278,14 +285,16 @@ func pushBranchIfNeeded(repoGit *git.Git, wtPath, branch string) (bool, error) {
 	return true, nil
 }
 
-func branchNeedsPush(repoGit *git.Git, branch string) (bool, error) {
-	if !repoGit.RemoteBranchExists(branch) {
+func branchNeedsPush(repoGit *git.Git, remote, branch string) (bool, error) {
+	remoteGit := &git.Git{Dir: repoGit.Dir, Verbose: repoGit.Verbose, Remote: remote}
+	if !remoteGit.RemoteBranchExists(branch) {
 		return true, nil
 	}
 
-	out, err := repoGit.Run("rev-list", "--left-right", "--count", "origin/"+branch+"..."+branch)
+	remoteRef := remoteGit.RemoteRef(branch)
+	out, err := repoGit.Run("rev-list", "--left-right", "--count", remoteRef+"..."+branch)
 	if err != nil {
-		return false, fmt.Errorf("failed to compare branch %q to origin: %w", branch, err)
+		return false, fmt.Errorf("failed to compare branch %q to %s: %w", branch, remote, err)
 	}
 
 	trimmed := strings.TrimSpace(out)
@@ -298,9 +307,9 @@ func branchNeedsPush(repoGit *git.Git, branch string) (bool, error) {
 
 	if behind > 0 {
 		if ahead > 0 {
-			return false, errors.Userf("branch %q has diverged from origin/%s\n\nReconcile the branch before creating a PR.", branch, branch)
+			return false, errors.Userf("branch %q has diverged from %s\n\nReconcile the branch before creating a PR.", branch, remoteRef)
 		}
-		return false, errors.Userf("branch %q is behind origin/%s\n\nFast-forward or reset the branch before creating a PR.", branch, branch)
+		return false, errors.Userf("branch %q is behind %s\n\nFast-forward or reset the branch before creating a PR.", branch, remoteRef)
 	}
 	return ahead > 0, nil
 }
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/gh"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/stack"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	stackSectionStart = "<!-- willow-stack:start -->"
+	stackSectionEnd   = "<!-- willow-stack:end -->"
+)
+
+func prUpdateStackCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "update-stack",
+		Usage: "Add or refresh a stack navigation table in each open PR of the stack",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Update every stack in a willow-managed repo by name",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show which PRs would change without editing them",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.pr.update_stack")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			var bareDir string
+			var st *stack.Stack
+			var roots []string
+			if repoFlag := cmd.String("repo"); repoFlag != "" {
+				var err error
+				bareDir, err = config.ResolveRepo(repoFlag)
+				if err != nil {
+					return err
+				}
+				st = stack.Load(bareDir)
+				roots = st.Roots()
+			} else {
+				wtPath, dir, err := requireWillowWorktree(g)
+				if err != nil {
+					return err
+				}
+				bareDir = dir
+				currentBranch, err := currentBranchName(&git.Git{Dir: wtPath, Verbose: g.Verbose})
+				if err != nil {
+					return err
+				}
+				st = stack.Load(bareDir)
+				if !st.IsTracked(currentBranch) {
+					return errors.Userf("branch %q is not in a stack\n\nCreate stacked branches with 'ww new <branch> -b <parent>'.", currentBranch)
+				}
+				roots = []string{stackRoot(st, currentBranch)}
+			}
+			if err := gh.EnsureCLI("stack PR updates"); err != nil {
+				return err
+			}
+			if len(roots) == 0 {
+				return errors.Userf("no stacked branches found")
+			}
+
+			dryRun := cmd.Bool("dry-run")
+			updated, unchanged, err := updateStackTables(bareDir, st, roots, "", dryRun, u)
+			if err != nil {
+				return err
+			}
+
+			if updated == 0 && unchanged == 0 {
+				u.Info("No open PRs in the stack.")
+				return nil
+			}
+			if dryRun {
+				u.Info(fmt.Sprintf("\nDry run: %d PR(s) would be updated, %d unchanged.", updated, unchanged))
+				return nil
+			}
+			u.Success(fmt.Sprintf("%d PR(s) updated, %d unchanged", updated, unchanged))
+			return nil
+		},
+	}
+}
+
+// updateStackTables refreshes the stack section in every open PR under roots
+// and reports how many bodies changed and how many were already current. A
+// non-empty header is printed before the first PR line.
+func updateStackTables(bareDir string, st *stack.Stack, roots []string, header string, dryRun bool, u *ui.UI) (updated, unchanged int, err error) {
+	ghDir, err := findGHDir(filepath.Join(config.WorktreesDir(), repoNameFromDir(bareDir)))
+	if err != nil {
+		return 0, 0, errors.Userf("no worktree found to run gh in (need at least one worktree)")
+	}
+
+	var all []string
+	for _, root := range roots {
+		all = append(all, st.SubtreeSort(root)...)
+	}
+	prMap, err := gh.BatchPRInfo(ghDir, all)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, root := range roots {
+		branches := st.SubtreeSort(root)
+		branchSet := make(map[string]bool, len(branches))
+		for _, b := range branches {
+			branchSet[b] = true
+		}
+		treeLines := st.TreeLines(branchSet)
+
+		for _, branch := range branches {
+			pr := prMap[branch]
+			if pr == nil || pr.State != "OPEN" {
+				continue
+			}
+			if header != "" && updated+unchanged == 0 {
+				u.Info(header)
+			}
+			body, err := gh.PRBody(ghDir, pr.Number)
+			if err != nil {
+				return updated, unchanged, err
+			}
+			newBody := replaceStackSection(body, renderStackSection(treeLines, prMap, branch))
+			if newBody == body {
+				u.Info(fmt.Sprintf("  %s #%d %s", u.Dim("↺"), pr.Number, branch))
+				unchanged++
+				continue
+			}
+			if dryRun {
+				u.Info(fmt.Sprintf("  %s #%d %s (would update)", u.Dim("~"), pr.Number, branch))
+				updated++
+				continue
+			}
+			if err := gh.EditPRBody(ghDir, pr.Number, newBody); err != nil {
+				return updated, unchanged, err
+			}
+			u.Info(fmt.Sprintf("  %s #%d %s", u.Green("✔"), pr.Number, branch))
+			updated++
+		}
+	}
+	return updated, unchanged, nil
+}
+
+// refreshStackTables runs the update-stack refresh after a restack when
+// defaults.updateStackTables is on and gh is installed. It stays quiet when
+// the stack has no open PRs, and failures only warn: the branches have
+// already been rebased.
+func refreshStackTables(bareDir string, u *ui.UI) {
+	cfg := config.Load(bareDir)
+	if !*cfg.Defaults.UpdateStackTables || gh.EnsureCLI("") != nil {
+		return
+	}
+	if plan, _ := stack.LoadRestackPlan(bareDir); plan != nil {
+		// Still conflicted; 'ww sync --continue' refreshes once it finishes.
+		return
+	}
+	st := stack.Load(bareDir)
+	roots := st.Roots()
+	if len(roots) == 0 {
+		return
+	}
+	if _, _, err := updateStackTables(bareDir, st, roots, "\nUpdating stack tables in open PRs:\n", false, u); err != nil {
+		u.Warn(fmt.Sprintf("Failed to update stack tables: %v", err))
+	}
+}
+
+// stackRoot walks up from branch to the topmost tracked ancestor.
+func stackRoot(st *stack.Stack, branch string) string {
+	for st.IsTracked(st.Parent(branch)) {
+		branch = st.Parent(branch)
+	}
+	return branch
+}
+
+// renderStackSection renders the delimited markdown table listing every PR in
+// the stack, with current's row highlighted.
+func renderStackSection(treeLines []stack.TreeLine, prMap map[string]*gh.PRInfo, current string) string {
+	var b strings.Builder
+	b.WriteString(stackSectionStart + "\n")
+	b.WriteString("**Stack** (updated by `ww pr update-stack`)\n\n")
+	b.WriteString("| | PR | Branch | Status |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, tl := range treeLines {
+		marker, prCell, status := "", "—", "No PR"
+		if pr := prMap[tl.Branch]; pr != nil {
+			prCell = fmt.Sprintf("#%d", pr.Number)
+			status = stackPRStatus(pr)
+		}
+		// Leading spaces in table cells are collapsed, so keep the tree
+		// indentation with non-breaking spaces.
+		branchCell := strings.ReplaceAll(tl.Prefix, " ", "\u00a0") + "`" + tl.Branch + "`"
+		if tl.Branch == current {
+			marker = "👉"
+			prCell = "**" + prCell + "**"
+			branchCell = "**" + branchCell + "**"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", marker, prCell, branchCell, status)
+	}
+	b.WriteString(stackSectionEnd)
+	return b.String()
+}
+
+func stackPRStatus(pr *gh.PRInfo) string {
+	switch pr.State {
+	case "MERGED":
+		return "Merged"
+	case "CLOSED":
+		return "Closed"
+	}
+
+	parts := []string{"Open"}
+	switch pr.CIStatus() {
+	case "pass":
+		parts = append(parts, "✅ CI")
+	case "fail":
+		parts = append(parts, "❌ CI")
+	case "pending":
+		parts = append(parts, "⏳ CI")
+	}
+	switch pr.ReviewStatus {
+	case "APPROVED":
+		parts = append(parts, "Approved")
+	case "CHANGES_REQUESTED":
+		parts = append(parts, "Changes requested")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// replaceStackSection swaps the delimited stack section in body for section,
+// or appends it when the body has none yet.
+func replaceStackSection(body, section string) string {
+	start := strings.Index(body, stackSectionStart)
+	if start >= 0 {
+		if end := strings.Index(body[start:], stackSectionEnd); end >= 0 {
+			end += start + len(stackSectionEnd)
+			return body[:start] + section + body[end:]
+		}
+	}
+	trimmed := strings.TrimRight(body, "\n")
+	if trimmed == "" {
+		return section
+	}
+	return trimmed + "\n\n" + section
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/gh"
+	"github.com/iamrajjoshi/willow/internal/stack"
+)
+
+func TestRenderStackSectionHighlightsCurrent(t *testing.T) {
+	st := &stack.Stack{Parents: map[string]string{
+		"feature-a": "main",
+		"feature-b": "feature-a",
+		"feature-c": "feature-a",
+	}}
+	lines := st.TreeLines(map[string]bool{"feature-a": true, "feature-b": true, "feature-c": true})
+	prMap := map[string]*gh.PRInfo{
+		"feature-a": {Number: 1, State: "MERGED"},
+		"feature-b": {Number: 2, State: "OPEN", ReviewStatus: "APPROVED"},
+	}
+
+	got := renderStackSection(lines, prMap, "feature-b")
+	if !strings.HasPrefix(got, stackSectionStart) || !strings.HasSuffix(got, stackSectionEnd) {
+		t.Fatalf("section should be delimited, got:\n%s", got)
+	}
+	for _, want := range []string{
+		"|  | #1 | `feature-a` | Merged |",
+		"| 👉 | **#2** | **├─\u00a0`feature-b`** | Open · Approved |",
+		"|  | — | └─\u00a0`feature-c` | No PR |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("section missing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestReplaceStackSection(t *testing.T) {
+	section := stackSectionStart + "\nnew\n" + stackSectionEnd
+
+	if got := replaceStackSection("", section); got != section {
+		t.Fatalf("empty body = %q, want section only", got)
+	}
+
+	appended := replaceStackSection("Summary\n", section)
+	if appended != "Summary\n\n"+section {
+		t.Fatalf("appended body = %q", appended)
+	}
+
+	old := "Summary\n\n" + stackSectionStart + "\nold\n" + stackSectionEnd + "\n\nFooter"
+	want := "Summary\n\n" + section + "\n\nFooter"
+	if got := replaceStackSection(old, section); got != want {
+		t.Fatalf("replaced body = %q, want %q", got, want)
+	}
+	if got := replaceStackSection(want, section); got != want {
+		t.Fatal("replacing with the same section should be a no-op")
+	}
+}
+
+func TestPRUpdateStackEditsOpenPRsIdempotently(t *testing.T) {
+	f := setupSyncStack(t, "prstack")
+	binDir, logPath := installTestCLIPath(t, "")
+	stateDir := t.TempDir()
+	writeTestExecutable(t, binDir, "gh", fmt.Sprintf(`#!/bin/sh
+STATE_DIR=%q
+printf '%%s\n' "$*" >> %q
+case "$1 $2" in
+  "pr list")
+    printf '[{"number":11,"headRefName":"feature-a","state":"OPEN"},{"number":12,"headRefName":"feature-b","state":"OPEN"}]\n'
+    ;;
+  "pr view")
+    if [ -f "$STATE_DIR/$3" ]; then cat "$STATE_DIR/$3"; else printf 'Original body\n'; fi
+    ;;
+  "pr edit")
+    cat > "$STATE_DIR/$3"
+    ;;
+esac
+`, stateDir, logPath))
+	if err := os.Chdir(f.FeatureBDir); err != nil {
+		t.Fatalf("chdir feature-b: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runApp("pr", "update-stack")
+	})
+	if err != nil {
+		t.Fatalf("pr update-stack failed: %v", err)
+	}
+	if !strings.Contains(out, "2 PR(s) updated, 0 unchanged") {
+		t.Fatalf("expected both PRs updated, got:\n%s", out)
+	}
+	body := readTestFile(t, filepath.Join(stateDir, "12"))
+	if !strings.HasPrefix(body, "Original body\n\n"+stackSectionStart) {
+		t.Fatalf("body should keep original text and append section, got:\n%s", body)
+	}
+	if !strings.Contains(body, "| 👉 | **#12** |") {
+		t.Fatalf("feature-b body should highlight #12, got:\n%s", body)
+	}
+	if !strings.Contains(readTestFile(t, filepath.Join(stateDir, "11")), "| 👉 | **#11** |") {
+		t.Fatal("feature-a body should highlight #11")
+	}
+
+	out, err = captureStdout(t, func() error {
+		return runApp("pr", "update-stack")
+	})
+	if err != nil {
+		t.Fatalf("second pr update-stack failed: %v", err)
+	}
+	if !strings.Contains(out, "0 PR(s) updated, 2 unchanged") {
+		t.Fatalf("second run should be a no-op, got:\n%s", out)
+	}
+	if edits := strings.Count(readTestFile(t, logPath), "pr edit"); edits != 2 {
+		t.Fatalf("expected 2 gh pr edit calls total, got %d", edits)
+	}
+}
+
+func TestPRUpdateStackRequiresStackedBranch(t *testing.T) {
+	f := setupSyncStack(t, "prstacknone")
+	installTestCLIPath(t, "#!/bin/sh\nexit 0\n")
+	if err := os.Chdir(f.MainDir); err != nil {
+		t.Fatalf("chdir main: %v", err)
+	}
+	if err := runApp("pr", "update-stack"); err == nil || !strings.Contains(err.Error(), "not in a stack") {
+		t.Fatalf("pr update-stack error = %v, want not-in-stack error", err)
+	}
+}
+
+func TestSyncRefreshesStackTablesWhenEnabled(t *testing.T) {
+	f := setupSyncStack(t, "syncstacktables")
+	binDir, logPath := installTestCLIPath(t, "")
+	stateDir := t.TempDir()
+	writeTestExecutable(t, binDir, "gh", fmt.Sprintf(`#!/bin/sh
+STATE_DIR=%q
+printf '%%s\n' "$*" >> %q
+case "$1 $2" in
+  "pr list")
+    printf '[{"number":11,"headRefName":"feature-a","state":"OPEN"},{"number":12,"headRefName":"feature-b","state":"OPEN"}]\n'
+    ;;
+  "pr view")
+    if [ -f "$STATE_DIR/$3" ]; then cat "$STATE_DIR/$3"; else printf 'Original body\n'; fi
+    ;;
+  "pr edit")
+    cat > "$STATE_DIR/$3"
+    ;;
+esac
+`, stateDir, logPath))
+	if err := os.Chdir(f.FeatureBDir); err != nil {
+		t.Fatalf("chdir feature-b: %v", err)
+	}
+
+	if err := runApp("sync", "--no-fetch"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if calls, _ := os.ReadFile(logPath); strings.Contains(string(calls), "pr edit") {
+		t.Fatalf("sync should leave PR bodies alone by default, got:\n%s", calls)
+	}
+
+	writeGlobalConfigFile(t, `{"defaults":{"updateStackTables":true}}`)
+	out, err := captureStdout(t, func() error {
+		return runApp("sync", "--no-fetch")
+	})
+	if err != nil {
+		t.Fatalf("sync with updateStackTables failed: %v", err)
+	}
+	if !strings.Contains(out, "Updating stack tables in open PRs") {
+		t.Fatalf("expected stack table refresh, got:\n%s", out)
+	}
+	for _, number := range []string{"11", "12"} {
+		if body := readTestFile(t, filepath.Join(stateDir, number)); !strings.Contains(body, "| 👉 | **#"+number+"** |") {
+			t.Fatalf("PR #%s body should carry the stack table, got:\n%s", number, body)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/ui"
+)
+
+// profileFile holds a worktree's profile name. It lives in the worktree's
+// git admin dir, so it follows the worktree through ww rename and the
+// trash without touching the working copy.
+const profileFile = "willow-profile"
+
+// applyProfileFlag applies the profile named by --profile, turning an
+// unknown name into a user error.
+func applyProfileFlag(cfg *config.Config, name string) (*config.Config, error) {
+	out, err := cfg.WithProfile(name)
+	if err != nil {
+		return nil, errors.User(err)
+	}
+	return out, nil
+}
+
+func saveWorktreeProfile(wtPath, name string) error {
+	adminDir, err := readGitAdminDir(wtPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(adminDir, profileFile), []byte(name+"\n"), 0o644)
+}
+
+// worktreeProfile returns the profile a worktree was created with, or "".
+func worktreeProfile(wtPath string) string {
+	adminDir, err := readGitAdminDir(wtPath)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(adminDir, profileFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// withWorktreeProfile applies the worktree's remembered profile to cfg. A
+// profile that has since been removed from config falls back to cfg, with a
+// warning when u is non-nil.
+func withWorktreeProfile(cfg *config.Config, wtPath string, u *ui.UI) *config.Config {
+	name := worktreeProfile(wtPath)
+	if name == "" || cfg.Profile == name {
+		return cfg
+	}
+	out, err := cfg.WithProfile(name)
+	if err != nil {
+		if u != nil {
+			u.Warn(fmt.Sprintf("Ignoring profile of %s: %v", filepath.Base(wtPath), err))
+		}
+		return cfg
+	}
+	return out
+}
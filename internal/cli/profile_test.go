@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestNewProfileAppliesAndIsRememberedForTeardown(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "profrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "profrepo.git")
+	bareGit := &git.Git{Dir: bareDir}
+	mainBranch, _ := bareGit.DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "profrepo")
+	out := t.TempDir()
+
+	cfg := `{
+  "setup": ["echo default > ` + filepath.Join(out, "setup") + `"],
+  "env": {"SHARED": "yes"},
+  "profiles": {
+    "review": {
+      "branchPrefix": "rev",
+      "setup": ["echo \"$APP_MODE $SHARED\" > ` + filepath.Join(out, "setup") + `"],
+      "teardown": ["echo \"$APP_MODE\" > ` + filepath.Join(out, "teardown") + `"],
+      "env": {"APP_MODE": "review"}
+    }
+  }
+}`
+	if err := os.WriteFile(filepath.Join(bareDir, "willow.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "pr-42", "--no-fetch", "--profile", "review"); err != nil {
+		t.Fatalf("new --profile: %v", err)
+	}
+	if !bareGit.LocalBranchExists("rev/pr-42") {
+		t.Fatal("profile branchPrefix not applied")
+	}
+	wtDir := filepath.Join(wtRoot, "rev-pr-42")
+	if got := worktreeProfile(wtDir); got != "review" {
+		t.Errorf("remembered profile = %q, want review", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "setup")); strings.TrimSpace(string(data)) != "review yes" {
+		t.Errorf("setup output = %q, want profile setup with profile and shared env", data)
+	}
+
+	if err := runApp("rm", "rev/pr-42", "--force"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "teardown")); strings.TrimSpace(string(data)) != "review" {
+		t.Errorf("teardown output = %q, want the profile's teardown", data)
+	}
+}
+
+func TestNewRejectsUnknownProfile(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "profrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "profrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	os.Chdir(filepath.Join(config.WorktreesDir(), "profrepo", mainBranch))
+
+	err := runApp("new", "feature", "--no-fetch", "--profile", "nope")
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "nope"`) {
+		t.Fatalf("new --profile nope error = %v", err)
+	}
+	if pathExists(filepath.Join(config.WorktreesDir(), "profrepo", "feature")) {
+		t.Error("worktree created despite unknown profile")
+	}
+}
@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/urfave/cli/v3"
+)
+
+func remoteCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "remote",
+		Usage: "Manage a repo's remotes for fork workflows",
+		Commands: []*cli.Command{
+			remoteAddCmd(),
+			remoteLsCmd(),
+		},
+	}
+}
+
+func remoteAddCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "add",
+		Usage: "Add a remote and optionally make it the upstream or push remote",
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "name",
+				UsageText: "<name>",
+			},
+			&cli.StringArg{
+				Name:      "url",
+				UsageText: "<url>",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.BoolFlag{
+				Name:  "upstream",
+				Usage: "Resolve base branches from this remote and open PRs against it",
+			},
+			&cli.BoolFlag{
+				Name:  "push",
+				Usage: "Push branches to this remote",
+			},
+			&cli.BoolFlag{
+				Name:  "no-fetch",
+				Usage: "Skip fetching the new remote",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.remote.add")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			name := cmd.StringArg("name")
+			remoteURL := cmd.StringArg("url")
+			if name == "" || remoteURL == "" {
+				return errors.Userf("remote name and URL are required\n\nUsage: ww remote add <name> <url> [--upstream] [--push]")
+			}
+
+			bareDir, err := remoteRepoDir(g, cmd.String("repo"))
+			if err != nil {
+				return err
+			}
+			repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose}
+			if _, err := repoGit.Run("remote", "get-url", name); err == nil {
+				return errors.Userf("remote %q already exists", name)
+			}
+
+			if err := addRemote(repoGit, name, remoteURL); err != nil {
+				return err
+			}
+			u.Success(fmt.Sprintf("Added remote %s (%s)", u.Bold(name), remoteURL))
+
+			if !cmd.Bool("no-fetch") {
+				cfg := config.Load(bareDir)
+				if err := u.Spin(fmt.Sprintf("Fetching %s", name), func() error {
+					_, err := repoGit.Run(fetchArgs(cfg, "--no-tags", name)...)
+					return err
+				}); err != nil {
+					u.Warn(fmt.Sprintf("Failed to fetch %s: %v", name, err))
+				}
+			}
+
+			upstream, push := cmd.Bool("upstream"), cmd.Bool("push")
+			if !upstream && !push {
+				return nil
+			}
+			if err := setRemoteRoles(bareDir, name, upstream, push); err != nil {
+				return err
+			}
+			if upstream {
+				u.Info(fmt.Sprintf("  Base branches and PRs now use %s", u.Bold(name)))
+			}
+			if push {
+				u.Info(fmt.Sprintf("  Branches now push to %s", u.Bold(name)))
+			}
+			return nil
+		},
+	}
+}
+
+func remoteLsCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "ls",
+		Aliases: []string{"list"},
+		Usage:   "List remotes and which one is upstream and push",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.remote.ls")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			bareDir, err := remoteRepoDir(g, cmd.String("repo"))
+			if err != nil {
+				return err
+			}
+			cfg := config.Load(bareDir)
+			repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose}
+
+			out, err := repoGit.Run("remote")
+			if err != nil {
+				return fmt.Errorf("failed to list remotes: %w", err)
+			}
+			names := strings.Fields(out)
+			width := 0
+			for _, name := range names {
+				width = max(width, len(name))
+			}
+			for _, name := range names {
+				remoteURL, _ := repoGit.Run("remote", "get-url", name)
+				var roles []string
+				if name == cfg.Upstream() {
+					roles = append(roles, "upstream")
+				}
+				if name == cfg.Push() {
+					roles = append(roles, "push")
+				}
+				line := fmt.Sprintf("%-*s  %s", width, name, remoteURL)
+				if len(roles) > 0 {
+					line += "  " + u.Dim("("+strings.Join(roles, ", ")+")")
+				}
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+}
+
+func remoteRepoDir(g *git.Git, repoFlag string) (string, error) {
+	if repoFlag != "" {
+		return config.ResolveRepo(repoFlag)
+	}
+	return requireWillowRepo(g)
+}
+
+// addRemote adds a remote. Unlike the origin of a bare clone, git remote add
+// writes the standard refspec, so fetches fill refs/remotes/<name>/*.
+func addRemote(repoGit *git.Git, name, remoteURL string) error {
+	if _, err := repoGit.Run("remote", "add", name, remoteURL); err != nil {
+		return fmt.Errorf("failed to add remote %q: %w", name, err)
+	}
+	return nil
+}
+
+// setRemoteRoles records name as the upstream and/or push remote in the
+// repo's willow.json, keeping the rest of the file as is.
+func setRemoteRoles(bareDir, name string, upstream, push bool) error {
+	path := config.LocalConfigPath(bareDir)
+	local, err := config.LoadFile(path)
+	if err != nil {
+		local = &config.Config{}
+	}
+	if upstream {
+		local.UpstreamRemote = name
+	}
+	if push {
+		local.PushRemote = name
+	}
+	if err := config.Save(local, path); err != nil {
+		return fmt.Errorf("failed to save repo config: %w", err)
+	}
+	return nil
+}
+
+// configRemotes returns the upstream remote followed by the push remote when
+// they differ.
+func configRemotes(cfg *config.Config) []string {
+	if cfg.Push() == cfg.Upstream() {
+		return []string{cfg.Upstream()}
+	}
+	return []string{cfg.Upstream(), cfg.Push()}
+}
+
+// fetchRemotesArgs builds a fetch of every configured remote, so a fork
+// workflow sees both upstream base branches and the fork's own branches.
+func fetchRemotesArgs(cfg *config.Config, args ...string) []string {
+	remotes := configRemotes(cfg)
+	if len(remotes) > 1 {
+		args = append(args, "--multiple")
+	}
+	return fetchArgs(cfg, append(args, remotes...)...)
+}
+
+// branchRemotes returns the remotes to look for an existing branch on: the
+// push remote first, where your own branches live, then upstream.
+func branchRemotes(cfg *config.Config) []string {
+	remotes := configRemotes(cfg)
+	if len(remotes) > 1 {
+		remotes[0], remotes[1] = remotes[1], remotes[0]
+	}
+	return remotes
+}
+
+// remoteWithBranch returns the first remote from branchRemotes that has a
+// tracking ref for branch, or "" when none does.
+func remoteWithBranch(repoGit *git.Git, cfg *config.Config, branch string) string {
+	for _, remote := range branchRemotes(cfg) {
+		rg := &git.Git{Dir: repoGit.Dir, Verbose: repoGit.Verbose, Remote: remote}
+		if rg.RemoteBranchExists(branch) {
+			return remote
+		}
+	}
+	return ""
+}
+
+// fetchBranch fetches branch from the push remote, falling back to upstream,
+// and streams progress to stderr when it is non-nil.
+func fetchBranch(repoGit *git.Git, cfg *config.Config, stderr io.Writer, branch string) error {
+	var err error
+	for _, remote := range branchRemotes(cfg) {
+		if stderr != nil {
+			_, err = repoGit.RunStream(stderr, fetchArgs(cfg, "--no-tags", "--progress", remote, branch)...)
+		} else {
+			_, err = repoGit.Run(fetchArgs(cfg, "--no-tags", remote, branch)...)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// existingBranchArgs builds git worktree add arguments for an existing
+// branch. Without a local branch, the remote branch is tracked explicitly:
+// git's own guess refuses names that exist on more than one remote.
+func existingBranchArgs(repoGit *git.Git, cfg *config.Config, wtPath, branch string) []string {
+	if !repoGit.LocalBranchExists(branch) {
+		if remote := remoteWithBranch(repoGit, cfg, branch); remote != "" {
+			return []string{"--track", "-b", branch, wtPath, remote + "/" + branch}
+		}
+	}
+	return []string{wtPath, branch}
+}
+
+// checkoutRemoteBranches lists branches on the push and upstream remotes,
+// without duplicates.
+func checkoutRemoteBranches(repoGit *git.Git, cfg *config.Config) ([]string, error) {
+	seen := map[string]bool{}
+	var branches []string
+	for _, remote := range branchRemotes(cfg) {
+		rg := &git.Git{Dir: repoGit.Dir, Verbose: repoGit.Verbose, Remote: remote}
+		names, err := rg.RemoteBranches()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				branches = append(branches, name)
+			}
+		}
+	}
+	return branches, nil
+}
+
+// githubRepo returns the gh [HOST/]OWNER/REPO form of a remote URL. The
+// host is omitted for github.com.
+func githubRepo(remoteURL string) (string, bool) {
+	var host, path string
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remoteURL, "@"); ok && !strings.Contains(at, "/") {
+		host, path, ok = strings.Cut(rest, ":")
+		if !ok {
+			return "", false
+		}
+	} else {
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	slug := parts[0] + "/" + parts[1]
+	if host != "github.com" {
+		slug = host + "/" + slug
+	}
+	return slug, true
+}
+
+// forkPRTarget returns the repo gh should open PRs in and the head to use
+// for branch. With a separate push remote the PR targets the upstream repo
+// and the head is owner:branch on the fork; otherwise both are gh defaults.
+func forkPRTarget(repoGit *git.Git, cfg *config.Config, branch string) (repo, head string, err error) {
+	if cfg.Push() == cfg.Upstream() {
+		return "", branch, nil
+	}
+	upstreamURL, err := repoGit.Run("remote", "get-url", cfg.Upstream())
+	if err != nil {
+		return "", "", errors.Userf("upstream remote %q is not configured\n\nAdd it with 'ww remote add %s <url> --upstream'.", cfg.Upstream(), cfg.Upstream())
+	}
+	pushURL, err := repoGit.Run("remote", "get-url", cfg.Push())
+	if err != nil {
+		return "", "", errors.Userf("push remote %q is not configured\n\nAdd it with 'ww remote add %s <url> --push'.", cfg.Push(), cfg.Push())
+	}
+	repo, ok := githubRepo(upstreamURL)
+	if !ok {
+		return "", "", errors.Userf("upstream remote %q (%s) is not a GitHub repo", cfg.Upstream(), upstreamURL)
+	}
+	fork, ok := githubRepo(pushURL)
+	if !ok {
+		return "", "", errors.Userf("push remote %q (%s) is not a GitHub repo", cfg.Push(), pushURL)
+	}
+	if fork == repo {
+		return repo, branch, nil
+	}
+	parts := strings.Split(fork, "/")
+	owner := parts[len(parts)-2]
+	return repo, owner + ":" + branch, nil
+}
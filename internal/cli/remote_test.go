@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestGithubRepo(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"git@github.com:org/repo.git", "org/repo", true},
+		{"https://github.com/org/repo", "org/repo", true},
+		{"ssh://git@github.com/org/repo.git", "org/repo", true},
+		{"git@ghe.example.com:org/repo.git", "ghe.example.com/org/repo", true},
+		{"/tmp/origin.git", "", false},
+		{"https://github.com/org", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := githubRepo(tt.url)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("githubRepo(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// setupForkClone clones a fork of the test origin with ww clone --upstream,
+// so origin is the fork and upstream is the canonical repo. It returns the
+// canonical repo's working copy and the fork's bare path.
+func setupForkClone(t *testing.T) (string, string, string) {
+	t.Helper()
+	canonical := setupTestEnv(t)
+	home := os.Getenv("HOME")
+	fork := filepath.Join(home, "fork.git")
+	if _, err := (&git.Git{}).Run("clone", "--bare", canonical, fork); err != nil {
+		t.Fatalf("clone fork: %v", err)
+	}
+	if err := runApp("clone", fork, "testrepo", "--upstream", canonical); err != nil {
+		t.Fatalf("clone --upstream: %v", err)
+	}
+	return filepath.Join(home, "workdir"), canonical, fork
+}
+
+func TestCloneUpstreamSetsUpForkWorkflow(t *testing.T) {
+	workdir, canonical, fork := setupForkClone(t)
+	bareDir := filepath.Join(config.ReposDir(), "testrepo.git")
+	cfg := config.Load(bareDir)
+	if cfg.Upstream() != "upstream" || cfg.Push() != "origin" {
+		t.Fatalf("remotes = upstream %q, push %q", cfg.Upstream(), cfg.Push())
+	}
+
+	// New upstream work lands after the fork was made.
+	commitFile(t, workdir, "upstream.txt", "upstream\n", "upstream change")
+	wg := &git.Git{Dir: workdir}
+	mainBranch, _ := wg.Run("symbolic-ref", "--short", "HEAD")
+	if _, err := wg.Run("push", "origin", mainBranch); err != nil {
+		t.Fatalf("push upstream: %v", err)
+	}
+	upstreamHead, _ := wg.Run("rev-parse", "HEAD")
+
+	wtRoot := filepath.Join(config.WorktreesDir(), "testrepo")
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	featureGit := &git.Git{Dir: featureDir}
+	if head, _ := featureGit.Run("rev-parse", "HEAD"); head != upstreamHead {
+		t.Fatalf("feature based on %s, want upstream head %s", head, upstreamHead)
+	}
+
+	commitFile(t, featureDir, "feature.txt", "feature\n", "feature")
+	pushed, err := pushBranchIfNeeded(&git.Git{Dir: bareDir}, cfg.Push(), featureDir, "feature")
+	if err != nil || !pushed {
+		t.Fatalf("pushBranchIfNeeded = %v, %v", pushed, err)
+	}
+	if !(&git.Git{Dir: fork}).LocalBranchExists("feature") {
+		t.Error("feature was not pushed to the fork")
+	}
+	if (&git.Git{Dir: canonical}).LocalBranchExists("feature") {
+		t.Error("feature was pushed to upstream")
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("remote", "ls") })
+	if err != nil {
+		t.Fatalf("remote ls: %v", err)
+	}
+	if !strings.Contains(out, "origin") || !strings.Contains(out, "(push)") || !strings.Contains(out, "(upstream)") {
+		t.Errorf("remote ls output missing roles:\n%s", out)
+	}
+}
+
+func TestPRCreateFromForkTargetsUpstream(t *testing.T) {
+	_, _, fork := setupForkClone(t)
+	_, logPath := installFakeGH(t)
+	bareDir := filepath.Join(config.ReposDir(), "testrepo.git")
+	bareGit := &git.Git{Dir: bareDir}
+	mainBranch, _ := bareGit.DefaultBranch()
+
+	wtRoot := filepath.Join(config.WorktreesDir(), "testrepo")
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	commitFile(t, featureDir, "feature.txt", "feature\n", "feature")
+
+	// gh needs GitHub URLs; pushes still go to the local fork.
+	for _, args := range [][]string{
+		{"remote", "set-url", "upstream", "git@github.com:org/repo.git"},
+		{"remote", "set-url", "origin", "git@github.com:me/repo.git"},
+		{"config", "remote.origin.pushurl", fork},
+	} {
+		if _, err := bareGit.Run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	os.Chdir(featureDir)
+	if err := runApp("pr", "create"); err != nil {
+		t.Fatalf("pr create: %v", err)
+	}
+	lines := readLogLines(t, logPath)
+	if len(lines) != 2 || !strings.Contains(lines[1], "|"+mainBranch+"|me:feature|") {
+		t.Fatalf("gh log = %v, want a create with head me:feature", lines)
+	}
+}
+
+func TestRemoteAddSetsRoles(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "testrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	if err := runApp("remote", "add", "fork", origin, "--repo", "testrepo", "--push"); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+
+	bareDir := filepath.Join(config.ReposDir(), "testrepo.git")
+	cfg := config.Load(bareDir)
+	if cfg.Push() != "fork" || cfg.Upstream() != "origin" {
+		t.Fatalf("remotes = upstream %q, push %q", cfg.Upstream(), cfg.Push())
+	}
+	forkGit := &git.Git{Dir: bareDir, Remote: "fork"}
+	if branches, err := forkGit.RemoteBranches(); err != nil || len(branches) == 0 {
+		t.Errorf("fork was not fetched: %v, %v", branches, err)
+	}
+
+	if err := runApp("remote", "add", "fork", origin, "--repo", "testrepo"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("duplicate remote error = %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/iamrajjoshi/willow/internal/errors"
 	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/ports"
 	"github.com/iamrajjoshi/willow/internal/stack"
 	"github.com/iamrajjoshi/willow/internal/tmux"
 	"github.com/iamrajjoshi/willow/internal/trace"
@@ -280,6 +281,9 @@ func executeRenamePlan(ctx context.Context, tr *trace.Tracer, u interface{ Warn(
 		if _, err := repoGit.Run("worktree", "move", plan.OldPath, plan.NewPath); err != nil {
 			return fmt.Errorf("failed to move worktree: %w", err)
 		}
+		if err := ports.Move(plan.OldPath, plan.NewPath); err != nil {
+			u.Warn(fmt.Sprintf("Failed to move port allocation: %v", err))
+		}
 		done()
 	}
 
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/gh"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/stack"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/worktree"
+)
+
+// reparentedBranch records a child that moved off a merged stack parent.
+type reparentedBranch struct {
+	Branch string
+	From   string
+	To     string
+}
+
+type reparentOptions struct {
+	// Scope limits which children may be moved. Nil means every child.
+	Scope map[string]bool
+	// RefreshPRState allows gh lookups; otherwise only cached PR state is used.
+	RefreshPRState bool
+	DryRun         bool
+}
+
+// reparentMergedParents finds stacked parents whose current-head PR has
+// merged and moves their children onto the nearest unmerged ancestor (or the
+// base branch). The stack is updated in place and on disk. Callers rebase the
+// moved children with --onto, using the old parent as the upstream so the
+// merged commits are not replayed.
+func reparentMergedParents(repoGit *git.Git, bareDir string, st *stack.Stack, opts reparentOptions, u *ui.UI) ([]reparentedBranch, error) {
+	cfg := config.Load(bareDir)
+	baseBranch := repoGit.ResolveBaseBranch(cfg.BaseBranch)
+
+	branchHeads := make(map[string]string)
+	branchBases := make(map[string]string)
+	for _, branch := range st.TopoSort() {
+		if len(st.Children(branch)) == 0 {
+			continue
+		}
+		head, err := repoGit.RevParse("refs/heads/" + branch)
+		if err != nil {
+			continue
+		}
+		branchHeads[branch] = head
+		branchBases[branch] = st.Parent(branch)
+	}
+	if len(branchHeads) == 0 {
+		return nil, nil
+	}
+
+	ghDir, err := findGHDir(filepath.Join(config.WorktreesDir(), repoNameFromDir(bareDir)))
+	if err != nil {
+		return nil, nil
+	}
+	var merged map[string]bool
+	if opts.RefreshPRState {
+		merged = gh.MergedWorktreeSet(ghDir, baseBranch, branchHeads, branchBases)
+	} else {
+		merged = gh.CachedMergedWorktreeSet(ghDir, baseBranch, branchHeads, branchBases)
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	var moved []reparentedBranch
+	for _, parent := range st.TopoSort() {
+		if !merged[parent] {
+			continue
+		}
+		newParent := st.Parent(parent)
+		for merged[newParent] {
+			newParent = st.Parent(newParent)
+		}
+		for _, child := range st.Children(parent) {
+			if merged[child] || (opts.Scope != nil && !opts.Scope[child]) {
+				continue
+			}
+			moved = append(moved, reparentedBranch{Branch: child, From: parent, To: newParent})
+		}
+	}
+	if len(moved) == 0 || opts.DryRun {
+		return moved, nil
+	}
+
+	if err := stack.Update(bareDir, func(s *stack.Stack) {
+		for _, m := range moved {
+			s.SetParent(m.Branch, m.To)
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update stack: %w", err)
+	}
+
+	repoName := repoNameFromDir(bareDir)
+	for _, m := range moved {
+		st.SetParent(m.Branch, m.To)
+		u.Info(fmt.Sprintf("  %s %s: %s merged, now on %s", u.Green("↑"), u.Bold(m.Branch), m.From, m.To))
+		meta := map[string]string{"from": m.From, "to": m.To}
+		if *cfg.Defaults.RetargetPRs {
+			if number, err := retargetPR(repoGit, cfg, ghDir, m); err != nil {
+				u.Warn(fmt.Sprintf("    Failed to retarget PR for %s: %v", m.Branch, err))
+			} else if number != 0 {
+				u.Info(fmt.Sprintf("    Retargeted #%d to %s", number, m.To))
+				meta["pr"] = fmt.Sprintf("%d", number)
+			}
+		}
+		_ = log.Append(log.Event{Action: "reparent", Repo: repoName, Branch: m.Branch, Metadata: meta})
+	}
+	return moved, nil
+}
+
+// retargetPR points the child's open PR at its new parent. PRs that already
+// target something other than the merged parent are left alone. In a fork
+// workflow the PR lives on the upstream repo, as in ww pr. Returns the PR
+// number, or 0 when nothing was changed.
+func retargetPR(repoGit *git.Git, cfg *config.Config, ghDir string, m reparentedBranch) (int, error) {
+	prRepo, _, err := forkPRTarget(repoGit, cfg, m.Branch)
+	if err != nil {
+		return 0, err
+	}
+	pr, err := gh.FindOpenPR(ghDir, prRepo, m.Branch, "")
+	if err != nil || pr == nil {
+		return 0, err
+	}
+	if pr.BaseRefName != m.From {
+		return 0, nil
+	}
+	if err := gh.RetargetPR(ghDir, prRepo, pr.Number, m.To); err != nil {
+		return 0, err
+	}
+	return pr.Number, nil
+}
+
+// reparentAndRestack moves children off merged parents and rebases the moved
+// subtrees. gc --prune runs it once the user confirms, before removing the
+// merged parents.
+func reparentAndRestack(repoGit *git.Git, bareDir string, u *ui.UI) {
+	st := stack.Load(bareDir)
+	if st.IsEmpty() {
+		return
+	}
+	moved, err := reparentMergedParents(repoGit, bareDir, st, reparentOptions{RefreshPRState: true}, u)
+	if err != nil {
+		u.Warn(fmt.Sprintf("Skipping reparent for %s: %v", repoNameFromDir(bareDir), err))
+		return
+	}
+	if len(moved) == 0 {
+		return
+	}
+
+	if plan, _ := stack.LoadRestackPlan(bareDir); plan != nil {
+		u.Warn("A restack is already in progress; run 'ww sync --continue', then 'ww sync' to rebase reparented branches.")
+		return
+	}
+
+	oldParents := make(map[string]string, len(moved))
+	mergedParents := make(map[string]bool, len(moved))
+	inScope := make(map[string]bool)
+	for _, m := range moved {
+		oldParents[m.Branch] = m.From
+		mergedParents[m.From] = true
+		for _, branch := range st.SubtreeSort(m.Branch) {
+			inScope[branch] = true
+		}
+	}
+	var branches []string
+	for _, branch := range st.TopoSort() {
+		if inScope[branch] && !mergedParents[branch] {
+			branches = append(branches, branch)
+		}
+	}
+
+	wts, err := worktree.List(repoGit)
+	if err != nil {
+		u.Warn(fmt.Sprintf("Skipping restack: failed to list worktrees: %v", err))
+		return
+	}
+	wtPaths := make(map[string]string)
+	for _, wt := range wts {
+		if !wt.IsBare && !wt.Detached {
+			wtPaths[wt.Branch] = wt.Path
+		}
+	}
+
+	plan := newRestackPlan(repoGit, st, branches, oldParents)
+	if err := plan.Save(bareDir); err != nil {
+		u.Warn(fmt.Sprintf("Skipping restack: failed to save restack plan: %v", err))
+		return
+	}
+	u.Info(fmt.Sprintf("\nRestacking %d reparented worktree(s):\n", len(branches)))
+	if err := executeRestack(bareDir, st, plan, wtPaths, repoGit.Verbose, u); err != nil {
+		u.Warn(fmt.Sprintf("Restack failed: %v", err))
+		return
+	}
+	refreshStackTables(bareDir, u)
+}
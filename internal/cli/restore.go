@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/cleanup"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/stack"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/worktree"
+	"github.com/urfave/cli/v3"
+)
+
+func trashCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "trash",
+		Usage: "Inspect worktrees removed by ww rm",
+		Commands: []*cli.Command{
+			trashLsCmd(),
+		},
+	}
+}
+
+func trashLsCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "ls",
+		Aliases: []string{"list"},
+		Usage:   "List removed worktrees that ww restore can bring back",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Only show worktrees from this repo",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.trash.ls")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			entries, err := cleanup.ScanTrash(config.TrashDir())
+			if err != nil {
+				return err
+			}
+			entries = filterTrashByRepo(entries, cmd.String("repo"))
+			if len(entries) == 0 {
+				u.Info("Trash is empty.")
+				return nil
+			}
+			for _, line := range formatTrashLines(u, entries) {
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+}
+
+func restoreCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "restore",
+		Usage: "Bring back a worktree removed by ww rm",
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "name",
+				UsageText: "<name>",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Only match worktrees from this repo",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.restore")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			name := cmd.StringArg("name")
+			if name == "" {
+				return errors.Userf("name is required\n\nUsage: ww restore <name>\n\nRun 'ww trash ls' to see removed worktrees.")
+			}
+			entries, err := cleanup.ScanTrash(config.TrashDir())
+			if err != nil {
+				return err
+			}
+			entry, err := findTrashEntry(filterTrashByRepo(entries, cmd.String("repo")), name)
+			if err != nil {
+				return err
+			}
+			return restoreTrashEntry(u, entry, flags.Verbose)
+		},
+	}
+}
+
+func filterTrashByRepo(entries []cleanup.TrashEntry, repo string) []cleanup.TrashEntry {
+	if repo == "" {
+		return entries
+	}
+	var out []cleanup.TrashEntry
+	for _, e := range entries {
+		if e.Manifest != nil && e.Manifest.Repo == repo {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// findTrashEntry matches name against trash entry names, then against the
+// branch or directory of each restorable entry. When a worktree was removed
+// more than once, the most recent removal wins.
+func findTrashEntry(entries []cleanup.TrashEntry, name string) (cleanup.TrashEntry, error) {
+	for _, e := range entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		m := entries[i].Manifest
+		if m != nil && (m.Name() == name || filepath.Base(m.Path) == name) {
+			return entries[i], nil
+		}
+	}
+	return cleanup.TrashEntry{}, errors.Userf("no removed worktree matching %q\n\nRun 'ww trash ls' to see removed worktrees.", name)
+}
+
+func formatTrashLines(u *ui.UI, entries []cleanup.TrashEntry) []string {
+	type row struct {
+		name, repo, worktree, removed, size string
+	}
+	rows := []row{{name: "NAME", repo: "REPO", worktree: "WORKTREE", removed: "REMOVED", size: "SIZE"}}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		r := row{name: e.Name, repo: "-", worktree: "(no manifest)", removed: agent.TimeSince(e.Trashed), size: formatSize(e.Size)}
+		if e.Manifest != nil {
+			r.repo, r.worktree = e.Manifest.Repo, e.Manifest.Name()
+		}
+		rows = append(rows, r)
+	}
+
+	nameW, repoW, wtW, removedW := 0, 0, 0, 0
+	for _, r := range rows {
+		nameW = max(nameW, termfmt.VisibleWidth(r.name))
+		repoW = max(repoW, termfmt.VisibleWidth(r.repo))
+		wtW = max(wtW, termfmt.VisibleWidth(r.worktree))
+		removedW = max(removedW, termfmt.VisibleWidth(r.removed))
+	}
+	lines := make([]string, 0, len(rows))
+	for i, r := range rows {
+		line := fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %s", nameW, r.name, repoW, r.repo, wtW, r.worktree, removedW, r.removed, r.size)
+		if i == 0 {
+			line = u.Bold(line)
+		} else if r.repo == "-" {
+			line = u.Dim(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// restoreTrashEntry moves a trashed worktree and its git admin dir back,
+// recreating the branch at its recorded head if it was deleted, and puts
+// back its stack parent and agent status.
+func restoreTrashEntry(u *ui.UI, entry cleanup.TrashEntry, verbose bool) error {
+	m := entry.Manifest
+	if m == nil {
+		return errors.Userf("%s has no manifest and can't be restored (it was removed by an older willow)", entry.Name)
+	}
+	wtSrc := filepath.Join(entry.Path, cleanup.TrashWorktreeDir)
+	gitSrc := filepath.Join(entry.Path, cleanup.TrashGitDir)
+	if !pathExists(wtSrc) || !pathExists(gitSrc) {
+		return errors.Userf("%s is incomplete and can't be restored", entry.Name)
+	}
+	if !pathExists(m.BareDir) {
+		return errors.Userf("repo %s no longer exists at %s", m.Repo, m.BareDir)
+	}
+	if pathExists(m.Path) {
+		return errors.Userf("%s already exists", m.Path)
+	}
+
+	repoGit := &git.Git{Dir: m.BareDir, Verbose: verbose}
+	label := m.Name()
+	if !m.Detached {
+		wts, err := worktree.List(repoGit)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		for _, wt := range wts {
+			if wt.Branch == m.Branch {
+				return errors.Userf("branch %s is already checked out at %s", m.Branch, wt.Path)
+			}
+		}
+		if !repoGit.LocalBranchExists(m.Branch) {
+			if _, err := repoGit.Run("branch", m.Branch, m.Head); err != nil {
+				return fmt.Errorf("failed to recreate branch %s at %s: %w", m.Branch, worktree.ShortHead(m.Head), err)
+			}
+			u.Info(fmt.Sprintf("Recreated branch %s at %s", u.Bold(m.Branch), worktree.ShortHead(m.Head)))
+		}
+	}
+
+	// Git names admin dirs after the worktree directory, adding a numeric
+	// suffix when the name is taken.
+	adminRoot := filepath.Join(m.BareDir, "worktrees")
+	adminDir := filepath.Join(adminRoot, filepath.Base(m.Path))
+	for i := 1; pathExists(adminDir); i++ {
+		adminDir = filepath.Join(adminRoot, filepath.Base(m.Path)+strconv.Itoa(i))
+	}
+	if err := os.MkdirAll(adminRoot, 0o755); err != nil {
+		return fmt.Errorf("failed to create worktree admin root: %w", err)
+	}
+	if err := os.Rename(gitSrc, adminDir); err != nil {
+		return fmt.Errorf("failed to restore worktree admin dir: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.Path), 0o755); err != nil {
+		_ = os.Rename(adminDir, gitSrc)
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(m.Path), err)
+	}
+	if err := os.Rename(wtSrc, m.Path); err != nil {
+		_ = os.Rename(adminDir, gitSrc)
+		return fmt.Errorf("failed to restore worktree: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, "gitdir"), []byte(filepath.Join(m.Path, ".git")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to link worktree admin dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Path, ".git"), []byte("gitdir: "+adminDir+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to link worktree admin dir: %w", err)
+	}
+
+	statusSrc := filepath.Join(entry.Path, cleanup.TrashStatusDir)
+	statusDest := agent.StatusWorktreeDir(m.Repo, filepath.Base(m.Path))
+	if pathExists(statusSrc) && !pathExists(statusDest) {
+		if err := os.MkdirAll(filepath.Dir(statusDest), 0o755); err == nil {
+			_ = os.Rename(statusSrc, statusDest)
+		}
+	}
+
+	if m.StackParent != "" {
+		if err := stack.Update(m.BareDir, func(s *stack.Stack) {
+			s.SetParent(m.Branch, m.StackParent)
+		}); err != nil {
+			u.Warn(fmt.Sprintf("Failed to save stack: %v", err))
+		}
+	}
+
+	if err := os.RemoveAll(entry.Path); err != nil {
+		u.Warn(fmt.Sprintf("Failed to remove trash entry %s: %v", entry.Name, err))
+	}
+	_ = log.Append(log.Event{Action: "restore", Repo: m.Repo, Branch: label, Metadata: map[string]string{"trash": entry.Name}})
+
+	u.Success(fmt.Sprintf("Restored %s to %s", u.Bold(label), m.Path))
+	return nil
+}
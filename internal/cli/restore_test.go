@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/stack"
+)
+
+func TestRestoreBringsBackRemovedWorktree(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "trashrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "trashrepo.git")
+	bareGit := &git.Git{Dir: bareDir}
+	mainBranch, _ := bareGit.DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "trashrepo")
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	commitFile(t, featureDir, "feature.txt", "feature\n", "feature work")
+	head, _ := (&git.Git{Dir: featureDir}).Run("rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(featureDir, "notes.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	statusDir := agent.StatusWorktreeDir("trashrepo", "feature")
+	if err := os.MkdirAll(statusDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("rm", "feature", "--force"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if bareGit.LocalBranchExists("feature") || pathExists(statusDir) {
+		t.Fatal("rm should delete the branch and move the status dir")
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("trash", "ls") })
+	if err != nil {
+		t.Fatalf("trash ls: %v", err)
+	}
+	if !strings.Contains(out, "trashrepo") || !strings.Contains(out, "feature") {
+		t.Fatalf("trash ls output missing entry:\n%s", out)
+	}
+
+	if err := runApp("restore", "feature"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	featureGit := &git.Git{Dir: featureDir}
+	if branch, err := featureGit.Run("symbolic-ref", "--short", "HEAD"); err != nil || branch != "feature" {
+		t.Fatalf("restored branch = %q, %v", branch, err)
+	}
+	if got, _ := bareGit.Run("rev-parse", "refs/heads/feature"); got != head {
+		t.Errorf("branch recreated at %s, want %s", got, head)
+	}
+	if status, err := featureGit.Run("status", "--porcelain"); err != nil || status != "?? notes.txt" {
+		t.Errorf("status = %q, %v; want only the untracked notes.txt", status, err)
+	}
+	if out, err := bareGit.Run("worktree", "list", "--porcelain"); err != nil || !strings.Contains(out, featureDir) {
+		t.Errorf("worktree not registered with git: %q, %v", out, err)
+	}
+	if !pathExists(statusDir) {
+		t.Error("status dir was not restored")
+	}
+	if parent := stack.Load(bareDir).Parent("feature"); parent != mainBranch {
+		t.Errorf("stack parent = %q, want %q", parent, mainBranch)
+	}
+	if entries, _ := os.ReadDir(config.TrashDir()); len(entries) != 0 {
+		t.Errorf("trash still has %d entries", len(entries))
+	}
+
+	logOut, err := captureStdout(t, func() error { return runApp("log", "--json") })
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if !strings.Contains(logOut, `"action": "restore"`) {
+		t.Errorf("restore not in activity log:\n%s", logOut)
+	}
+}
+
+func TestRestoreRejectsUnknownAndOccupiedTargets(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "trashrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "trashrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "trashrepo")
+
+	if err := runApp("restore", "nope"); err == nil || !strings.Contains(err.Error(), "no removed worktree") {
+		t.Fatalf("restore unknown error = %v", err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := runApp("rm", "feature", "--force", "--keep-branch"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if err := runApp("new", "other", "--no-fetch"); err != nil {
+		t.Fatalf("new other: %v", err)
+	}
+	if err := os.Rename(filepath.Join(wtRoot, "other"), filepath.Join(wtRoot, "feature")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runApp("restore", "feature")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("restore onto occupied path error = %v", err)
+	}
+	if entries, _ := os.ReadDir(config.TrashDir()); len(entries) != 1 {
+		t.Errorf("failed restore should leave the trash entry, have %d entries", len(entries))
+	}
+}
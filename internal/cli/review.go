@@ -0,0 +1,470 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/cleanup"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/fzf"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/stack"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+func reviewCmd() *cli.Command {
+	return &cli.Command{
+		Name:          "review",
+		Usage:         "Summarize what an agent did in a worktree: session timeline, last turn's tools, and diff",
+		ShellComplete: completeWorktreesWithFlag,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "target",
+				UsageText: "[worktree]",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.StringFlag{
+				Name:  "session",
+				Usage: "Review this session (ID or prefix) instead of the most recent one",
+			},
+			&cli.BoolFlag{
+				Name:    "files",
+				Aliases: []string{"f"},
+				Usage:   "Pick changed files with fzf and page each file's diff",
+			},
+			&cli.BoolFlag{
+				Name:  "no-pager",
+				Usage: "Print the review instead of paging it",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output the review as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.review")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			rwt, err := resolveWorktreeTarget(g, cmd.String("repo"), cmd.StringArg("target"))
+			if err != nil {
+				return err
+			}
+			report, err := buildReview(rwt, cmd.String("session"), g.Verbose)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+
+			paged := !cmd.Bool("no-pager") && term.IsTerminal(int(os.Stdout.Fd()))
+			if cmd.Bool("files") {
+				return pickReviewFiles(report, paged)
+			}
+			diff := report.Diff
+			if paged {
+				if diff, err = reviewDiff(report.Path, report.MergeBase, report.Files, true); err != nil {
+					return err
+				}
+			}
+			text := strings.Join(formatReview(u, report), "\n") + "\n"
+			if diff != "" {
+				text += "\n" + diff
+			}
+			return writePaged(text, paged)
+		},
+	}
+}
+
+type reviewReport struct {
+	Repo          string         `json:"repo"`
+	Worktree      string         `json:"worktree"`
+	Branch        string         `json:"branch,omitempty"`
+	Path          string         `json:"path"`
+	Base          string         `json:"base"`
+	MergeBase     string         `json:"merge_base"`
+	Session       *reviewSession `json:"session,omitempty"`
+	Timeline      []reviewEvent  `json:"timeline"`
+	LastTurnTools []string       `json:"last_turn_tools"`
+	FilesTouched  []string       `json:"files_touched"`
+	Files         []reviewFile   `json:"files"`
+	Diff          string         `json:"diff"`
+}
+
+type reviewSession struct {
+	ID        string       `json:"id"`
+	Harness   string       `json:"harness"`
+	Status    agent.Status `json:"status"`
+	Model     string       `json:"model,omitempty"`
+	StartTime time.Time    `json:"start_time"`
+	Updated   time.Time    `json:"updated"`
+}
+
+type reviewEvent struct {
+	Status agent.Status `json:"status"`
+	Time   time.Time    `json:"time"`
+}
+
+// reviewFile is a file changed since the merge base. Untracked files have no
+// line counts.
+type reviewFile struct {
+	Path      string `json:"path"`
+	Added     int    `json:"added"`
+	Deleted   int    `json:"deleted"`
+	Untracked bool   `json:"untracked,omitempty"`
+	Binary    bool   `json:"binary,omitempty"`
+	Touched   bool   `json:"touched"`
+}
+
+// buildReview collects the agent activity and the changes in rwt. The diff
+// runs from the merge base with the stack parent, or the base branch for
+// unstacked branches, to the working tree, so uncommitted edits count.
+func buildReview(rwt *repoWorktree, sessionID string, verbose bool) (*reviewReport, error) {
+	wt := rwt.Worktree
+	report := &reviewReport{
+		Repo:     rwt.Repo.Name,
+		Worktree: wt.MatchName(),
+		Path:     wt.Path,
+	}
+	if !wt.Detached {
+		report.Branch = wt.Branch
+	}
+
+	cfg := config.Load(rwt.Repo.BareDir)
+	repoGit := &git.Git{Dir: rwt.Repo.BareDir, Remote: cfg.Upstream(), Verbose: verbose}
+	wtGit := &git.Git{Dir: wt.Path, Verbose: verbose}
+	st := stack.Load(rwt.Repo.BareDir)
+	report.Base = prBaseBranch(st, repoGit, cfg, wt.Branch)
+	// The bare repo's local base branch is never fast-forwarded by fetch, so
+	// diff against the upstream remote-tracking ref unless stacked.
+	baseRef := cleanup.ExpectedBaseRef(repoGit, st, repoGit.ResolveBaseBranch(cfg.BaseBranch), wt.Branch)
+	if baseRef == "" {
+		baseRef = report.Base
+	}
+	mergeBase, err := wtGit.MergeBase(baseRef, "HEAD")
+	if err != nil {
+		mergeBase, err = wtGit.MergeBase(report.Base, "HEAD")
+	}
+	if err != nil {
+		return nil, errors.Userf("can't find where %s branched from %s", wt.DisplayName(), report.Base)
+	}
+	report.MergeBase = mergeBase
+
+	wtDir := filepath.Base(wt.Path)
+	if ss := pickReviewSession(agent.ReadAllSessions(rwt.Repo.Name, wtDir), sessionID); ss != nil {
+		report.Session = &reviewSession{
+			ID:        ss.SessionID,
+			Harness:   ss.Harness,
+			Status:    agent.EffectiveStatus(ss.Status, ss.Timestamp),
+			Model:     ss.Model,
+			StartTime: ss.StartTime,
+			Updated:   ss.Timestamp,
+		}
+		timeline, _ := agent.ReadTimeline(rwt.Repo.Name, wtDir, ss.SessionID, time.Time{})
+		for _, e := range timeline {
+			report.Timeline = append(report.Timeline, reviewEvent{Status: e.Status, Time: e.Time})
+		}
+		tools, _ := agent.ReadTools(rwt.Repo.Name, wtDir, ss.SessionID, agent.LastTurnStart(timeline))
+		for _, e := range tools {
+			report.LastTurnTools = append(report.LastTurnTools, e.Tool)
+		}
+		for _, f := range agent.ReadFilesTouched(rwt.Repo.Name, wtDir, ss.SessionID) {
+			if rel, err := filepath.Rel(wt.Path, f); err == nil && !strings.HasPrefix(rel, "..") {
+				f = rel
+			}
+			report.FilesTouched = append(report.FilesTouched, f)
+		}
+	} else if sessionID != "" {
+		return nil, errors.Userf("no session matching %q in %s", sessionID, wt.DisplayName())
+	}
+
+	report.Files, err = reviewFiles(wtGit, mergeBase, report.FilesTouched)
+	if err != nil {
+		return nil, err
+	}
+	report.Diff, err = reviewDiff(wt.Path, mergeBase, report.Files, false)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// pickReviewSession returns the session whose ID starts with id, or the most
+// recently updated session when id is empty.
+func pickReviewSession(sessions []*agent.SessionStatus, id string) *agent.SessionStatus {
+	var best *agent.SessionStatus
+	for _, ss := range sessions {
+		if id != "" {
+			if strings.HasPrefix(ss.SessionID, id) {
+				return ss
+			}
+			continue
+		}
+		if best == nil || ss.Timestamp.After(best.Timestamp) {
+			best = ss
+		}
+	}
+	return best
+}
+
+func reviewFiles(wtGit *git.Git, mergeBase string, touched []string) ([]reviewFile, error) {
+	touchedSet := make(map[string]bool, len(touched))
+	for _, f := range touched {
+		touchedSet[f] = true
+	}
+
+	numstat, err := wtGit.Run("diff", "--numstat", "--no-renames", mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", mergeBase, err)
+	}
+	var files []reviewFile
+	for _, line := range strings.Split(numstat, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := reviewFile{Path: fields[2], Touched: touchedSet[fields[2]]}
+		if fields[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, f)
+	}
+
+	untracked, err := wtGit.Run("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, path := range strings.Split(untracked, "\n") {
+		if path != "" {
+			files = append(files, reviewFile{Path: path, Untracked: true, Touched: touchedSet[path]})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// reviewDiff returns the diff of files from mergeBase to the working tree.
+// Untracked files are diffed against /dev/null.
+func reviewDiff(wtPath, mergeBase string, files []reviewFile, color bool) (string, error) {
+	colorFlag := "--color=never"
+	if color {
+		colorFlag = "--color=always"
+	}
+	var tracked, untracked []string
+	for _, f := range files {
+		if f.Untracked {
+			untracked = append(untracked, f.Path)
+		} else {
+			tracked = append(tracked, f.Path)
+		}
+	}
+
+	var out strings.Builder
+	if len(tracked) > 0 {
+		c := exec.Command("git", append([]string{"diff", "--no-renames", colorFlag, mergeBase, "--"}, tracked...)...)
+		c.Dir = wtPath
+		data, err := c.Output()
+		if err != nil {
+			return "", fmt.Errorf("git diff %s: %w", mergeBase, err)
+		}
+		out.Write(data)
+	}
+	for _, path := range untracked {
+		// --no-index exits 1 when the files differ, which they always do here.
+		c := exec.Command("git", "diff", "--no-index", colorFlag, "--", os.DevNull, path)
+		c.Dir = wtPath
+		data, _ := c.Output()
+		out.Write(data)
+	}
+	return out.String(), nil
+}
+
+func formatReview(u *ui.UI, r *reviewReport) []string {
+	title := fmt.Sprintf("Review of %s in %s against %s", u.Bold(r.Worktree), r.Repo, u.Bold(r.Base))
+	lines := []string{title, u.Dim(r.Path), ""}
+
+	if s := r.Session; s != nil {
+		session := fmt.Sprintf("[%s] %s  %s %s  updated %s", s.Harness, agent.ShortSessionID(s.ID), agent.StatusIcon(s.Status), agent.StatusLabel(s.Status), agent.TimeSince(s.Updated))
+		if s.Model != "" {
+			session += "  " + u.Dim(s.Model)
+		}
+		lines = append(lines, u.Bold("Session")+"  "+session)
+
+		if len(r.Timeline) > 0 {
+			lines = append(lines, "", u.Bold("Timeline"))
+			for i, e := range r.Timeline {
+				end := s.Updated
+				if i+1 < len(r.Timeline) {
+					end = r.Timeline[i+1].Time
+				}
+				lines = append(lines, fmt.Sprintf("  %s  %s %-4s  %s", e.Time.Local().Format("Jan 02 15:04"), agent.StatusIcon(e.Status), agent.StatusLabel(e.Status), u.Dim(end.Sub(e.Time).Round(time.Second).String())))
+			}
+		}
+
+		lines = append(lines, "", u.Bold("Last turn"))
+		if len(r.LastTurnTools) == 0 {
+			lines = append(lines, u.Dim("  no tool calls recorded"))
+		} else {
+			lines = append(lines, "  "+summarizeTools(r.LastTurnTools))
+		}
+	} else {
+		lines = append(lines, u.Dim("No agent session recorded for this worktree."))
+	}
+
+	added, deleted := 0, 0
+	for _, f := range r.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	lines = append(lines, "", u.Bold("Files")+fmt.Sprintf("  %d changed, %s %s", len(r.Files), u.Green(fmt.Sprintf("+%d", added)), u.Red(fmt.Sprintf("-%d", deleted))))
+	if len(r.Files) == 0 {
+		lines = append(lines, u.Dim("  no changes since "+r.Base))
+	}
+	pathW := 0
+	for _, f := range r.Files {
+		pathW = max(pathW, len(f.Path))
+	}
+	changed := make(map[string]bool, len(r.Files))
+	for _, f := range r.Files {
+		changed[f.Path] = true
+		stat := fmt.Sprintf("%s %s", u.Green(fmt.Sprintf("+%d", f.Added)), u.Red(fmt.Sprintf("-%d", f.Deleted)))
+		switch {
+		case f.Untracked:
+			stat = u.Green("new")
+		case f.Binary:
+			stat = u.Dim("binary")
+		}
+		marker := " "
+		if f.Touched {
+			marker = u.Cyan("●")
+		}
+		lines = append(lines, fmt.Sprintf("  %s %-*s  %s", marker, pathW, f.Path, stat))
+	}
+	var unchanged []string
+	for _, f := range r.FilesTouched {
+		if !changed[f] {
+			unchanged = append(unchanged, f)
+		}
+	}
+	if len(r.Files) > 0 && r.Session != nil {
+		lines = append(lines, u.Dim("  ● touched by the agent"))
+	}
+	if len(unchanged) > 0 {
+		lines = append(lines, u.Dim("  touched but unchanged: "+strings.Join(unchanged, ", ")))
+	}
+	return lines
+}
+
+// summarizeTools collapses tool calls into "Read ×3, Edit ×2" in order of
+// first use.
+func summarizeTools(tools []string) string {
+	counts := map[string]int{}
+	var order []string
+	for _, t := range tools {
+		if counts[t] == 0 {
+			order = append(order, t)
+		}
+		counts[t]++
+	}
+	parts := make([]string, len(order))
+	for i, t := range order {
+		parts[i] = t
+		if counts[t] > 1 {
+			parts[i] += fmt.Sprintf(" ×%d", counts[t])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pickReviewFiles lets the user pick changed files with a diff preview and
+// pages the diff of each selection until the picker is cancelled.
+func pickReviewFiles(r *reviewReport, paged bool) error {
+	if len(r.Files) == 0 {
+		return errors.Userf("no changes in %s since %s", r.Worktree, r.Base)
+	}
+	lines := make([]string, len(r.Files))
+	byPath := make(map[string]reviewFile, len(r.Files))
+	for i, f := range r.Files {
+		lines[i] = f.Path
+		byPath[f.Path] = f
+	}
+	preview := fmt.Sprintf("cd %s && { git diff --no-renames --color=always %s -- {}; git ls-files --error-unmatch -- {} >/dev/null 2>&1 || git diff --no-index --color=always -- /dev/null {}; }", shellQuote(r.Path), r.MergeBase)
+
+	for {
+		selected, err := fzf.RunMulti(lines,
+			fzf.WithReverse(),
+			fzf.WithHeader(fmt.Sprintf("%s against %s  (enter: view diff, tab: select several, esc: quit)", r.Worktree, r.Base)),
+			fzf.WithPreview(preview, "right:60%"),
+		)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			return nil
+		}
+		files := make([]reviewFile, 0, len(selected))
+		for _, path := range selected {
+			files = append(files, byPath[path])
+		}
+		diff, err := reviewDiff(r.Path, r.MergeBase, files, paged)
+		if err != nil {
+			return err
+		}
+		if err := writePaged(diff, paged); err != nil {
+			return err
+		}
+		if !paged {
+			return nil
+		}
+	}
+}
+
+// writePaged writes text through $PAGER (less by default) when paged is set,
+// and straight to stdout otherwise.
+func writePaged(text string, paged bool) error {
+	if !paged {
+		_, err := os.Stdout.WriteString(text)
+		return err
+	}
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	c := exec.Command("sh", "-c", pager)
+	c.Stdin = strings.NewReader(text)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		// Keep colors, and skip the pager when the review fits on one screen.
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+	return c.Run()
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestReviewSummarizesSessionAndDiff(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "reviewrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "reviewrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "reviewrepo")
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	commitFile(t, featureDir, "parent.txt", "parent\n", "parent work")
+	if err := runApp("new", "child", "--base", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new child: %v", err)
+	}
+	childDir := filepath.Join(wtRoot, "child")
+	commitFile(t, childDir, "committed.txt", "one\ntwo\n", "child work")
+	if err := os.WriteFile(filepath.Join(childDir, "parent.txt"), []byte("parent\nedited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(childDir, "new.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeActiveSessionFile(t, "reviewrepo", "child", "sess1", agent.StatusDone)
+	sessionDir := agent.SessionDir("reviewrepo", "child", "claude")
+	start := time.Now().Add(-10 * time.Minute)
+	timeline := ""
+	for i, s := range []agent.Status{agent.StatusBusy, agent.StatusDone, agent.StatusBusy, agent.StatusDone} {
+		timeline += fmt.Sprintf(`{"s":%q,"t":%q}`+"\n", s, start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339Nano))
+	}
+	tools := fmt.Sprintf(`{"tool":"Read","t":%q}`+"\n"+`{"tool":"Edit","t":%q}`+"\n"+`{"tool":"Edit","t":%q}`+"\n",
+		start.Add(30*time.Second).Format(time.RFC3339Nano),
+		start.Add(150*time.Second).Format(time.RFC3339Nano),
+		start.Add(160*time.Second).Format(time.RFC3339Nano))
+	files := filepath.Join(childDir, "parent.txt") + "\n" + filepath.Join(childDir, "gone.txt") + "\n"
+	for name, data := range map[string]string{"sess1.timeline": timeline, "sess1.tools": tools, "sess1.files": files} {
+		if err := os.WriteFile(filepath.Join(sessionDir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.Chdir(childDir)
+	out, err := captureStdout(t, func() error { return runApp("review", "--json") })
+	if err != nil {
+		t.Fatalf("review --json: %v", err)
+	}
+	var report reviewReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("parse review --json: %v\n%s", err, out)
+	}
+	if report.Base != "feature" || report.Session == nil || report.Session.ID != "sess1" {
+		t.Fatalf("report = %+v, want base feature and session sess1", report)
+	}
+	if len(report.Timeline) != 4 {
+		t.Errorf("timeline has %d entries, want 4", len(report.Timeline))
+	}
+	if strings.Join(report.LastTurnTools, ",") != "Edit,Edit" {
+		t.Errorf("last turn tools = %v, want [Edit Edit]", report.LastTurnTools)
+	}
+	var paths []string
+	for _, f := range report.Files {
+		paths = append(paths, fmt.Sprintf("%s:%v:%v", f.Path, f.Untracked, f.Touched))
+	}
+	if got := strings.Join(paths, " "); got != "committed.txt:false:false new.txt:true:false parent.txt:false:true" {
+		t.Errorf("files = %s", got)
+	}
+	for _, want := range []string{"+edited", "+new", "+two"} {
+		if !strings.Contains(report.Diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, report.Diff)
+		}
+	}
+
+	out, err = captureStdout(t, func() error { return runApp("review", "child", "--no-pager") })
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	for _, want := range []string{"Edit ×2", "gone.txt", "3 changed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("review output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReviewDiffsAgainstUpstreamBase(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "reviewrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "reviewrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "reviewrepo")
+
+	// origin's main moves ahead; the fetch below updates origin/main but
+	// leaves the bare repo's local main behind.
+	workdir := filepath.Join(os.Getenv("HOME"), "workdir")
+	commitFile(t, workdir, "upstream.txt", "upstream\n", "upstream work")
+	if _, err := (&git.Git{Dir: workdir}).Run("push", "origin", "HEAD"); err != nil {
+		t.Fatalf("git push: %v", err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+	commitFile(t, featureDir, "agent.txt", "agent\n", "agent work")
+
+	os.Chdir(featureDir)
+	out, err := captureStdout(t, func() error { return runApp("review", "--json") })
+	if err != nil {
+		t.Fatalf("review --json: %v", err)
+	}
+	var report reviewReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("parse review --json: %v\n%s", err, out)
+	}
+	if len(report.Files) != 1 || report.Files[0].Path != "agent.txt" {
+		t.Errorf("files = %+v, want only agent.txt", report.Files)
+	}
+	if strings.Contains(report.Diff, "upstream") {
+		t.Errorf("diff includes upstream commit:\n%s", report.Diff)
+	}
+}
@@ -4,18 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/cleanup"
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/errors"
 	"github.com/iamrajjoshi/willow/internal/fzf"
 	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/ports"
 	"github.com/iamrajjoshi/willow/internal/stack"
 	"github.com/iamrajjoshi/willow/internal/trace"
 	"github.com/iamrajjoshi/willow/internal/ui"
@@ -249,10 +249,11 @@ func removeWorktree(ctx context.Context, tr *trace.Tracer, u *ui.UI, repoGit *gi
 		}
 	}
 
+	cfg = withWorktreeProfile(cfg, wt.Path, u)
 	if len(cfg.Teardown) > 0 {
 		done := tr.StartCtx(ctx, "teardown hooks "+label)
 		u.Info(fmt.Sprintf("Running teardown hooks for %s...", u.Bold(label)))
-		if err := runHooks(cfg.Teardown, wt.Path, u, os.Stdout); err != nil {
+		if err := runHooks(cfg.Teardown, wt.Path, worktreeEnv(cfg, wt.Path, u), u, os.Stdout); err != nil {
 			return err
 		}
 		done()
@@ -267,32 +268,39 @@ func removeWorktree(ctx context.Context, tr *trace.Tracer, u *ui.UI, repoGit *gi
 		done()
 		return fmt.Errorf("failed to read worktree git admin dir: %w", err)
 	}
+	head := wt.Head
+	if head == "" {
+		head, _ = wtGit.Run("rev-parse", "HEAD")
+	}
 
 	// Move worktree to trash first (reversible — admin dir still intact).
-	// Then remove the admin dir. This order ensures consistent state on
-	// partial failure: if the move fails, git still tracks the worktree and
-	// the user can retry.
+	// Then move the admin dir after it. This order ensures consistent state
+	// on partial failure: if the move fails, git still tracks the worktree
+	// and the user can retry.
 	trashDir := config.TrashDir()
 	if err := os.MkdirAll(trashDir, 0o755); err != nil {
 		done()
 		return fmt.Errorf("failed to create trash dir: %w", err)
 	}
 
-	trashDest := filepath.Join(trashDir, fmt.Sprintf("%d-%s", time.Now().UnixNano(), filepath.Base(wt.Path)))
-	if err := os.Rename(wt.Path, trashDest); err != nil {
+	removedAt := time.Now()
+	trashDest := filepath.Join(trashDir, fmt.Sprintf("%d-%s", removedAt.UnixNano(), filepath.Base(wt.Path)))
+	trashed := os.Mkdir(trashDest, 0o755) == nil &&
+		os.Rename(wt.Path, filepath.Join(trashDest, cleanup.TrashWorktreeDir)) == nil
+	if !trashed {
+		_ = os.RemoveAll(trashDest)
 		if removeErr := os.RemoveAll(wt.Path); removeErr != nil {
 			done()
 			return fmt.Errorf("failed to remove worktree: %w", removeErr)
 		}
-	} else {
-		bgRm := exec.Command("rm", "-rf", trashDest)
-		bgRm.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-		_ = bgRm.Start()
 	}
 
-	if err := os.RemoveAll(adminDir); err != nil {
-		done()
-		return fmt.Errorf("failed to remove worktree admin dir: %w", err)
+	// Keeping the admin dir preserves the index and HEAD for ww restore.
+	if !trashed || os.Rename(adminDir, filepath.Join(trashDest, cleanup.TrashGitDir)) != nil {
+		if err := os.RemoveAll(adminDir); err != nil {
+			done()
+			return fmt.Errorf("failed to remove worktree admin dir: %w", err)
+		}
 	}
 	done()
 
@@ -307,9 +315,34 @@ func removeWorktree(ctx context.Context, tr *trace.Tracer, u *ui.UI, repoGit *gi
 	done = tr.StartCtx(ctx, "cleanup status "+label)
 	repoName := repoNameFromDir(bareDir)
 	wtDir := filepath.Base(wt.Path)
-	agent.RemoveStatusDir(repoName, wtDir)
+	statusDir := agent.StatusWorktreeDir(repoName, wtDir)
+	_ = agent.ArchiveWorktreeSessions(repoName, wtDir)
+	if !trashed || os.Rename(statusDir, filepath.Join(trashDest, cleanup.TrashStatusDir)) != nil {
+		agent.RemoveStatusDir(repoName, wtDir)
+	}
+	if err := ports.Release(wt.Path); err != nil {
+		u.Warn(fmt.Sprintf("Failed to release ports: %v", err))
+	}
 	done()
 
+	if trashed {
+		manifest := &cleanup.TrashManifest{
+			Repo:      repoName,
+			BareDir:   bareDir,
+			Path:      wt.Path,
+			Branch:    wt.Branch,
+			Head:      head,
+			Detached:  wt.Detached,
+			RemovedAt: removedAt,
+		}
+		if !wt.Detached {
+			manifest.StackParent = st.Parent(wt.Branch)
+		}
+		if err := cleanup.WriteTrashManifest(trashDest, manifest); err != nil {
+			u.Warn(fmt.Sprintf("Failed to write trash manifest: %v", err))
+		}
+	}
+
 	if !wt.Detached && st.IsTracked(wt.Branch) {
 		if err := stack.Update(bareDir, func(s *stack.Stack) {
 			s.Remove(wt.Branch)
@@ -4,10 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/iamrajjoshi/willow/internal/agent"
 	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/trace"
 	"github.com/iamrajjoshi/willow/internal/ui"
 	"github.com/iamrajjoshi/willow/internal/worktree"
@@ -53,6 +56,54 @@ func TestRmCommandPickerSingleRepoRemovesSelectedWorktree(t *testing.T) {
 	}
 }
 
+func TestRmArchivesLiveSessions(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "rmarchive"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "rmarchive.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	os.Chdir(filepath.Join(config.WorktreesDir(), "rmarchive", mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	writeActiveSessionFile(t, "rmarchive", "feature", "sess-busy", agent.StatusBusy)
+	writeActiveSessionFile(t, "rmarchive", "feature", "sess-done", agent.StatusDone)
+
+	archivedIDs := func() []string {
+		t.Helper()
+		archived, err := agent.ReadArchive(agent.ArchiveReadOpts{Repo: "rmarchive"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, a := range archived {
+			ids = append(ids, a.SessionID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	if err := runApp("rm", "feature", "--force", "--keep-branch"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if got := strings.Join(archivedIDs(), ","); got != "sess-busy,sess-done" {
+		t.Fatalf("archived sessions = %s, want sess-busy,sess-done", got)
+	}
+
+	// Restoring brings the sessions back; removing again replaces their
+	// archives rather than adding duplicates.
+	if err := runApp("restore", "feature"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := runApp("rm", "feature", "--force", "--keep-branch"); err != nil {
+		t.Fatalf("rm again: %v", err)
+	}
+	if got := strings.Join(archivedIDs(), ","); got != "sess-busy,sess-done" {
+		t.Errorf("archived sessions after second rm = %s", got)
+	}
+}
+
 func TestRmCommandPickerMultiRepoRemovesSelectedWorktree(t *testing.T) {
 	origin := setupTestEnv(t)
 	home, _ := os.UserHomeDir()
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/server"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/urfave/cli/v3"
+)
+
+func serveCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serve worktrees, agent sessions, and the activity log over a local HTTP/JSON API",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "socket",
+				Usage: "Unix socket path (default: <willow-base>/willow.sock)",
+			},
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "Listen on a loopback TCP address instead (e.g. 127.0.0.1:7463)",
+			},
+			&cli.IntFlag{
+				Name:  "interval",
+				Usage: "Status poll interval for the event stream, in milliseconds",
+				Value: 1000,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.serve")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			ln, where, err := serveListener(cmd.String("socket"), cmd.String("addr"))
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			u.Info(fmt.Sprintf("Serving willow API on %s (Ctrl-C to stop)", u.Bold(where)))
+			srv := server.New(server.Options{
+				PollInterval: time.Duration(cmd.Int("interval")) * time.Millisecond,
+			})
+			return srv.Serve(ctx, ln)
+		},
+	}
+}
+
+// serveListener opens the API listener. TCP is restricted to loopback
+// addresses: the API exposes local paths and session data without auth.
+func serveListener(socket, addr string) (net.Listener, string, error) {
+	if addr != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, "", errors.Userf("invalid --addr %q: %v", addr, err)
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return nil, "", errors.Userf("--addr must be a loopback address (127.0.0.1, ::1, or localhost), got %q", host)
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		return ln, "http://" + ln.Addr().String(), nil
+	}
+
+	if socket == "" {
+		socket = server.DefaultSocketPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0o755); err != nil {
+		return nil, "", fmt.Errorf("failed to create socket dir: %w", err)
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, "", errors.Userf("another 'ww serve' is already listening on %s", socket)
+	}
+	// Nothing answered, so any existing file is left over from a crashed run.
+	_ = os.Remove(socket)
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	if err := os.Chmod(socket, 0o600); err != nil {
+		ln.Close()
+		return nil, "", fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return ln, socket, nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeListenerRejectsNonLoopbackAddr(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:7463", "192.168.1.10:7463", ":7463"} {
+		if _, _, err := serveListener("", addr); err == nil || !strings.Contains(err.Error(), "loopback") {
+			t.Errorf("serveListener(%q) err = %v, want loopback error", addr, err)
+		}
+	}
+}
+
+func TestServeListenerRefusesLiveSocket(t *testing.T) {
+	// Unix socket paths are length-limited, so keep this one short.
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "s.sock")
+
+	ln, where, err := serveListener(socket, "")
+	if err != nil {
+		t.Fatalf("first listen: %v", err)
+	}
+	defer ln.Close()
+	if where != socket {
+		t.Fatalf("where = %q, want %q", where, socket)
+	}
+
+	if _, _, err := serveListener(socket, ""); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Fatalf("second listen err = %v, want already listening", err)
+	}
+
+	ln.Close()
+	// A stale socket file left behind is replaced.
+	if ln2, _, err := serveListener(socket, ""); err != nil {
+		t.Fatalf("listen over stale socket: %v", err)
+	} else {
+		ln2.Close()
+	}
+}
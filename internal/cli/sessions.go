@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
+	"github.com/urfave/cli/v3"
+)
+
+func sessionsCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "sessions",
+		Usage: "Browse agent sessions that have ended",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Filter by repo name",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Show sessions that ended after duration (e.g. 7d, 24h, 30m)",
+			},
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Max sessions to show",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Commands: []*cli.Command{
+			sessionsShowCmd(),
+			sessionsPruneCmd(),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.sessions")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			opts := agent.ArchiveReadOpts{Repo: cmd.String("repo")}
+			if since := cmd.String("since"); since != "" {
+				d, err := parseDuration(since)
+				if err != nil {
+					return err
+				}
+				opts.Since = time.Now().Add(-d)
+			}
+			sessions, err := agent.ReadArchive(opts)
+			if err != nil {
+				return fmt.Errorf("read session archive: %w", err)
+			}
+			if limit := int(cmd.Int("limit")); limit > 0 && len(sessions) > limit {
+				sessions = sessions[len(sessions)-limit:]
+			}
+
+			if cmd.Bool("json") {
+				if sessions == nil {
+					sessions = []agent.ArchivedSession{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(sessions)
+			}
+			if len(sessions) == 0 {
+				u.Info("No ended agent sessions found.")
+				return nil
+			}
+			for _, line := range formatSessionLines(u, sessions) {
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+}
+
+func sessionsShowCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "show",
+		Usage: "Show an ended session's files and timeline",
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "session",
+				UsageText: "<session>",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Filter by repo name",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.sessions.show")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			id := cmd.StringArg("session")
+			if id == "" {
+				return errors.Userf("session is required\n\nUsage: ww sessions show <session>\n\nRun 'ww sessions' to see ended sessions.")
+			}
+			sessions, err := agent.ReadArchive(agent.ArchiveReadOpts{Repo: cmd.String("repo")})
+			if err != nil {
+				return fmt.Errorf("read session archive: %w", err)
+			}
+			a, err := findArchivedSession(sessions, id)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(a)
+			}
+			fmt.Print(formatArchivedSession(u, a))
+			return nil
+		},
+	}
+}
+
+func sessionsPruneCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: "Delete archived sessions older than a given age",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "older-than",
+				Usage: "Delete sessions that ended before this duration ago (e.g. 30d)",
+				Value: "30d",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show how many sessions would be deleted",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.sessions.prune")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			d, err := parseDuration(cmd.String("older-than"))
+			if err != nil {
+				return errors.User(err)
+			}
+			return pruneSessions(u, time.Now().Add(-d), cmd.Bool("dry-run"))
+		},
+	}
+}
+
+func pruneSessions(u *ui.UI, cutoff time.Time, dryRun bool) error {
+	n, err := agent.PruneArchive(cutoff, dryRun)
+	if err != nil {
+		return fmt.Errorf("prune session archive: %w", err)
+	}
+	switch {
+	case n == 0:
+		u.Info("No archived sessions to prune.")
+	case dryRun:
+		u.Info(fmt.Sprintf("Would delete %d archived sessions", n))
+	default:
+		u.Success(fmt.Sprintf("Deleted %d archived sessions", n))
+	}
+	return nil
+}
+
+// findArchivedSession matches id against session IDs by prefix, preferring
+// the most recent session when a prefix matches several.
+func findArchivedSession(sessions []agent.ArchivedSession, id string) (agent.ArchivedSession, error) {
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if strings.HasPrefix(sessions[i].SessionID, id) {
+			return sessions[i], nil
+		}
+	}
+	return agent.ArchivedSession{}, errors.Userf("no ended session matching %q\n\nRun 'ww sessions' to see ended sessions.", id)
+}
+
+// formatSessionLines renders sessions as a table, most recent first.
+func formatSessionLines(u *ui.UI, sessions []agent.ArchivedSession) []string {
+	type row struct {
+		ended, worktree, session, model, ran, tools, files, status string
+	}
+	rows := []row{{ended: "ENDED", worktree: "WORKTREE", session: "SESSION", model: "MODEL", ran: "RAN", tools: "TOOLS", files: "FILES", status: "STATUS"}}
+	for i := len(sessions) - 1; i >= 0; i-- {
+		a := sessions[i]
+		rows = append(rows, row{
+			ended:    agent.TimeSince(a.EndTime),
+			worktree: a.Repo + "/" + a.Worktree,
+			session:  fmt.Sprintf("[%s] %s", a.Harness, agent.ShortSessionID(a.SessionID)),
+			model:    nonEmptyOr(a.Model, "-"),
+			ran:      formatSessionDuration(a.Duration()),
+			tools:    fmt.Sprintf("%d", a.ToolCount),
+			files:    fmt.Sprintf("%d", len(a.FilesTouched)),
+			status:   string(a.Status),
+		})
+	}
+
+	endedW, wtW, sessW, modelW, ranW, toolsW, filesW := 0, 0, 0, 0, 0, 0, 0
+	for _, r := range rows {
+		endedW = max(endedW, termfmt.VisibleWidth(r.ended))
+		wtW = max(wtW, termfmt.VisibleWidth(r.worktree))
+		sessW = max(sessW, termfmt.VisibleWidth(r.session))
+		modelW = max(modelW, termfmt.VisibleWidth(r.model))
+		ranW = max(ranW, termfmt.VisibleWidth(r.ran))
+		toolsW = max(toolsW, termfmt.VisibleWidth(r.tools))
+		filesW = max(filesW, termfmt.VisibleWidth(r.files))
+	}
+	lines := make([]string, 0, len(rows))
+	for i, r := range rows {
+		line := fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %*s  %*s  %*s  %s",
+			endedW, r.ended, wtW, r.worktree, sessW, r.session, modelW, r.model,
+			ranW, r.ran, toolsW, r.tools, filesW, r.files, r.status)
+		if i == 0 {
+			line = u.Bold(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func formatArchivedSession(u *ui.UI, a agent.ArchivedSession) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n", u.Bold(fmt.Sprintf("[%s] %s", a.Harness, a.SessionID)), a.Repo+"/"+a.Worktree)
+	field := func(label, value string) {
+		fmt.Fprintf(&b, "  %s %s\n", u.Dim(fmt.Sprintf("%-8s", label)), value)
+	}
+	field("model", nonEmptyOr(a.Model, "-"))
+	field("started", formatSessionTime(a.StartTime))
+	field("ended", fmt.Sprintf("%s (%s)", formatSessionTime(a.EndTime), agent.TimeSince(a.EndTime)))
+	field("ran", formatSessionDuration(a.Duration()))
+	field("status", string(a.Status))
+	field("tools", fmt.Sprintf("%d", a.ToolCount))
+	if a.CostUSD > 0 {
+		field("cost", usage.FormatCost(a.CostUSD))
+	}
+
+	if len(a.FilesTouched) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", u.Bold(fmt.Sprintf("Files touched (%d)", len(a.FilesTouched))))
+		for _, f := range a.FilesTouched {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+	if len(a.Timeline) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", u.Bold("Timeline"))
+		for _, e := range a.Timeline {
+			fmt.Fprintf(&b, "  %s  %s %s\n", u.Dim(e.Time.Local().Format("Jan 02 15:04:05")), agent.StatusIcon(e.Status), e.Status)
+		}
+	}
+	return b.String()
+}
+
+func formatSessionTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("Jan 02 15:04")
+}
+
+func formatSessionDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func nonEmptyOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+)
+
+func TestSessionsListsShowsAndPrunesArchive(t *testing.T) {
+	setupTestEnv(t)
+
+	endSession := func(repo, wt, id string) {
+		t.Helper()
+		writeActiveSessionFile(t, repo, wt, id, agent.StatusDone)
+		dir := agent.SessionDir(repo, wt, "claude")
+		if err := os.WriteFile(filepath.Join(dir, id+".files"), []byte("/src/"+wt+"/main.go\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		ss := agent.ReadAllSessions(repo, wt)
+		if len(ss) != 1 {
+			t.Fatalf("sessions in %s/%s = %d, want 1", repo, wt, len(ss))
+		}
+		if err := agent.RemoveSessionFileForSession(repo, wt, *ss[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	endSession("api", "auth", "sess-auth-1")
+	endSession("web", "nav", "sess-nav-1")
+
+	out, err := captureStdout(t, func() error { return runApp("sessions") })
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	for _, want := range []string{"WORKTREE", "api/auth", "web/nav", "[claude] sess-aut", "DONE"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("sessions output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = captureStdout(t, func() error { return runApp("sessions", "--repo", "web", "--json") })
+	if err != nil {
+		t.Fatalf("sessions --json: %v", err)
+	}
+	var archived []agent.ArchivedSession
+	if err := json.Unmarshal([]byte(out), &archived); err != nil {
+		t.Fatalf("decode sessions --json: %v\n%s", err, out)
+	}
+	if len(archived) != 1 || archived[0].SessionID != "sess-nav-1" {
+		t.Fatalf("sessions --repo web = %+v", archived)
+	}
+
+	out, err = captureStdout(t, func() error { return runApp("sessions", "show", "sess-auth") })
+	if err != nil {
+		t.Fatalf("sessions show: %v", err)
+	}
+	if !strings.Contains(out, "sess-auth-1") || !strings.Contains(out, "/src/auth/main.go") {
+		t.Errorf("sessions show output:\n%s", out)
+	}
+	if err := runApp("sessions", "show", "nope"); err == nil || !strings.Contains(err.Error(), "no ended session") {
+		t.Errorf("sessions show unknown error = %v", err)
+	}
+
+	if err := runApp("sessions", "prune", "--older-than", "1d"); err != nil {
+		t.Fatalf("sessions prune 1d: %v", err)
+	}
+	if archived, _ := agent.ReadArchive(agent.ArchiveReadOpts{}); len(archived) != 2 {
+		t.Fatalf("prune --older-than 1d removed recent sessions, %d left", len(archived))
+	}
+	if err := runApp("sessions", "prune", "--older-than", "0d"); err != nil {
+		t.Fatalf("sessions prune 0d: %v", err)
+	}
+	if archived, _ := agent.ReadArchive(agent.ArchiveReadOpts{}); len(archived) != 0 {
+		t.Errorf("prune --older-than 0d left %d sessions", len(archived))
+	}
+}
@@ -52,11 +52,11 @@ func agentCmd() *cli.Command {
 		Commands: []*cli.Command{
 			{
 				Name:  "setup",
-				Usage: "Install agent hooks for claude, codex, cursor, or all",
+				Usage: "Install agent hooks for claude, codex, cursor, a custom harness, or all",
 				Arguments: []cli.Argument{
 					&cli.StringArg{
 						Name:      "harness",
-						UsageText: "[claude|codex|cursor|all]",
+						UsageText: "[claude|codex|cursor|<custom>|all]",
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -115,6 +115,14 @@ func runAgentSetup(cmd *cli.Command, ids []string) error {
 		if hint := h.DocsHint(); hint != "" {
 			u.Info(fmt.Sprintf("  note:   %s", hint))
 		}
+		for rawID, hc := range config.Load("").Agent.Harnesses {
+			if harness.NormalizeID(rawID) != id {
+				continue
+			}
+			for _, w := range hc.HookEventWarnings(rawID) {
+				u.Warn(w)
+			}
+		}
 	}
 
 	u.Info("")
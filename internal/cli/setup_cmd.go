@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/setup"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+)
+
+func setupHooksCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "setup",
+		Usage: "Rerun or inspect a worktree's setup hooks",
+		Commands: []*cli.Command{
+			setupRerunCmd(),
+			setupLogCmd(),
+		},
+	}
+}
+
+func setupRerunCmd() *cli.Command {
+	return &cli.Command{
+		Name:          "rerun",
+		Usage:         "Run the setup hooks again in a worktree",
+		ShellComplete: completeWorktreesWithFlag,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "target",
+				UsageText: "[worktree]",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Run every step, even those whose inputs are unchanged",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.setup.rerun")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			rwt, err := resolveWorktreeTarget(g, cmd.String("repo"), cmd.StringArg("target"))
+			if err != nil {
+				return err
+			}
+			wtPath := rwt.Worktree.Path
+			cfg := withWorktreeProfile(config.Load(rwt.Repo.BareDir), wtPath, u)
+			if len(cfg.Setup) == 0 {
+				u.Info("No setup hooks configured.")
+				return nil
+			}
+
+			u.Info(fmt.Sprintf("Running setup hooks in %s...", u.Bold(rwt.Worktree.DisplayName())))
+			if err := runSetup(cfg, u, wtPath, worktreeEnv(cfg, wtPath, u), os.Stdout, cmd.Bool("force")); err != nil {
+				return err
+			}
+			u.Success("Setup complete")
+			return nil
+		},
+	}
+}
+
+func setupLogCmd() *cli.Command {
+	return &cli.Command{
+		Name:          "log",
+		Usage:         "Show the output of a worktree's last setup run",
+		ShellComplete: completeWorktreesWithFlag,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "target",
+				UsageText: "[worktree]",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.BoolFlag{
+				Name:  "path",
+				Usage: "Print the log file path instead of its contents",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.setup.log")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			rwt, err := resolveWorktreeTarget(g, cmd.String("repo"), cmd.StringArg("target"))
+			if err != nil {
+				return err
+			}
+			adminDir, err := readGitAdminDir(rwt.Worktree.Path)
+			if err != nil {
+				return err
+			}
+			logPath := filepath.Join(adminDir, setup.LogFile)
+			if cmd.Bool("path") {
+				fmt.Println(logPath)
+				return nil
+			}
+			data, err := os.ReadFile(logPath)
+			if os.IsNotExist(err) {
+				u.Info(fmt.Sprintf("No setup log for %s yet.", rwt.Worktree.DisplayName()))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			state := setup.LoadState(adminDir)
+			if state.Failed != "" {
+				u.Warn(fmt.Sprintf("Last setup %s failed at: %s", agent.TimeSince(state.LastRun), u.Bold(state.Failed)))
+			} else if !state.LastRun.IsZero() {
+				u.Info(u.Dim(fmt.Sprintf("Last setup succeeded %s", agent.TimeSince(state.LastRun))))
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		},
+	}
+}
+
+// runSetup runs cfg's setup steps in wtPath, logging to the worktree's git
+// admin dir so ww setup log can show a failure later.
+func runSetup(cfg *config.Config, u *ui.UI, wtPath string, env []string, stdout *os.File, force bool) error {
+	adminDir, err := readGitAdminDir(wtPath)
+	if err != nil {
+		adminDir = ""
+	}
+	r := &setup.Runner{
+		Dir:      wtPath,
+		StateDir: adminDir,
+		Env:      env,
+		Stdout:   stdout,
+		Stderr:   os.Stderr,
+		Force:    force,
+		Progress: u.Info,
+	}
+	if err := r.Run(cfg.Setup); err != nil {
+		if adminDir != "" {
+			u.Warn("Setup failed. Inspect it with 'ww setup log' and retry with 'ww setup rerun' from the worktree.")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+)
+
+func TestSetupLogAndRerunAfterFailure(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "setuprepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "setuprepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "setuprepo")
+	cfgPath := filepath.Join(bareDir, "willow.json")
+
+	cfg := `{"setup": [{"run": "echo deps >> runs", "inputs": ["lock"]}, "test -f ready || { echo missing-ready; exit 4; }"]}`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err == nil {
+		t.Fatal("new should fail when a setup hook fails")
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+
+	out, err := captureStdout(t, func() error { return runApp("setup", "log", "feature") })
+	if err != nil {
+		t.Fatalf("setup log: %v", err)
+	}
+	if !strings.Contains(out, "missing-ready") || !strings.Contains(out, "failed after") {
+		t.Errorf("setup log missing failure output:\n%s", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(featureDir, "ready"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chdir(featureDir)
+	if err := runApp("setup", "rerun"); err != nil {
+		t.Fatalf("setup rerun: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(featureDir, "runs")); string(data) != "deps\n" {
+		t.Errorf("runs = %q, want the step with unchanged inputs skipped on rerun", data)
+	}
+	if err := runApp("setup", "rerun", "--force"); err != nil {
+		t.Fatalf("setup rerun --force: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(featureDir, "runs")); string(data) != "deps\ndeps\n" {
+		t.Errorf("runs = %q, want --force to run every step", data)
+	}
+}
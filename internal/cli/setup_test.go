@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
 	"github.com/iamrajjoshi/willow/internal/config"
 )
 
@@ -119,6 +120,26 @@ func TestAgentSetupAllIncludesCursor(t *testing.T) {
 	}
 }
 
+func TestAgentSetupWarnsAboutUnknownCustomHookStatus(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeGlobalConfigFile(t, `{"telemetry":false,"agent":{"harnesses":{"Aider":{"command":"aider","hooks":{"events":{"start":"busy","stop":"FINISHED"}}}}}}`)
+	t.Cleanup(func() { harness.RegisterCustom(nil) })
+
+	out, err := captureStdout(t, func() error {
+		return runApp("agent", "setup", "aider")
+	})
+	if err != nil {
+		t.Fatalf("agent setup aider failed: %v", err)
+	}
+	if !strings.Contains(out, `hooks.events.stop "FINISHED" is not one of`) {
+		t.Fatalf("agent setup should warn about the stop mapping:\n%s", out)
+	}
+	if strings.Contains(out, "hooks.events.start") {
+		t.Fatalf("lowercase busy is a valid mapping:\n%s", out)
+	}
+}
+
 func TestSetupTargetsAcceptCursor(t *testing.T) {
 	ids, err := setupTargets("cursor")
 	if err != nil {
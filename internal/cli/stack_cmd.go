@@ -23,10 +23,26 @@ func stackCmd() *cli.Command {
 		Usage: "Manage stacked branches",
 		Commands: []*cli.Command{
 			stackStatusCmd(),
+			stackRestackCmd(),
 		},
 	}
 }
 
+func stackRestackCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "restack",
+		Usage: "Rebase a whole stack onto updated parents, resumable after conflicts",
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "branch",
+				UsageText: "[branch]",
+			},
+		},
+		Flags:  restackFlags(),
+		Action: runRestack,
+	}
+}
+
 func stackStatusCmd() *cli.Command {
 	return &cli.Command{
 		Name:    "status",
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/stats"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/urfave/cli/v3"
+)
+
+func statsCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Report agent busy time, wait time, and worktree lifetimes",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only count activity after duration (e.g. 30d, 7d, 24h)",
+				Value: "30d",
+			},
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Filter by repo name",
+			},
+			&cli.StringFlag{
+				Name:  "by",
+				Usage: "Group by repo or harness",
+				Value: stats.GroupRepo,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+			&cli.BoolFlag{
+				Name:  "csv",
+				Usage: "Output as CSV",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.stats")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			if cmd.Bool("json") && cmd.Bool("csv") {
+				return errors.Userf("--json and --csv cannot be used together")
+			}
+			d, err := parseDuration(cmd.String("since"))
+			if err != nil {
+				return errors.User(err)
+			}
+			now := time.Now()
+			in, err := stats.Load(stats.LoadOpts{Repo: cmd.String("repo"), Since: now.Add(-d), Now: now})
+			if err != nil {
+				return err
+			}
+			by := cmd.String("by")
+			summaries, err := stats.Summarize(in, by)
+			if err != nil {
+				return errors.Userf("%v", err)
+			}
+
+			switch {
+			case cmd.Bool("json"):
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(summaries)
+			case cmd.Bool("csv"):
+				return writeStatsCSV(os.Stdout, summaries)
+			}
+
+			if len(summaries) == 0 {
+				u.Info("No agent activity recorded.")
+				return nil
+			}
+			for _, line := range formatStatsLines(u, by, summaries, stats.Total(in)) {
+				u.Info(line)
+			}
+			return nil
+		},
+	}
+}
+
+func writeStatsCSV(w io.Writer, summaries []stats.Summary) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"key", "sessions", "sessions_per_day", "busy_seconds", "wait_seconds",
+		"responses", "mean_response_seconds", "worktrees", "mean_lifetime_seconds",
+	})
+	num := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	for _, s := range summaries {
+		cw.Write([]string{
+			s.Key,
+			strconv.Itoa(s.Sessions),
+			num(s.SessionsPerDay),
+			num(s.BusySeconds),
+			num(s.WaitSeconds),
+			strconv.Itoa(s.Responses),
+			num(s.MeanResponseSeconds),
+			strconv.Itoa(s.Worktrees),
+			num(s.MeanLifetimeSeconds),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatStatsLines(u *ui.UI, by string, summaries []stats.Summary, total stats.Summary) []string {
+	type row struct {
+		key, sessions, perDay, busy, wait, response, worktrees, lifetime string
+	}
+	toRow := func(s stats.Summary) row {
+		return row{
+			key:       nonEmptyOr(s.Key, "-"),
+			sessions:  fmt.Sprintf("%d", s.Sessions),
+			perDay:    fmt.Sprintf("%.1f", s.SessionsPerDay),
+			busy:      formatStatsDuration(s.BusySeconds),
+			wait:      formatStatsDuration(s.WaitSeconds),
+			response:  formatStatsDuration(s.MeanResponseSeconds),
+			worktrees: fmt.Sprintf("%d", s.Worktrees),
+			lifetime:  formatStatsDuration(s.MeanLifetimeSeconds),
+		}
+	}
+	rows := []row{{
+		key: strings.ToUpper(by), sessions: "SESSIONS", perDay: "/DAY", busy: "BUSY", wait: "WAITING",
+		response: "RESPONSE", worktrees: "WORKTREES", lifetime: "LIFETIME",
+	}}
+	for _, s := range summaries {
+		rows = append(rows, toRow(s))
+	}
+	rows = append(rows, toRow(total))
+
+	widths := make([]int, 8)
+	for _, r := range rows {
+		for i, cell := range []string{r.key, r.sessions, r.perDay, r.busy, r.wait, r.response, r.worktrees, r.lifetime} {
+			widths[i] = max(widths[i], termfmt.VisibleWidth(cell))
+		}
+	}
+	format := func(r row) string {
+		return fmt.Sprintf("  %-*s  %*s  %*s  %*s  %*s  %*s  %*s  %*s",
+			widths[0], r.key, widths[1], r.sessions, widths[2], r.perDay, widths[3], r.busy,
+			widths[4], r.wait, widths[5], r.response, widths[6], r.worktrees, widths[7], r.lifetime)
+	}
+	lines := make([]string, 0, len(rows)+1)
+	lines = append(lines, u.Bold(format(rows[0])))
+	for _, r := range rows[1 : len(rows)-1] {
+		lines = append(lines, format(r))
+	}
+	lines = append(lines, "", u.Bold(format(rows[len(rows)-1])))
+	return lines
+}
+
+// formatStatsDuration extends formatSessionDuration with days, since
+// totals over a month of activity run well past 24h.
+func formatStatsDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	if d < 24*time.Hour {
+		return formatSessionDuration(d)
+	}
+	return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+}
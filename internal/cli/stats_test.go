@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/stats"
+)
+
+func TestStatsReportsArchivedSessions(t *testing.T) {
+	setupTestEnv(t)
+
+	writeActiveSessionFile(t, "api", "auth", "sess-1", agent.StatusDone)
+	start := time.Now().Add(-2 * time.Hour)
+	var timeline strings.Builder
+	for i, s := range []agent.Status{agent.StatusBusy, agent.StatusDone, agent.StatusBusy, agent.StatusDone} {
+		fmt.Fprintf(&timeline, `{"s":%q,"t":%q}`+"\n", s, start.Add(time.Duration(i)*20*time.Minute).Format(time.RFC3339Nano))
+	}
+	if err := os.WriteFile(agent.TimelinePathForHarness("api", "auth", "claude", "sess-1"), []byte(timeline.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ss := agent.ReadAllSessions("api", "auth")
+	if len(ss) != 1 {
+		t.Fatalf("sessions = %d, want 1", len(ss))
+	}
+	if err := agent.RemoveSessionFileForSession("api", "auth", *ss[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error { return runApp("stats", "--by", "harness", "--json") })
+	if err != nil {
+		t.Fatalf("stats --json: %v", err)
+	}
+	var summaries []stats.Summary
+	if err := json.Unmarshal([]byte(out), &summaries); err != nil {
+		t.Fatalf("decode stats --json: %v\n%s", err, out)
+	}
+	if len(summaries) != 1 || summaries[0].Key != "claude" || summaries[0].Sessions != 1 || summaries[0].Responses != 1 {
+		t.Fatalf("stats --json = %+v", summaries)
+	}
+	if got := summaries[0].BusySeconds; got != 40*60 {
+		t.Errorf("busy seconds = %v, want 2400", got)
+	}
+
+	out, err = captureStdout(t, func() error { return runApp("stats", "--csv") })
+	if err != nil {
+		t.Fatalf("stats --csv: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse stats --csv: %v\n%s", err, out)
+	}
+	if len(records) != 2 || records[0][0] != "key" || records[1][0] != "api" || records[1][3] != "2400" {
+		t.Errorf("stats --csv = %q", records)
+	}
+
+	if err := runApp("stats", "--by", "model"); err == nil {
+		t.Error("stats --by model should fail")
+	}
+}
@@ -13,19 +13,23 @@ import (
 	"github.com/iamrajjoshi/willow/internal/termfmt"
 	"github.com/iamrajjoshi/willow/internal/trace"
 	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
 	"github.com/iamrajjoshi/willow/internal/worktree"
 	"github.com/urfave/cli/v3"
 )
 
 type sessionEntry struct {
-	Repo      string `json:"repo,omitempty"`
-	Branch    string `json:"branch"`
-	Harness   string `json:"harness,omitempty"`
-	SessionID string `json:"session_id,omitempty"`
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp,omitempty"`
-	Unread    bool   `json:"unread,omitempty"`
-	Path      string `json:"path"`
+	Repo      string  `json:"repo,omitempty"`
+	Branch    string  `json:"branch"`
+	Harness   string  `json:"harness,omitempty"`
+	SessionID string  `json:"session_id,omitempty"`
+	Status    string  `json:"status"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	Unread    bool    `json:"unread,omitempty"`
+	Tokens    int64   `json:"tokens,omitempty"`
+	CostUSD   float64 `json:"cost_usd,omitempty"`
+	Ports     []int   `json:"ports,omitempty"`
+	Path      string  `json:"path"`
 }
 
 type repoStatus struct {
@@ -38,8 +42,13 @@ type repoStatus struct {
 
 func collectRepoStatus(repoName string, worktrees []worktree.Worktree) repoStatus {
 	rs := repoStatus{Name: repoName, WorktreeCount: len(worktrees)}
+	allocated := worktreePorts()
 	for _, wt := range worktrees {
 		wtDir := filepath.Base(wt.Path)
+		var wtPorts []int
+		if a, ok := allocated[wt.Path]; ok {
+			wtPorts = a.Ports()
+		}
 		sessions := agent.ReadAllSessions(repoName, wtDir)
 		if agent.CountUnreadIn(repoName, wtDir, sessions) > 0 {
 			rs.UnreadCount++
@@ -54,6 +63,9 @@ func collectRepoStatus(repoName string, worktrees []worktree.Worktree) repoStatu
 					Harness:   ss.Harness,
 					SessionID: ss.SessionID,
 					Status:    string(effective),
+					Tokens:    ss.Usage.Total().Total(),
+					CostUSD:   ss.CostUSD,
+					Ports:     wtPorts,
 					Path:      wt.Path,
 				}
 				if !ss.Timestamp.IsZero() {
@@ -74,6 +86,7 @@ func collectRepoStatus(repoName string, worktrees []worktree.Worktree) repoStatu
 				Repo:   repoName,
 				Branch: wt.DisplayName(),
 				Status: string(ws.Status),
+				Ports:  wtPorts,
 				Path:   wt.Path,
 			}
 			if !ws.Timestamp.IsZero() {
@@ -116,6 +129,27 @@ func collectRepoStatuses(repos []repoInfo, verbose bool) []repoStatus {
 	return statuses
 }
 
+// formatSessionUsage renders a session's token count and estimated cost,
+// or "" when the harness reported no usage.
+func formatSessionUsage(tokens int64, costUSD float64) string {
+	if tokens == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s tok %s", usage.FormatTokens(tokens), usage.FormatCost(costUSD))
+}
+
+// formatPortRange renders a worktree's port block as ":4000-4002", or ""
+// when it has none.
+func formatPortRange(ports []int) string {
+	switch len(ports) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(":%d", ports[0])
+	}
+	return fmt.Sprintf(":%d-%d", ports[0], ports[len(ports)-1])
+}
+
 func statusBranchLabel(branch, harnessID, sessionID string) string {
 	if sessionID == "" {
 		return branch
@@ -130,11 +164,15 @@ func statusBranchLabel(branch, harnessID, sessionID string) string {
 func formatStatusEntryLines(u *ui.UI, entries []sessionEntry, width int) []string {
 	branchW := 0
 	labelW := 0
+	portsW := 0
+	usageW := 0
 	timeW := 0
 	type row struct {
 		icon   string
 		branch string
 		label  string
+		ports  string
+		usage  string
 		ts     string
 	}
 	rows := make([]row, 0, len(entries))
@@ -147,16 +185,26 @@ func formatStatusEntryLines(u *ui.UI, entries []sessionEntry, width int) []strin
 			icon:   agent.StatusIcon(agent.Status(e.Status)),
 			branch: statusBranchLabel(e.Branch, e.Harness, e.SessionID),
 			label:  label,
+			ports:  formatPortRange(e.Ports),
+			usage:  formatSessionUsage(e.Tokens, e.CostUSD),
 			ts:     e.Timestamp,
 		}
 		rows = append(rows, r)
 		branchW = max(branchW, termfmt.VisibleWidth(r.branch))
 		labelW = max(labelW, termfmt.VisibleWidth(r.label))
+		portsW = max(portsW, termfmt.VisibleWidth(r.ports))
+		usageW = max(usageW, termfmt.VisibleWidth(r.usage))
 		timeW = max(timeW, termfmt.VisibleWidth(r.ts))
 	}
 
 	termWidth := termfmt.Width(width)
 	fixed := 2 + 2 + 1 + 2 + labelW
+	if portsW > 0 {
+		fixed += 2 + portsW
+	}
+	if usageW > 0 {
+		fixed += 2 + usageW
+	}
 	if timeW > 0 {
 		fixed += 2 + timeW
 	}
@@ -169,6 +217,12 @@ func formatStatusEntryLines(u *ui.UI, entries []sessionEntry, width int) []strin
 		icon := termfmt.PadRight(r.icon, 2)
 		branch := termfmt.FitRight(r.branch, branchW)
 		label := termfmt.FitRight(r.label, labelW)
+		if portsW > 0 {
+			label += "  " + u.Dim(termfmt.FitRight(r.ports, portsW))
+		}
+		if usageW > 0 {
+			label += "  " + u.Dim(termfmt.FitRight(r.usage, usageW))
+		}
 		if timeW > 0 {
 			lines = append(lines, fmt.Sprintf("  %s %s  %s  %s",
 				icon, branch, label, u.Dim(termfmt.FitRight(r.ts, timeW))))
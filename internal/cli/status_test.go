@@ -226,3 +226,15 @@ func TestCollectRepoStatus_EmptyWorktrees(t *testing.T) {
 		t.Errorf("Entries = %d, want 0", len(rs.Entries))
 	}
 }
+
+func TestFormatStatusEntryLinesShowsUsage(t *testing.T) {
+	u := &ui.UI{}
+	lines := formatStatusEntryLines(u, []sessionEntry{
+		{Branch: "auth", Harness: "claude", SessionID: "s1", Status: string(agent.StatusDone), Tokens: 45_600, CostUSD: 0.42},
+		{Branch: "main", Status: string(agent.StatusIdle)},
+	}, 120)
+	plain := termfmt.StripANSI(strings.Join(lines, "\n"))
+	if !strings.Contains(plain, "45.6k tok $0.42") {
+		t.Fatalf("status lines should show session usage:\n%s", plain)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/errors"
@@ -28,200 +29,382 @@ func syncCmd() *cli.Command {
 				UsageText: "[branch]",
 			},
 		},
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "repo",
-				Aliases: []string{"r"},
-				Usage:   "Target a willow-managed repo by name",
-			},
-			&cli.BoolFlag{
-				Name:  "no-fetch",
-				Usage: "Skip fetching from remote",
-			},
-			&cli.BoolFlag{
-				Name:  "abort",
-				Usage: "Abort any in-progress rebases across stacked worktrees",
-			},
+		Flags:  restackFlags(),
+		Action: runRestack,
+	}
+}
+
+func restackFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "repo",
+			Aliases: []string{"r"},
+			Usage:   "Target a willow-managed repo by name",
 		},
-		Action: func(ctx context.Context, cmd *cli.Command) error {
-			flags := parseFlags(cmd)
-			tr := trace.FromContext(ctx)
-			defer tr.Total()
-			g := flags.NewGit()
-			u := flags.NewUI()
-
-			done := tr.StartCtx(ctx, "resolve repo")
-			var bareDir string
-			var err error
-			if repoFlag := cmd.String("repo"); repoFlag != "" {
-				bareDir, err = config.ResolveRepo(repoFlag)
-				if err != nil {
-					return err
-				}
-			} else {
-				bareDir, err = requireWillowRepo(g)
-				if err != nil {
-					return err
-				}
-			}
-			done()
+		&cli.BoolFlag{
+			Name:  "no-fetch",
+			Usage: "Skip fetching from remote",
+		},
+		&cli.BoolFlag{
+			Name:  "continue",
+			Usage: "Resume an interrupted restack after resolving conflicts",
+		},
+		&cli.BoolFlag{
+			Name:  "abort",
+			Usage: "Abort any in-progress rebases across stacked worktrees",
+		},
+	}
+}
 
-			repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose}
+// runRestack backs both 'ww sync' and 'ww stack restack'. The rebase plan is
+// persisted to restack.json before any branch moves so that --continue can
+// resume from the conflicted branch with the original parent tips.
+func runRestack(ctx context.Context, cmd *cli.Command) error {
+	flags := parseFlags(cmd)
+	tr := trace.FromContext(ctx)
+	defer tr.Total()
+	g := flags.NewGit()
+	u := flags.NewUI()
 
-			done = tr.StartCtx(ctx, "load stack")
-			st := stack.Load(bareDir)
-			done()
-			if st.IsEmpty() {
-				u.Info("No stacked branches found. Use 'ww new <branch> -b <parent>' to create a stack.")
-				return nil
-			}
+	done := tr.StartCtx(ctx, "resolve repo")
+	var bareDir string
+	var err error
+	if repoFlag := cmd.String("repo"); repoFlag != "" {
+		bareDir, err = config.ResolveRepo(repoFlag)
+		if err != nil {
+			return err
+		}
+	} else {
+		bareDir, err = requireWillowRepo(g)
+		if err != nil {
+			return err
+		}
+	}
+	done()
 
-			done = tr.StartCtx(ctx, "list worktrees")
-			wts, err := worktree.List(repoGit)
-			if err != nil {
-				return fmt.Errorf("failed to list worktrees: %w", err)
-			}
-			wtPaths := make(map[string]string) // branch → worktree path
-			for _, wt := range wts {
-				if !wt.IsBare && !wt.Detached {
-					wtPaths[wt.Branch] = wt.Path
-				}
+	cfg := config.Load(bareDir)
+	repoGit := &git.Git{Dir: bareDir, Verbose: g.Verbose, Remote: cfg.Upstream()}
+
+	done = tr.StartCtx(ctx, "load stack")
+	st := stack.Load(bareDir)
+	plan, err := stack.LoadRestackPlan(bareDir)
+	if err != nil {
+		return err
+	}
+	done()
+	if st.IsEmpty() && plan == nil {
+		u.Info("No stacked branches found. Use 'ww new <branch> -b <parent>' to create a stack.")
+		return nil
+	}
+
+	done = tr.StartCtx(ctx, "list worktrees")
+	wts, err := worktree.List(repoGit)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	wtPaths := make(map[string]string) // branch → worktree path
+	for _, wt := range wts {
+		if !wt.IsBare && !wt.Detached {
+			wtPaths[wt.Branch] = wt.Path
+		}
+	}
+	if cmd.Bool("abort") || cmd.Bool("continue") {
+		// Mid-rebase worktrees have a detached HEAD, so git no longer
+		// reports their branch; fall back to the conventional directory.
+		repoName := repoNameFromDir(bareDir)
+		for _, branch := range st.TopoSort() {
+			if _, ok := wtPaths[branch]; ok {
+				continue
 			}
-			if cmd.Bool("abort") {
-				repoName := repoNameFromDir(bareDir)
-				for _, branch := range st.TopoSort() {
-					if _, ok := wtPaths[branch]; ok {
-						continue
-					}
-					dirName := strings.ReplaceAll(branch, "/", "-")
-					candidate := filepath.Join(config.WorktreesDir(), repoName, dirName)
-					if info, err := os.Stat(candidate); err == nil && info.IsDir() {
-						wtPaths[branch] = candidate
-					}
-				}
+			dirName := strings.ReplaceAll(branch, "/", "-")
+			candidate := filepath.Join(config.WorktreesDir(), repoName, dirName)
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				wtPaths[branch] = candidate
 			}
-			done()
+		}
+	}
+	done()
 
-			if cmd.Bool("abort") {
-				return syncAbort(st, wtPaths, g.Verbose, u)
-			}
+	if cmd.Bool("abort") {
+		if err := syncAbort(st, wtPaths, g.Verbose, u); err != nil {
+			return err
+		}
+		return stack.ClearRestackPlan(bareDir)
+	}
 
-			var branches []string
-			if targetBranch := cmd.StringArg("branch"); targetBranch != "" {
-				if !st.IsTracked(targetBranch) {
-					return errors.Userf("branch %q is not in the stack", targetBranch)
-				}
-				branches = st.SubtreeSort(targetBranch)
-			} else {
-				branches = st.TopoSort()
-			}
+	if cmd.Bool("continue") {
+		if plan == nil {
+			return errors.Userf("no restack in progress")
+		}
+		u.Info(fmt.Sprintf("\nResuming restack of %d stacked worktree(s):\n", len(plan.Steps)))
+		if err := executeRestack(bareDir, st, plan, wtPaths, g.Verbose, u); err != nil {
+			return err
+		}
+		refreshStackTables(bareDir, u)
+		return nil
+	}
 
-			if len(branches) == 0 {
-				u.Info("Nothing to sync.")
-				return nil
-			}
+	if plan != nil {
+		return errors.Userf("a restack is already in progress\n\nResolve conflicts, then run 'ww sync --continue' (or 'ww sync --abort').")
+	}
 
-			if !cmd.Bool("no-fetch") {
-				done = tr.StartCtx(ctx, "git fetch")
-				if err := u.Spin("Fetching origin", func() error {
-					_, err := repoGit.Run("fetch", "--no-tags", "origin")
-					return err
-				}); err != nil {
-					u.Warn(fmt.Sprintf("fetch failed: %v (continuing anyway)", err))
-				}
-				done()
+	var branches []string
+	if targetBranch := cmd.StringArg("branch"); targetBranch != "" {
+		if !st.IsTracked(targetBranch) {
+			return errors.Userf("branch %q is not in the stack", targetBranch)
+		}
+		branches = st.SubtreeSort(targetBranch)
+	} else {
+		branches = st.TopoSort()
+	}
+
+	if len(branches) == 0 {
+		u.Info("Nothing to sync.")
+		return nil
+	}
+
+	if !cmd.Bool("no-fetch") {
+		done = tr.StartCtx(ctx, "git fetch")
+		if err := u.Spin("Fetching "+strings.Join(configRemotes(cfg), ", "), func() error {
+			_, err := repoGit.Run(fetchRemotesArgs(cfg, "--no-tags")...)
+			return err
+		}); err != nil {
+			u.Warn(fmt.Sprintf("fetch failed: %v (continuing anyway)", err))
+		}
+		done()
+	}
+
+	scope := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		scope[branch] = true
+	}
+	moved, err := reparentMergedParents(repoGit, bareDir, st, reparentOptions{
+		Scope:          scope,
+		RefreshPRState: !cmd.Bool("no-fetch"),
+	}, u)
+	if err != nil {
+		return err
+	}
+	oldParents := make(map[string]string, len(moved))
+	mergedParents := make(map[string]bool, len(moved))
+	for _, m := range moved {
+		oldParents[m.Branch] = m.From
+		mergedParents[m.From] = true
+	}
+	if len(mergedParents) > 0 {
+		kept := branches[:0]
+		for _, branch := range branches {
+			if !mergedParents[branch] {
+				kept = append(kept, branch)
 			}
+		}
+		branches = kept
+	}
 
-			u.Info(fmt.Sprintf("\nSyncing %d stacked worktree(s):\n", len(branches)))
+	plan = newRestackPlan(repoGit, st, branches, oldParents)
+	if err := plan.Save(bareDir); err != nil {
+		return fmt.Errorf("failed to save restack plan: %w", err)
+	}
 
-			conflicted := make(map[string]bool)
-			synced := 0
-			skipped := 0
+	u.Info(fmt.Sprintf("\nSyncing %d stacked worktree(s):\n", len(branches)))
+	if err := executeRestack(bareDir, st, plan, wtPaths, g.Verbose, u); err != nil {
+		return err
+	}
+	refreshStackTables(bareDir, u)
+	return nil
+}
 
-			for _, branch := range branches {
-				parent := st.Parent(branch)
+// newRestackPlan records, for each branch, the ref to rebase onto and the
+// parent tip the branch currently sits on. Recording tips before anything is
+// rebased lets children use 'rebase --onto <new> <old-tip>' so rewritten or
+// squash-merged parent commits are not replayed. oldParents maps reparented
+// branches to the merged parent they were built on.
+func newRestackPlan(repoGit *git.Git, st *stack.Stack, branches []string, oldParents map[string]string) *stack.RestackPlan {
+	plan := &stack.RestackPlan{Started: time.Now().UTC()}
+	for _, branch := range branches {
+		parent := st.Parent(branch)
+		onto := parent
+		if !st.IsTracked(parent) {
+			onto = repoGit.RemoteRef(parent)
+		}
+		base := onto
+		if old, ok := oldParents[branch]; ok {
+			base = old
+		}
+		plan.Steps = append(plan.Steps, stack.RestackStep{
+			Branch:  branch,
+			Parent:  parent,
+			Onto:    onto,
+			OldBase: restackBase(repoGit, base, branch),
+			State:   stack.StepPending,
+		})
+	}
+	return plan
+}
 
-				if isAncestorConflicted(st, branch, conflicted) {
-					u.Info(fmt.Sprintf("  %s → %s", parent, branch))
-					u.Info(fmt.Sprintf("    %s Skipped (ancestor has conflict)", u.Dim("⊘")))
-					skipped++
-					continue
-				}
+// restackBase returns the commit on ref that branch was built on: ref's tip
+// when branch contains it, otherwise ref's fork point or the merge base.
+func restackBase(repoGit *git.Git, ref, branch string) string {
+	tip, err := repoGit.RevParse(ref)
+	if err != nil {
+		return ""
+	}
+	if repoGit.IsAncestor(tip, branch) {
+		return tip
+	}
+	if fp, err := repoGit.ForkPoint(ref, branch); err == nil {
+		return fp
+	}
+	if mb, err := repoGit.MergeBase(ref, branch); err == nil {
+		return mb
+	}
+	return ""
+}
 
-				wtPath, hasWorktree := wtPaths[branch]
-				if !hasWorktree {
-					u.Info(fmt.Sprintf("  %s → %s", parent, branch))
-					u.Info(fmt.Sprintf("    %s Skipped (no worktree)", u.Dim("⊘")))
-					skipped++
-					continue
-				}
+func executeRestack(bareDir string, st *stack.Stack, plan *stack.RestackPlan, wtPaths map[string]string, verbose bool, u *ui.UI) error {
+	conflicted := make(map[string]bool)
+	synced := 0
+	skipped := 0
 
-				wtGit := &git.Git{Dir: wtPath, Verbose: g.Verbose}
+	save := func() {
+		if err := plan.Save(bareDir); err != nil {
+			u.Warn(fmt.Sprintf("failed to save restack plan: %v", err))
+		}
+	}
 
-				dirty, err := wtGit.IsDirty()
-				if err != nil {
-					u.Info(fmt.Sprintf("  %s → %s", parent, branch))
-					u.Warn(fmt.Sprintf("    ⚠ Skipped (failed to check status: %v)", err))
-					skipped++
-					continue
-				}
-				if dirty {
-					u.Info(fmt.Sprintf("  %s → %s", parent, branch))
-					u.Warn(fmt.Sprintf("    ⚠ Skipped (uncommitted changes)"))
-					skipped++
-					continue
-				}
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		branch, parent := step.Branch, step.Parent
+		if step.State == stack.StepDone || step.State == stack.StepSkipped {
+			continue
+		}
 
-				if wtGit.IsRebaseInProgress() {
-					u.Info(fmt.Sprintf("  %s → %s", parent, branch))
-					u.Warn(fmt.Sprintf("    ⚠ Rebase in progress — resolve manually"))
-					conflicted[branch] = true
-					continue
-				}
+		if isAncestorConflicted(st, branch, conflicted) {
+			u.Info(fmt.Sprintf("  %s → %s", parent, branch))
+			u.Info(fmt.Sprintf("    %s Skipped (ancestor has conflict)", u.Dim("⊘")))
+			skipped++
+			continue
+		}
 
-				rebaseOnto := parent
-				if !st.IsTracked(parent) {
-					rebaseOnto = "origin/" + parent
-				}
+		wtPath, hasWorktree := wtPaths[branch]
+		if !hasWorktree {
+			u.Info(fmt.Sprintf("  %s → %s", parent, branch))
+			u.Info(fmt.Sprintf("    %s Skipped (no worktree)", u.Dim("⊘")))
+			step.State = stack.StepSkipped
+			save()
+			skipped++
+			continue
+		}
 
-				u.Info(fmt.Sprintf("  %s → %s", parent, u.Bold(branch)))
+		wtGit := &git.Git{Dir: wtPath, Verbose: verbose}
 
-				if err := wtGit.Rebase(rebaseOnto); err != nil {
+		if step.State == stack.StepConflict {
+			u.Info(fmt.Sprintf("  %s → %s", parent, u.Bold(branch)))
+			if wtGit.IsRebaseInProgress() {
+				if err := wtGit.RebaseContinue(); err != nil {
 					conflicted[branch] = true
-					u.Warn(fmt.Sprintf("    ✗ Conflict — resolve in %s", wtPath))
-					u.Info(fmt.Sprintf("      cd %s && git rebase --continue", wtPath))
+					u.Warn(fmt.Sprintf("    ✗ Still conflicted — resolve in %s", wtPath))
 					continue
 				}
-
-				ahead, aheadErr := wtGit.CommitsAhead(rebaseOnto)
-				if aheadErr != nil {
-					u.Info(fmt.Sprintf("    %s Rebased onto %s", u.Green("✔"), rebaseOnto))
-				} else {
-					u.Info(fmt.Sprintf("    %s Rebased onto %s (%d commits ahead)", u.Green("✔"), rebaseOnto, ahead))
-				}
-				meta := map[string]string{"parent": parent}
-				if aheadErr == nil {
-					meta["ahead"] = fmt.Sprintf("%d", ahead)
-				}
-				_ = log.Append(log.Event{
-					Action:   "sync",
-					Repo:     repoNameFromDir(bareDir),
-					Branch:   branch,
-					Metadata: meta,
-				})
+				finishRestackStep(bareDir, wtGit, step, u)
+				save()
+				synced++
+				continue
+			}
+			if wtGit.IsAncestor(step.Onto, "HEAD") {
+				// Resolved by hand with 'git rebase --continue'.
+				finishRestackStep(bareDir, wtGit, step, u)
+				save()
 				synced++
+				continue
+			}
+			// The rebase was aborted by hand; start it over below.
+			step.State = stack.StepPending
+		} else {
+			dirty, err := wtGit.IsDirty()
+			if err != nil {
+				u.Info(fmt.Sprintf("  %s → %s", parent, branch))
+				u.Warn(fmt.Sprintf("    ⚠ Skipped (failed to check status: %v)", err))
+				step.State = stack.StepSkipped
+				save()
+				skipped++
+				continue
+			}
+			if dirty {
+				u.Info(fmt.Sprintf("  %s → %s", parent, branch))
+				u.Warn(fmt.Sprintf("    ⚠ Skipped (uncommitted changes)"))
+				step.State = stack.StepSkipped
+				save()
+				skipped++
+				continue
 			}
 
-			fmt.Println()
-			if len(conflicted) > 0 {
-				u.Warn(fmt.Sprintf("%d synced, %d conflicted, %d skipped", synced, len(conflicted), skipped))
-				u.Info("After resolving conflicts, run 'ww sync' again.")
-			} else {
-				u.Success(fmt.Sprintf("All %d worktree(s) synced.", synced))
+			if wtGit.IsRebaseInProgress() {
+				u.Info(fmt.Sprintf("  %s → %s", parent, branch))
+				u.Warn(fmt.Sprintf("    ⚠ Rebase in progress — resolve manually"))
+				conflicted[branch] = true
+				step.State = stack.StepConflict
+				save()
+				continue
 			}
-			return nil
-		},
+
+			u.Info(fmt.Sprintf("  %s → %s", parent, u.Bold(branch)))
+		}
+
+		var err error
+		if step.OldBase != "" {
+			err = wtGit.RebaseOnto(step.Onto, step.OldBase)
+		} else {
+			err = wtGit.Rebase(step.Onto)
+		}
+		if err != nil {
+			conflicted[branch] = true
+			step.State = stack.StepConflict
+			save()
+			u.Warn(fmt.Sprintf("    ✗ Conflict — resolve in %s", wtPath))
+			u.Info(fmt.Sprintf("      cd %s && git add <files>, then run 'ww sync --continue'", wtPath))
+			continue
+		}
+
+		finishRestackStep(bareDir, wtGit, step, u)
+		save()
+		synced++
+	}
+
+	fmt.Println()
+	if plan.Finished() {
+		if err := stack.ClearRestackPlan(bareDir); err != nil {
+			return fmt.Errorf("failed to clear restack plan: %w", err)
+		}
+	}
+	if len(conflicted) > 0 {
+		u.Warn(fmt.Sprintf("%d synced, %d conflicted, %d skipped", synced, len(conflicted), skipped))
+		u.Info("After resolving conflicts, run 'ww sync --continue'.")
+	} else {
+		u.Success(fmt.Sprintf("All %d worktree(s) synced.", synced))
+	}
+	return nil
+}
+
+// finishRestackStep marks a rebased step done and records it in the activity log.
+func finishRestackStep(bareDir string, wtGit *git.Git, step *stack.RestackStep, u *ui.UI) {
+	step.State = stack.StepDone
+	ahead, aheadErr := wtGit.CommitsAhead(step.Onto)
+	if aheadErr != nil {
+		u.Info(fmt.Sprintf("    %s Rebased onto %s", u.Green("✔"), step.Onto))
+	} else {
+		u.Info(fmt.Sprintf("    %s Rebased onto %s (%d commits ahead)", u.Green("✔"), step.Onto, ahead))
+	}
+	meta := map[string]string{"parent": step.Parent}
+	if aheadErr == nil {
+		meta["ahead"] = fmt.Sprintf("%d", ahead)
 	}
+	_ = log.Append(log.Event{
+		Action:   "sync",
+		Repo:     repoNameFromDir(bareDir),
+		Branch:   step.Branch,
+		Metadata: meta,
+	})
 }
 
 func syncAbort(st *stack.Stack, wtPaths map[string]string, verbose bool, u *ui.UI) error {
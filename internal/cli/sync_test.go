@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/iamrajjoshi/willow/internal/config"
 	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/stack"
 )
 
 type syncStackFixture struct {
@@ -236,3 +240,340 @@ func TestSync_ConflictSkipsDescendantsAndAbortClearsRebase(t *testing.T) {
 		t.Fatal("expected sync --abort to clear the in-progress rebase")
 	}
 }
+
+func TestSync_ContinueResumesFromConflictedBranch(t *testing.T) {
+	f := setupSyncStack(t, "synccontinue")
+
+	commitFile(t, f.FeatureADir, "conflict.txt", "base\n", "add conflict base")
+	if err := os.Chdir(f.MainDir); err != nil {
+		t.Fatalf("chdir main: %v", err)
+	}
+	if err := runApp("new", "conflict-child", "--base", "feature-a", "--no-fetch"); err != nil {
+		t.Fatalf("new conflict-child failed: %v", err)
+	}
+	childDir := filepath.Join(f.WorktreeDir, "conflict-child")
+	commitFile(t, childDir, "conflict.txt", "child\n", "child conflict edit")
+	if err := os.Chdir(f.MainDir); err != nil {
+		t.Fatalf("chdir main: %v", err)
+	}
+	if err := runApp("new", "conflict-grandchild", "--base", "conflict-child", "--no-fetch"); err != nil {
+		t.Fatalf("new conflict-grandchild failed: %v", err)
+	}
+	grandchildDir := filepath.Join(f.WorktreeDir, "conflict-grandchild")
+	commitFile(t, grandchildDir, "grandchild.txt", "grandchild\n", "grandchild work")
+	commitFile(t, f.FeatureADir, "conflict.txt", "parent\n", "parent conflict edit")
+
+	if _, err := captureStdout(t, func() error {
+		return runApp("sync", "--no-fetch")
+	}); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	plan, err := stack.LoadRestackPlan(f.BareDir)
+	if err != nil || plan == nil {
+		t.Fatalf("expected persisted restack plan, got %v, %v", plan, err)
+	}
+	if step := plan.Step("conflict-child"); step == nil || step.State != stack.StepConflict {
+		t.Fatalf("conflict-child step = %+v, want conflict", step)
+	}
+	if step := plan.Step("feature-b"); step == nil || step.State != stack.StepDone {
+		t.Fatalf("feature-b step = %+v, want done", step)
+	}
+
+	if err := runApp("sync", "--no-fetch"); err == nil || !strings.Contains(err.Error(), "already in progress") {
+		t.Fatalf("sync during restack error = %v, want in-progress error", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(childDir, "conflict.txt"), []byte("resolved\n"), 0o644); err != nil {
+		t.Fatalf("write resolution: %v", err)
+	}
+	gitOutput(t, childDir, "add", "conflict.txt")
+	featureBHead := gitOutput(t, f.FeatureBDir, "rev-parse", "HEAD")
+
+	out, err := captureStdout(t, func() error {
+		return runApp("sync", "--continue")
+	})
+	if err != nil {
+		t.Fatalf("sync --continue failed: %v", err)
+	}
+	if !strings.Contains(out, "All 2 worktree(s) synced") {
+		t.Fatalf("expected resumed sync summary, got:\n%s", out)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "rev-parse", "HEAD"); got != featureBHead {
+		t.Fatal("already-rebased feature-b should not be rebased again")
+	}
+	if _, err := (&git.Git{Dir: grandchildDir}).Run("merge-base", "--is-ancestor", "conflict-child", "HEAD"); err != nil {
+		t.Fatalf("grandchild should be rebased onto resolved child: %v", err)
+	}
+	if plan, _ := stack.LoadRestackPlan(f.BareDir); plan != nil {
+		t.Fatalf("restack plan should be cleared after completion, got %+v", plan)
+	}
+}
+
+func TestSync_ContinueWithoutPlan(t *testing.T) {
+	setupSyncStack(t, "syncnoplan")
+	if err := runApp("sync", "--continue"); err == nil || !strings.Contains(err.Error(), "no restack in progress") {
+		t.Fatalf("sync --continue error = %v, want no-restack error", err)
+	}
+}
+
+func TestStackRestack_SkipsRewrittenParentCommits(t *testing.T) {
+	f := setupSyncStack(t, "restackonto")
+
+	// Rewrite feature-a's only commit; a plain rebase would replay the old
+	// version of feature-a.txt on top of the new one and conflict.
+	if err := os.WriteFile(filepath.Join(f.FeatureADir, "feature-a.txt"), []byte("feature a v2\n"), 0o644); err != nil {
+		t.Fatalf("rewrite feature-a.txt: %v", err)
+	}
+	gitOutput(t, f.FeatureADir, "commit", "-a", "--amend", "-m", "feature a v2")
+
+	out, err := captureStdout(t, func() error {
+		return runApp("stack", "restack", "--no-fetch")
+	})
+	if err != nil {
+		t.Fatalf("stack restack failed: %v", err)
+	}
+	if !strings.Contains(out, "All 2 worktree(s) synced") {
+		t.Fatalf("expected clean restack, got:\n%s", out)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "rev-list", "--count", "feature-a..HEAD"); got != "1" {
+		t.Fatalf("feature-b should carry only its own commit, got %s", got)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "show", "HEAD:feature-a.txt"); got != "feature a v2" {
+		t.Fatalf("feature-b should see rewritten parent content, got %q", got)
+	}
+}
+
+// squashMergeFeatureA lands feature-a on origin's base branch as a single
+// squash commit and installs a gh stub that reports feature-a's PR as merged
+// and feature-b's PR as open against feature-a.
+func squashMergeFeatureA(t *testing.T, f syncStackFixture) string {
+	t.Helper()
+
+	featureAHead := gitOutput(t, f.FeatureADir, "rev-parse", "HEAD")
+	gitOutput(t, f.MainDir, "merge", "--squash", "feature-a")
+	gitOutput(t, f.MainDir, "commit", "-m", "feature a (#1)")
+	gitOutput(t, f.MainDir, "push", "origin", "HEAD:"+f.BaseBranch)
+
+	binDir, logPath := installTestCLIPath(t, "")
+	writeTestExecutable(t, binDir, "gh", fmt.Sprintf(`#!/bin/sh
+printf '%%s\n' "$*" >> %q
+case "$*" in
+  "pr list --search head:feature-a "*)
+    printf '[{"number":1,"headRefName":"feature-a","headRefOid":"%s","baseRefName":"%s","state":"MERGED","mergedAt":"2026-01-01T00:00:00Z"}]\n'
+    ;;
+  "pr list --head feature-b "*)
+    printf '[{"number":2,"headRefName":"feature-b","baseRefName":"feature-a","state":"OPEN"}]\n'
+    ;;
+  *)
+    printf '[]\n'
+    ;;
+esac
+`, logPath, featureAHead, f.BaseBranch))
+	return logPath
+}
+
+func TestSync_ReparentsChildOfMergedParent(t *testing.T) {
+	f := setupSyncStack(t, "syncreparent")
+	logPath := squashMergeFeatureA(t, f)
+	writeGlobalConfigFile(t, `{"defaults":{"retargetPRs":true}}`)
+	if err := os.Chdir(f.FeatureBDir); err != nil {
+		t.Fatalf("chdir feature-b: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runApp("sync")
+	})
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if !strings.Contains(out, "feature-a merged, now on "+f.BaseBranch) {
+		t.Fatalf("expected reparent message, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Retargeted #2 to "+f.BaseBranch) {
+		t.Fatalf("expected PR retarget message, got:\n%s", out)
+	}
+	if !strings.Contains(out, "All 1 worktree(s) synced") {
+		t.Fatalf("expected only feature-b to be restacked, got:\n%s", out)
+	}
+
+	if got := stack.Load(f.BareDir).Parent("feature-b"); got != f.BaseBranch {
+		t.Fatalf("feature-b parent = %q, want %q", got, f.BaseBranch)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "rev-list", "--count", "origin/"+f.BaseBranch+"..HEAD"); got != "1" {
+		t.Fatalf("feature-b should carry only its own commit after --onto rebase, got %s", got)
+	}
+	if !strings.Contains(readTestFile(t, logPath), "pr edit 2 --base "+f.BaseBranch) {
+		t.Fatalf("expected gh pr edit call, got:\n%s", readTestFile(t, logPath))
+	}
+
+	events, err := log.Read(log.ReadOpts{Branch: "feature-b"})
+	if err != nil {
+		t.Fatalf("read activity log: %v", err)
+	}
+	found := false
+	for _, e := range events {
+		if e.Action == "reparent" && e.Metadata["from"] == "feature-a" && e.Metadata["to"] == f.BaseBranch {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected reparent event, got %+v", events)
+	}
+}
+
+func TestGcPruneDryRunReportsReparent(t *testing.T) {
+	f := setupSyncStack(t, "gcreparent")
+	logPath := squashMergeFeatureA(t, f)
+
+	out, err := captureStdout(t, func() error {
+		return runApp("gc", "--repo", "gcreparent", "--prune", "--dry-run")
+	})
+	if err != nil {
+		t.Fatalf("gc --prune --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out, "Would reparent feature-b: feature-a merged, move onto "+f.BaseBranch) {
+		t.Fatalf("expected dry-run reparent, got:\n%s", out)
+	}
+	if got := stack.Load(f.BareDir).Parent("feature-b"); got != "feature-a" {
+		t.Fatalf("dry run should not change the stack, feature-b parent = %q", got)
+	}
+	if strings.Contains(readTestFile(t, logPath), "pr edit") {
+		t.Fatal("dry run should not retarget PRs")
+	}
+}
+
+func TestGcPruneDeclinedLeavesStackUntouched(t *testing.T) {
+	f := setupSyncStack(t, "gcdecline")
+	squashMergeFeatureA(t, f)
+	headBefore := gitOutput(t, f.FeatureBDir, "rev-parse", "HEAD")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe stdin: %v", err)
+	}
+	if _, err := w.WriteString("n\n"); err != nil {
+		t.Fatalf("write stdin: %v", err)
+	}
+	w.Close()
+	origStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = origStdin })
+
+	out, err := captureStdout(t, func() error {
+		return runApp("gc", "--repo", "gcdecline", "--prune")
+	})
+	if err != nil {
+		t.Fatalf("gc --prune failed: %v", err)
+	}
+	for _, want := range []string{"feature-b: feature-a merged, move onto " + f.BaseBranch, "Aborted."} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+	if got := stack.Load(f.BareDir).Parent("feature-b"); got != "feature-a" {
+		t.Fatalf("declined prune changed the stack, feature-b parent = %q", got)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "rev-parse", "HEAD"); got != headBefore {
+		t.Fatal("declined prune rebased feature-b")
+	}
+}
+
+func TestGcPruneYesReparentsThenRemovesMergedParent(t *testing.T) {
+	f := setupSyncStack(t, "gcyes")
+	squashMergeFeatureA(t, f)
+
+	out, err := captureStdout(t, func() error {
+		return runApp("gc", "--repo", "gcyes", "--prune", "--yes")
+	})
+	if err != nil {
+		t.Fatalf("gc --prune --yes failed: %v", err)
+	}
+	if got := stack.Load(f.BareDir).Parent("feature-b"); got != f.BaseBranch {
+		t.Fatalf("feature-b parent = %q, want %q\n%s", got, f.BaseBranch, out)
+	}
+	if got := gitOutput(t, f.FeatureBDir, "rev-list", "--count", "origin/"+f.BaseBranch+"..HEAD"); got != "1" {
+		t.Fatalf("feature-b should carry only its own commit, got %s", got)
+	}
+	if _, err := os.Stat(f.FeatureADir); !os.IsNotExist(err) {
+		t.Fatalf("merged parent feature-a should be removed:\n%s", out)
+	}
+}
+
+func TestGcPruneRefreshesStackTablesAfterReparent(t *testing.T) {
+	f := setupSyncStack(t, "gctables")
+	featureAHead := gitOutput(t, f.FeatureADir, "rev-parse", "HEAD")
+	logPath := squashMergeFeatureA(t, f)
+	stateDir := t.TempDir()
+	writeTestExecutable(t, filepath.Dir(logPath), "gh", fmt.Sprintf(`#!/bin/sh
+STATE_DIR=%q
+printf '%%s\n' "$*" >> %q
+case "$*" in
+  "pr list --search head:feature-a "*)
+    printf '[{"number":1,"headRefName":"feature-a","headRefOid":"%s","baseRefName":"%s","state":"MERGED","mergedAt":"2026-01-01T00:00:00Z"}]\n'
+    ;;
+  "pr list --json "*)
+    printf '[{"number":2,"headRefName":"feature-b","baseRefName":"%s","state":"OPEN"}]\n'
+    ;;
+  "pr view 2 "*)
+    printf 'Original body\n'
+    ;;
+  "pr edit 2 --body-file -")
+    cat > "$STATE_DIR/2"
+    ;;
+  *)
+    printf '[]\n'
+    ;;
+esac
+`, stateDir, logPath, featureAHead, f.BaseBranch, f.BaseBranch))
+	writeGlobalConfigFile(t, `{"defaults":{"updateStackTables":true}}`)
+
+	out, err := captureStdout(t, func() error {
+		return runApp("gc", "--repo", "gctables", "--prune", "--yes")
+	})
+	if err != nil {
+		t.Fatalf("gc --prune --yes failed: %v", err)
+	}
+	body := readTestFile(t, filepath.Join(stateDir, "2"))
+	if !strings.Contains(body, "| 👉 | **#2** |") || strings.Contains(body, "feature-a") {
+		t.Fatalf("PR #2 should list only the restacked branch, got:\n%s\n%s", body, out)
+	}
+}
+
+func TestRetargetPRTargetsUpstreamRepoInForkWorkflow(t *testing.T) {
+	bareDir := t.TempDir()
+	repoGit := &git.Git{Dir: bareDir}
+	for _, args := range [][]string{
+		{"init", "--bare"},
+		{"remote", "add", "upstream", "https://github.com/acme/widgets.git"},
+		{"remote", "add", "fork", "git@github.com:me/widgets.git"},
+	} {
+		if _, err := repoGit.Run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	binDir, logPath := installTestCLIPath(t, "")
+	writeTestExecutable(t, binDir, "gh", fmt.Sprintf(`#!/bin/sh
+printf '%%s\n' "$*" >> %q
+case "$*" in
+  "pr list "*)
+    printf '[{"number":7,"headRefName":"child","baseRefName":"parent","state":"OPEN"}]\n'
+    ;;
+esac
+`, logPath))
+
+	cfg := &config.Config{UpstreamRemote: "upstream", PushRemote: "fork"}
+	number, err := retargetPR(repoGit, cfg, bareDir, reparentedBranch{Branch: "child", From: "parent", To: "main"})
+	if err != nil || number != 7 {
+		t.Fatalf("retargetPR = %d, %v; want 7", number, err)
+	}
+	calls := readTestFile(t, logPath)
+	for _, want := range []string{"pr list --head child", "pr edit 7 --base main --repo acme/widgets"} {
+		if !strings.Contains(calls, want) {
+			t.Errorf("gh calls missing %q:\n%s", want, calls)
+		}
+	}
+	if strings.Count(calls, "--repo acme/widgets") != 2 {
+		t.Errorf("both gh calls should target acme/widgets:\n%s", calls)
+	}
+}
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/tmux"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/urfave/cli/v3"
+)
+
+// tellSubmitDelay separates the pasted message from the Enter that submits
+// it; agent TUIs treat an Enter inside the paste as a newline.
+var tellSubmitDelay = 150 * time.Millisecond
+
+func tellCmd() *cli.Command {
+	return &cli.Command{
+		Name:          "tell",
+		Usage:         "Send a follow-up prompt to a worktree's agent in tmux",
+		UsageText:     "ww tell [flags] <worktree> <message>\n\nUse - as the message to read it from stdin.",
+		ShellComplete: completeWorktreesWithFlag,
+		Arguments: []cli.Argument{
+			&cli.StringArg{
+				Name:      "target",
+				UsageText: "<worktree>",
+			},
+			&cli.StringArg{
+				Name:      "message",
+				UsageText: "<message>",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Target a willow-managed repo by name",
+			},
+			&cli.StringFlag{
+				Name:  "session",
+				Usage: "Agent session ID or prefix, when the worktree has several",
+			},
+			&cli.StringFlag{
+				Name:  "pane",
+				Usage: "tmux pane ID (e.g. %3) to type into instead of finding the agent's pane",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Send even if the agent is not DONE or WAIT",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.tell")()
+			flags := parseFlags(cmd)
+			g := flags.NewGit()
+			u := flags.NewUI()
+
+			target, message := cmd.StringArg("target"), cmd.StringArg("message")
+			if target == "" || message == "" {
+				return errors.Userf("worktree and message are required\n\nUsage: ww tell <worktree> <message>")
+			}
+			if message == "-" {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read message: %w", err)
+				}
+				message = string(data)
+			}
+			message = strings.TrimSpace(message)
+			if message == "" {
+				return errors.Userf("message is empty")
+			}
+
+			rwt, err := resolveWorktreeTarget(g, cmd.String("repo"), target)
+			if err != nil {
+				return err
+			}
+			tt, err := findTellTarget(rwt.Repo.Name, rwt.Worktree.Path, cmd.String("session"), cmd.String("pane"), cmd.Bool("force"))
+			if err != nil {
+				return err
+			}
+			if err := sendTell(tt, message); err != nil {
+				return err
+			}
+			_ = log.Append(log.Event{Action: "tell", Repo: rwt.Repo.Name, Branch: rwt.Worktree.MatchName(), Metadata: map[string]string{"message": truncatePrompt(message), "agent": tt.Session.Harness}})
+			u.Success(fmt.Sprintf("Sent to %s in %s (pane %s)", sessionLabel(tt.Session), u.Bold(rwt.Worktree.DisplayName()), tt.Pane.ID))
+			return nil
+		},
+	}
+}
+
+// agentTarget is an agent session and the tmux pane it runs in.
+type agentTarget struct {
+	Session *agent.SessionStatus
+	Pane    tmux.Pane
+}
+
+// tellReady are the statuses in which an agent takes a follow-up prompt.
+var tellReady = []agent.Status{agent.StatusDone, agent.StatusWait}
+
+// findTellTarget picks the session to send to, checks that it is waiting
+// for input, and finds the tmux pane it runs in.
+func findTellTarget(repoName, wtPath, sessionID, paneID string, force bool) (*agentTarget, error) {
+	hint := "Wait for it to finish, or pass --force to send anyway."
+	if force {
+		hint = ""
+	}
+	return findAgentTarget(repoName, wtPath, sessionID, paneID, tellReady, hint)
+}
+
+// findAgentTarget picks the session matching sessionID, preferring one in a
+// ready status, and finds its tmux pane. Unless hint is empty, a session
+// that isn't ready is an error, with hint as its advice.
+func findAgentTarget(repoName, wtPath, sessionID, paneID string, ready []agent.Status, hint string) (*agentTarget, error) {
+	name := filepath.Base(wtPath)
+	ss, err := pickAgentSession(agent.ReadAllSessions(repoName, name), sessionID, name, ready)
+	if err != nil {
+		return nil, err
+	}
+	if status := agent.EffectiveStatus(ss.Status, ss.Timestamp); hint != "" && !slices.Contains(ready, status) {
+		want := make([]string, len(ready))
+		for i, s := range ready {
+			want[i] = string(s)
+		}
+		return nil, errors.Userf("%s in %s is %s, not %s\n\n%s", sessionLabel(ss), name, status, strings.Join(want, " or "), hint)
+	}
+
+	panes := tmux.ListPanes()
+	if paneID != "" {
+		for _, p := range panes {
+			if p.ID == paneID {
+				return &agentTarget{Session: ss, Pane: p}, nil
+			}
+		}
+		return nil, errors.Userf("no tmux pane %s", paneID)
+	}
+	executable := ""
+	if h, ok := harness.Get(ss.Harness); ok {
+		executable = h.ExecutableName()
+	}
+	pane, err := findAgentPane(panes, wtPath, executable)
+	if err != nil {
+		return nil, err
+	}
+	return &agentTarget{Session: ss, Pane: pane}, nil
+}
+
+// pickAgentSession returns the session matching id, or the worktree's only
+// session in a ready status when id is empty.
+func pickAgentSession(sessions []*agent.SessionStatus, id, name string, ready []agent.Status) (*agent.SessionStatus, error) {
+	if id != "" {
+		for _, ss := range sessions {
+			if strings.HasPrefix(ss.SessionID, id) {
+				return ss, nil
+			}
+		}
+		return nil, errors.Userf("no agent session matching %q in %s", id, name)
+	}
+	switch len(sessions) {
+	case 0:
+		return nil, errors.Userf("no agent session in %s", name)
+	case 1:
+		return sessions[0], nil
+	}
+
+	var matches []*agent.SessionStatus
+	for _, ss := range sessions {
+		if slices.Contains(ready, agent.EffectiveStatus(ss.Status, ss.Timestamp)) {
+			matches = append(matches, ss)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	lines := fmt.Sprintf("%s has %d agent sessions, pass --session with one of:\n", name, len(sessions))
+	for _, ss := range sessions {
+		lines += fmt.Sprintf("  %s  %s\n", sessionLabel(ss), agent.EffectiveStatus(ss.Status, ss.Timestamp))
+	}
+	return nil, errors.User(fmt.Errorf("%s", strings.TrimRight(lines, "\n")))
+}
+
+var shellCommands = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true, "ksh": true, "tcsh": true, "nu": true,
+}
+
+// findAgentPane finds the pane inside wtPath running executable. Failing
+// that, it settles for the only pane inside wtPath that isn't sitting at a
+// shell prompt, since some agents run under an interpreter such as node.
+func findAgentPane(panes []tmux.Pane, wtPath, executable string) (tmux.Pane, error) {
+	var exact, busy []tmux.Pane
+	for _, p := range panes {
+		if p.Path == "" || !pathWithin(wtPath, p.Path) {
+			continue
+		}
+		switch {
+		case executable != "" && p.Command == executable:
+			exact = append(exact, p)
+		case !shellCommands[strings.TrimPrefix(p.Command, "-")]:
+			busy = append(busy, p)
+		}
+	}
+	candidates := exact
+	if len(candidates) == 0 {
+		candidates = busy
+	}
+	switch len(candidates) {
+	case 0:
+		return tmux.Pane{}, errors.Userf("no tmux pane is running an agent in %s\n\nOpen it with 'ww sw', or pass --pane.", filepath.Base(wtPath))
+	case 1:
+		return candidates[0], nil
+	}
+	lines := "several tmux panes could be the agent, pass --pane with one of:\n"
+	for _, p := range candidates {
+		lines += fmt.Sprintf("  %s  %s  %s\n", p.ID, p.Session, p.Command)
+	}
+	return tmux.Pane{}, errors.User(fmt.Errorf("%s", strings.TrimRight(lines, "\n")))
+}
+
+func sendTell(tt *agentTarget, message string) error {
+	if err := tmux.PasteText(tt.Pane.ID, message); err != nil {
+		return fmt.Errorf("failed to type message: %w", err)
+	}
+	time.Sleep(tellSubmitDelay)
+	if err := tmux.SendKeys(tt.Pane.ID, "Enter"); err != nil {
+		return fmt.Errorf("failed to submit message: %w", err)
+	}
+	return nil
+}
+
+func sessionLabel(ss *agent.SessionStatus) string {
+	return fmt.Sprintf("[%s] %s", ss.Harness, agent.ShortSessionID(ss.SessionID))
+}
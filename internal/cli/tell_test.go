@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/tmux"
+)
+
+func TestTellPastesIntoAgentPane(t *testing.T) {
+	origin := setupTestEnv(t)
+	if err := runApp("clone", origin, "tellrepo"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	bareDir := filepath.Join(config.ReposDir(), "tellrepo.git")
+	mainBranch, _ := (&git.Git{Dir: bareDir}).DefaultBranch()
+	wtRoot := filepath.Join(config.WorktreesDir(), "tellrepo")
+	os.Chdir(filepath.Join(wtRoot, mainBranch))
+	if err := runApp("new", "feature", "--no-fetch"); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	featureDir := filepath.Join(wtRoot, "feature")
+
+	binDir := t.TempDir()
+	tmuxLog := filepath.Join(t.TempDir(), "tmux.log")
+	writeTestExecutable(t, binDir, "tmux", "#!/bin/sh\n"+
+		"printf '%s\\n' \"$*\" >> "+shellQuote(tmuxLog)+"\n"+
+		"if [ \"$1\" = list-panes ]; then\n"+
+		"  printf '%%1\\ttellrepo/feature\\t"+featureDir+"\\tzsh\\n'\n"+
+		"  printf '%%2\\ttellrepo/feature\\t"+featureDir+"/sub\\tclaude\\n'\n"+
+		"  printf '%%3\\tother\\t/elsewhere\\tclaude\\n'\n"+
+		"fi\n")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	prevDelay := tellSubmitDelay
+	tellSubmitDelay = 0
+	t.Cleanup(func() { tellSubmitDelay = prevDelay })
+
+	writeActiveSessionFile(t, "tellrepo", "feature", "sess1", agent.StatusBusy)
+	err := runApp("tell", "feature", "keep going")
+	if err == nil || !strings.Contains(err.Error(), "not DONE or WAIT") {
+		t.Fatalf("tell to busy agent error = %v", err)
+	}
+
+	writeActiveSessionFile(t, "tellrepo", "feature", "sess1", agent.StatusDone)
+	if err := runApp("tell", "feature", "now add tests"); err != nil {
+		t.Fatalf("tell: %v", err)
+	}
+	logText := readTestFile(t, tmuxLog)
+	for _, want := range []string{
+		"set-buffer -b willow-paste-",
+		"-- now add tests",
+		"paste-buffer -p -d -b willow-paste-",
+		"send-keys -t %2 Enter",
+	} {
+		if !strings.Contains(logText, want) {
+			t.Errorf("tmux log missing %q:\n%s", want, logText)
+		}
+	}
+}
+
+func TestFindAgentPane(t *testing.T) {
+	wt := t.TempDir()
+	panes := []tmux.Pane{
+		{ID: "%1", Path: wt, Command: "zsh"},
+		{ID: "%2", Path: wt, Command: "node"},
+		{ID: "%3", Path: t.TempDir(), Command: "claude"},
+	}
+	if p, err := findAgentPane(panes, wt, "claude"); err != nil || p.ID != "%2" {
+		t.Errorf("findAgentPane = %+v, %v; want the only non-shell pane %%2", p, err)
+	}
+
+	panes = append(panes, tmux.Pane{ID: "%4", Path: wt, Command: "claude"})
+	if p, err := findAgentPane(panes, wt, "claude"); err != nil || p.ID != "%4" {
+		t.Errorf("findAgentPane = %+v, %v; want the claude pane %%4", p, err)
+	}
+
+	if _, err := findAgentPane(panes, wt, "codex"); err == nil || !strings.Contains(err.Error(), "--pane") {
+		t.Errorf("ambiguous panes error = %v", err)
+	}
+	if _, err := findAgentPane(panes[:1], wt, "claude"); err == nil || !strings.Contains(err.Error(), "no tmux pane") {
+		t.Errorf("no agent pane error = %v", err)
+	}
+}
@@ -19,15 +19,17 @@ import (
 	"github.com/iamrajjoshi/willow/internal/git"
 	"github.com/iamrajjoshi/willow/internal/log"
 	"github.com/iamrajjoshi/willow/internal/parallel"
+	"github.com/iamrajjoshi/willow/internal/server"
 	"github.com/iamrajjoshi/willow/internal/stack"
 	"github.com/iamrajjoshi/willow/internal/termfmt"
 	"github.com/iamrajjoshi/willow/internal/tmux"
 	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
 	"github.com/iamrajjoshi/willow/internal/worktree"
 	"github.com/urfave/cli/v3"
 )
 
-const tmuxPickerHeader = "^N new ^T detach ^U promote ^B stack ^E existing ^P PR ^G dispatch ^O agent ^S sync ^D rm ^X prune"
+const tmuxPickerHeader = "^N new ^T detach ^U promote ^B stack ^E existing ^P PR ^G dispatch ^O agent ^S sync ^L tell ^Y approve ^V deny ^D rm ^X prune"
 
 func tmuxCmd() *cli.Command {
 	return &cli.Command{
@@ -71,7 +73,9 @@ func tmuxPickCmd() *cli.Command {
 			}
 
 			for {
-				items, err := tmux.BuildPickerItemsWithOptions(ctx, repoFilter, tmux.PickerBuildOptions{})
+				items, err := tmux.BuildPickerItemsWithOptions(ctx, repoFilter, tmux.PickerBuildOptions{
+					Sessions: serverSessionIndex(),
+				})
 				if err != nil {
 					return err
 				}
@@ -99,7 +103,7 @@ func tmuxPickCmd() *cli.Command {
 					fzf.WithDelimiter("\\|"),
 					fzf.WithNth("1,2"),
 					fzf.WithHeader(tmuxPickerHeader),
-					fzf.WithExpectKeys("ctrl-n", "ctrl-t", "ctrl-u", "ctrl-b", "ctrl-e", "ctrl-p", "ctrl-g", "ctrl-o", "ctrl-s", "ctrl-d", "ctrl-x"),
+					fzf.WithExpectKeys("ctrl-n", "ctrl-t", "ctrl-u", "ctrl-b", "ctrl-e", "ctrl-p", "ctrl-g", "ctrl-o", "ctrl-s", "ctrl-l", "ctrl-y", "ctrl-v", "ctrl-d", "ctrl-x"),
 					fzf.WithPrintQuery(),
 				}
 
@@ -196,6 +200,28 @@ func tmuxPickCmd() *cli.Command {
 					fmt.Fscanln(os.Stdin)
 					continue
 
+				case "ctrl-l":
+					if result.Selection == "" {
+						continue
+					}
+					if err := tmuxPickTell(result.Selection, items); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+						fmt.Fprintf(os.Stderr, "\nPress Enter to return to picker...\n")
+						fmt.Fscanln(os.Stdin)
+					}
+					continue
+
+				case "ctrl-y", "ctrl-v":
+					if result.Selection == "" {
+						continue
+					}
+					if err := tmuxPickPermission(result.Selection, items, result.Key == "ctrl-y"); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+						fmt.Fprintf(os.Stderr, "\nPress Enter to return to picker...\n")
+						fmt.Fscanln(os.Stdin)
+					}
+					continue
+
 				case "ctrl-d":
 					if result.Selection == "" {
 						continue
@@ -270,7 +296,7 @@ func tmuxExistingBranchesCmd() *cli.Command {
 			},
 			&cli.BoolFlag{
 				Name:  "refresh",
-				Usage: "Fetch origin before listing branches",
+				Usage: "Fetch remotes before listing branches",
 			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -491,7 +517,7 @@ func tmuxPickExisting(self, repoFilter, sessionName string, items []tmux.PickerI
 		remoteBranches = nil
 	}
 	if len(remoteBranches) == 0 {
-		remoteBranches, err = repoGit.RemoteBranches()
+		remoteBranches, err = checkoutRemoteBranches(repoGit, cfg)
 		if err != nil {
 			return fmt.Errorf("failed to list remote branches: %w", err)
 		}
@@ -559,11 +585,12 @@ func existingBranchesForRepo(repo string, refresh bool) ([]string, error) {
 	}
 
 	repoGit := &git.Git{Dir: bareDir}
+	cfg := config.Load(bareDir)
 	if refresh {
-		_, _ = repoGit.Run("fetch", "--no-tags", "origin")
+		_, _ = repoGit.Run(fetchRemotesArgs(cfg, "--no-tags")...)
 	}
 
-	remoteBranches, err := repoGit.RemoteBranches()
+	remoteBranches, err := checkoutRemoteBranches(repoGit, cfg)
 	if err != nil {
 		cached, cacheErr := loadExistingBranchCache(repo)
 		if cacheErr == nil {
@@ -810,32 +837,9 @@ func tmuxPickDispatchForRepo(self, query, repo, agentID string, cfg *config.Conf
 	meta := map[string]string{"prompt": truncatePrompt(query), "agent": h.ID()}
 	_ = log.Append(log.Event{Action: "dispatch", Repo: repo, Branch: branch, Metadata: meta})
 
-	wtDir := filepath.Base(wtPath)
-	repoName := filepath.Base(filepath.Dir(wtPath))
-
-	sessName := tmux.SessionNameForWorktree(repoName, wtDir)
-
-	promptFile := filepath.Join(config.WillowHome(), "prompts", repoName, wtDir+".prompt")
-	if err := os.MkdirAll(filepath.Dir(promptFile), 0o755); err != nil {
-		return fmt.Errorf("failed to create prompts dir: %w", err)
-	}
-	if err := os.WriteFile(promptFile, []byte(query), 0o644); err != nil {
-		return fmt.Errorf("failed to write prompt file: %w", err)
-	}
-
-	if err := tmux.NewSession(sessName, wtPath, cfg.Tmux.Layout, cfg.Tmux.Panes); err != nil {
-		return fmt.Errorf("failed to create tmux session: %w", err)
-	}
-
-	promptArg := fmt.Sprintf(`"$(cat %s)"`, shellQuote(promptFile))
-	agentCmd := h.BuildShellLaunch(harness.ShellLaunchOptions{
-		PromptArg:    promptArg,
-		PromptArgRaw: true,
-		Overrides:    harness.OverridesFor(cfg, h.ID()),
-	})
-	agentCmd = fmt.Sprintf("%s; rm -f %s", agentCmd, shellQuote(promptFile))
-	if err := tmux.SendKeys(sessName, agentCmd, "Enter"); err != nil {
-		return fmt.Errorf("failed to send agent command: %w", err)
+	sessName, err := startDispatchSession(wtPath, query, h, cfg, false)
+	if err != nil {
+		return err
 	}
 
 	return tmux.SwitchClient(sessName)
@@ -907,6 +911,65 @@ func resolveRepo(repoFilter, sessionName string, items []tmux.PickerItem) (strin
 	return selected, nil
 }
 
+// tmuxPickTell prompts for a message and sends it to the selected
+// worktree's agent, checking first that the agent is ready for one.
+func tmuxPickTell(selection string, items []tmux.PickerItem) error {
+	wtPath := tmux.ExtractPathFromLine(selection)
+	item := findItemByPath(items, wtPath)
+	if item == nil {
+		return fmt.Errorf("worktree not found: %s", wtPath)
+	}
+	tt, err := findTellTarget(item.RepoName, item.WtPath, "", "", false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Message for %s %s: ", sessionLabel(tt.Session), item.WtDirName)
+	message := readTrimmedStdinLine()
+	if message == "" {
+		return nil
+	}
+	if err := sendTell(tt, message); err != nil {
+		return err
+	}
+	_ = log.Append(log.Event{Action: "tell", Repo: item.RepoName, Branch: item.Branch, Metadata: map[string]string{"message": truncatePrompt(message), "agent": tt.Session.Harness}})
+	return nil
+}
+
+// tmuxPickPermission shows the tool call the selected worktree's agent is
+// waiting on and, once confirmed, approves or denies it.
+func tmuxPickPermission(selection string, items []tmux.PickerItem, approve bool) error {
+	wtPath := tmux.ExtractPathFromLine(selection)
+	item := findItemByPath(items, wtPath)
+	if item == nil {
+		return fmt.Errorf("worktree not found: %s", wtPath)
+	}
+	pt, err := findPermissionTarget(item.RepoName, item.WtPath, "", "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(os.Stderr, formatPendingTool(&ui.UI{Out: os.Stderr}, pt))
+	if err := requirePermissionPrompt(pt); err != nil {
+		return err
+	}
+	action := "Approve"
+	if !approve {
+		action = "Deny"
+	}
+	fmt.Fprintf(os.Stderr, "\n%s? [y/N] ", action)
+	answer := readTrimmedStdinLine()
+	if answer != "y" && answer != "Y" {
+		return nil
+	}
+	bareDir, _ := config.ResolveRepo(item.RepoName)
+	if err := answerPermission(config.Load(bareDir), pt, approve, ""); err != nil {
+		return err
+	}
+	logPermissionAnswer(strings.ToLower(action), item.RepoName, item.Branch, pt)
+	return nil
+}
+
 func tmuxPickDelete(self, selection string, items []tmux.PickerItem) error {
 	wtPath := tmux.ExtractPathFromLine(selection)
 	item := findItemByPath(items, wtPath)
@@ -1021,9 +1084,9 @@ func cleanupCandidateForPickerItem(item tmux.PickerItem) (cleanup.Candidate, err
 		reasons = []cleanup.Reason{cleanup.ReasonMergedPR}
 	}
 	expectedBaseRef := item.ExpectedBaseRef
-	if expectedBaseRef == "" {
-		repoGit := &git.Git{Dir: bareDir}
+	if expectedBaseRef == "" && !item.Detached {
 		cfg := config.Load(bareDir)
+		repoGit := &git.Git{Dir: bareDir, Remote: cfg.Upstream()}
 		st := stack.Load(bareDir)
 		baseBranch := repoGit.ResolveBaseBranch(cfg.BaseBranch)
 		expectedBaseRef = cleanup.ExpectedBaseRef(repoGit, st, baseBranch, item.Branch)
@@ -1035,6 +1098,7 @@ func cleanupCandidateForPickerItem(item tmux.PickerItem) (cleanup.Candidate, err
 		Head:            item.Head,
 		Path:            item.WtPath,
 		WtDirName:       item.WtDirName,
+		Detached:        item.Detached,
 		ExpectedBaseRef: expectedBaseRef,
 		Reasons:         reasons,
 	}, nil
@@ -1091,8 +1155,8 @@ func loadRepoConfig(repoName string) *config.Config {
 func ensureTmuxSession(repoName, wtDir, wtPath string) error {
 	sessName := tmux.SessionNameForWorktree(repoName, wtDir)
 	if !tmux.SessionExists(sessName) {
-		cfg := loadRepoConfig(repoName)
-		if err := tmux.NewSession(sessName, wtPath, cfg.Tmux.Layout, cfg.Tmux.Panes); err != nil {
+		cfg := withWorktreeProfile(loadRepoConfig(repoName), wtPath, nil)
+		if err := tmux.NewSession(sessName, wtPath, worktreeEnv(cfg, wtPath, nil), cfg.Tmux.Layout, cfg.Tmux.Panes); err != nil {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
 	}
@@ -1259,7 +1323,7 @@ func printPreviewMetadata(wtPath, repoName string) {
 		}
 	}
 
-	if diffOut, err := g.Run("diff", "--shortstat", fmt.Sprintf("origin/%s...HEAD", baseBranch)); err == nil {
+	if diffOut, err := g.Run("diff", "--shortstat", fmt.Sprintf("%s/%s...HEAD", repoCfg.Upstream(), baseBranch)); err == nil {
 		stats := git.ParseShortstat(diffOut)
 		fmt.Printf("  \033[1mDiff:\033[0m    %s\n", stats)
 	}
@@ -1343,6 +1407,7 @@ func tmuxListCmd() *cli.Command {
 			defer trace.Span(ctx, "tmux.list")()
 			items, err := tmux.BuildPickerItemsWithOptions(ctx, cmd.String("repo"), tmux.PickerBuildOptions{
 				RefreshGitHubMerged: cmd.Bool("refresh-github-merged"),
+				Sessions:            serverSessionIndex(),
 			})
 			if err != nil {
 				return err
@@ -1375,9 +1440,10 @@ func tmuxStatusBarCmd() *cli.Command {
 			done := trace.Span(ctx, "tmux.ListSessions")
 			sessionSet := tmux.ListSessions()
 			done()
+			idx := serverSessionIndex()
 
 			results := parallel.Map(repos, func(_ int, repoName string) tmuxStatusBarRepoResult {
-				return collectTmuxStatusBarRepo(ctx, repoName, sessionSet)
+				return collectTmuxStatusBarRepo(ctx, repoName, sessionSet, idx)
 			})
 
 			for _, result := range results {
@@ -1408,7 +1474,7 @@ type tmuxStatusBarRepoResult struct {
 	statuses     map[string]agent.Status
 }
 
-func collectTmuxStatusBarRepo(ctx context.Context, repoName string, sessionSet map[string]bool) tmuxStatusBarRepoResult {
+func collectTmuxStatusBarRepo(ctx context.Context, repoName string, sessionSet map[string]bool, idx *agent.SessionIndex) tmuxStatusBarRepoResult {
 	result := tmuxStatusBarRepoResult{statuses: make(map[string]agent.Status)}
 	bareDir, err := config.ResolveRepo(repoName)
 	if err != nil {
@@ -1427,7 +1493,7 @@ func collectTmuxStatusBarRepo(ctx context.Context, repoName string, sessionSet m
 		}
 		result.totalWt++
 		wtDir := filepath.Base(wt.Path)
-		sessions := agent.ReadAllSessions(repoName, wtDir)
+		sessions := idx.ReadAllSessions(repoName, wtDir)
 		ws := agent.AggregateStatus(sessions)
 
 		// Clean orphaned sessions whose tmux session no longer exists.
@@ -1449,6 +1515,17 @@ func collectTmuxStatusBarRepo(ctx context.Context, repoName string, sessionSet m
 	return result
 }
 
+// serverSessionIndex returns the session index held by a running 'ww serve',
+// sparing short-lived commands a full walk of the status directory. Returns
+// nil (read from disk) when no server is reachable.
+func serverSessionIndex() *agent.SessionIndex {
+	sessions, err := server.FetchSessions(server.DefaultSocketPath())
+	if err != nil {
+		return nil
+	}
+	return agent.NewSessionIndex(sessions)
+}
+
 func tmuxInstallCmd() *cli.Command {
 	return &cli.Command{
 		Name:  "install",
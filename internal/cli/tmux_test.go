@@ -299,7 +299,7 @@ func TestMoveToFrontWithStack_DetachedOnlyMovesSelectedWorktree(t *testing.T) {
 }
 
 func TestTmuxPickerHeaderActions(t *testing.T) {
-	want := "^N new ^T detach ^U promote ^B stack ^E existing ^P PR ^G dispatch ^O agent ^S sync ^D rm ^X prune"
+	want := "^N new ^T detach ^U promote ^B stack ^E existing ^P PR ^G dispatch ^O agent ^S sync ^L tell ^Y approve ^V deny ^D rm ^X prune"
 	if tmuxPickerHeader != want {
 		t.Fatalf("tmux picker header = %q, want %q", tmuxPickerHeader, want)
 	}
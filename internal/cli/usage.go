@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/errors"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/trace"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
+	"github.com/urfave/cli/v3"
+)
+
+func usageCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "usage",
+		Usage: "Report agent token usage and estimated cost",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only count usage after duration (e.g. 7d, 24h, 30m)",
+				Value: "7d",
+			},
+			&cli.StringFlag{
+				Name:    "repo",
+				Aliases: []string{"r"},
+				Usage:   "Filter by repo name",
+			},
+			&cli.StringFlag{
+				Name:  "by",
+				Usage: "Group by repo, worktree, harness, model, or day",
+				Value: usage.ByRepo,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			defer trace.Span(ctx, "cli.usage")()
+			flags := parseFlags(cmd)
+			u := flags.NewUI()
+
+			opts := usage.ReadOpts{Repo: cmd.String("repo")}
+			if since := cmd.String("since"); since != "" {
+				d, err := parseDuration(since)
+				if err != nil {
+					return err
+				}
+				opts.Since = time.Now().Add(-d)
+			}
+
+			records, err := usage.Read(opts)
+			if err != nil {
+				return fmt.Errorf("read usage: %w", err)
+			}
+			summaries, err := usage.Summarize(records, cmd.String("by"))
+			if err != nil {
+				return errors.Userf("%v", err)
+			}
+
+			if cmd.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(summaries)
+			}
+
+			if len(summaries) == 0 {
+				u.Info("No agent usage recorded.")
+				return nil
+			}
+
+			for _, line := range formatUsageLines(u, cmd.String("by"), summaries, termfmt.TerminalWidth()) {
+				u.Info(line)
+			}
+			return nil
+		},
+	}
+}
+
+func formatUsageLines(u *ui.UI, by string, summaries []usage.Summary, width int) []string {
+	type row struct {
+		key, sessions, input, output, cache, cost string
+	}
+	header := row{key: strings.ToUpper(by), sessions: "SESSIONS", input: "INPUT", output: "OUTPUT", cache: "CACHE", cost: "COST"}
+
+	var total usage.Summary
+	unpriced := false
+	rows := []row{header}
+	for _, s := range summaries {
+		cost := usage.FormatCost(s.CostUSD)
+		if s.Unpriced {
+			cost += "*"
+			unpriced = true
+		}
+		rows = append(rows, row{
+			key:      s.Key,
+			sessions: fmt.Sprintf("%d", s.Sessions),
+			input:    usage.FormatTokens(s.Tokens.Input),
+			output:   usage.FormatTokens(s.Tokens.Output),
+			cache:    usage.FormatTokens(s.Tokens.CacheRead + s.Tokens.CacheWrite),
+			cost:     cost,
+		})
+		total.Tokens = total.Tokens.Add(s.Tokens)
+		total.CostUSD += s.CostUSD
+	}
+
+	keyW, sessW, inW, outW, cacheW, costW := 0, 0, 0, 0, 0, 0
+	for _, r := range rows {
+		keyW = max(keyW, termfmt.VisibleWidth(r.key))
+		sessW = max(sessW, termfmt.VisibleWidth(r.sessions))
+		inW = max(inW, termfmt.VisibleWidth(r.input))
+		outW = max(outW, termfmt.VisibleWidth(r.output))
+		cacheW = max(cacheW, termfmt.VisibleWidth(r.cache))
+		costW = max(costW, termfmt.VisibleWidth(r.cost))
+	}
+	fixed := 2 + 2 + sessW + 2 + inW + 2 + outW + 2 + cacheW + 2 + costW
+	if available := termfmt.Width(width) - fixed; available < keyW {
+		keyW = max(1, available)
+	}
+
+	format := func(r row) string {
+		return fmt.Sprintf("  %s  %*s  %*s  %*s  %*s  %*s",
+			termfmt.FitRight(r.key, keyW), sessW, r.sessions, inW, r.input, outW, r.output, cacheW, r.cache, costW, r.cost)
+	}
+	lines := make([]string, 0, len(rows)+3)
+	lines = append(lines, u.Bold(format(rows[0])))
+	for _, r := range rows[1:] {
+		lines = append(lines, format(r))
+	}
+	lines = append(lines, "", fmt.Sprintf("  Total: %s tokens, %s",
+		usage.FormatTokens(total.Tokens.Total()), u.Bold(usage.FormatCost(total.CostUSD))))
+	if unpriced {
+		lines = append(lines, u.Dim("  * includes models without a known price; their tokens are not costed"))
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
+)
+
+func seedUsage(t *testing.T) {
+	t.Helper()
+	now := time.Now().UTC()
+	for _, r := range []usage.Record{
+		{Timestamp: now.Add(-30 * 24 * time.Hour), Repo: "api", Worktree: "old", Harness: "claude", Session: "s0",
+			Usage: harness.Usage{"claude-sonnet-4-5": {Input: 9_000_000}}},
+		{Timestamp: now.Add(-time.Hour), Repo: "api", Worktree: "auth", Harness: "claude", Session: "s1",
+			Usage: harness.Usage{"claude-sonnet-4-5": {Input: 1_000_000, Output: 100_000}}},
+		{Timestamp: now.Add(-time.Hour), Repo: "web", Worktree: "nav", Harness: "codex", Session: "s2",
+			Usage: harness.Usage{"gpt-5-codex": {Input: 1_000_000}}},
+	} {
+		if err := usage.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUsageCmdJSONFiltersByRepoAndSince(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	seedUsage(t)
+
+	out, err := captureStdout(t, func() error {
+		return runApp("usage", "--since", "7d", "--repo", "api", "--by", "worktree", "--json")
+	})
+	if err != nil {
+		t.Fatalf("ww usage: %v", err)
+	}
+	var got []usage.Summary
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(got) != 1 || got[0].Key != "api/auth" || got[0].Sessions != 1 {
+		t.Fatalf("summaries = %+v, want only api/auth", got)
+	}
+	if got[0].CostUSD < 4.49 || got[0].CostUSD > 4.51 {
+		t.Errorf("CostUSD = %v, want 4.5", got[0].CostUSD)
+	}
+}
+
+func TestUsageCmdRejectsUnknownGrouping(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := runApp("usage", "--by", "branch")
+	if err == nil || !strings.Contains(err.Error(), "unknown grouping") {
+		t.Fatalf("err = %v, want unknown grouping", err)
+	}
+}
+
+func TestFormatUsageLinesShowsTotalsAndUnpricedNote(t *testing.T) {
+	u := &ui.UI{}
+	lines := formatUsageLines(u, usage.ByModel, []usage.Summary{
+		{Key: "claude-sonnet-4-5", Tokens: harness.TokenUsage{Input: 1_000_000, Output: 100_000}, CostUSD: 4.5, Sessions: 2},
+		{Key: "mystery", Tokens: harness.TokenUsage{Input: 500}, Sessions: 1, Unpriced: true},
+	}, 120)
+	plain := termfmt.StripANSI(strings.Join(lines, "\n"))
+	for _, want := range []string{"MODEL", "claude-sonnet-4-5", "$4.50", "$0.00*", "Total: 1.1M tokens, $4.50", "without a known price"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("output missing %q:\n%s", want, plain)
+		}
+	}
+}
@@ -124,6 +124,19 @@ func collectAllWorktrees(repos []repoInfo, verbose bool) []repoWorktree {
 	return all
 }
 
+// resolveWorktreeTarget finds the worktree named by target, or the current
+// worktree when target is empty.
+func resolveWorktreeTarget(g *git.Git, repoFlag, target string) (*repoWorktree, error) {
+	if target == "" {
+		return currentManagedWorktree(g)
+	}
+	repos, err := resolveRepos(g, repoFlag)
+	if err != nil {
+		return nil, err
+	}
+	return findCrossRepoWorktree(collectAllWorktrees(repos, g.Verbose), target)
+}
+
 func findCrossRepoWorktree(rwts []repoWorktree, target string) (*repoWorktree, error) {
 	for i := range rwts {
 		if rwts[i].Worktree.Branch == target || rwts[i].Worktree.MatchName() == target {
@@ -6,21 +6,219 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/localfiles"
 )
 
 type Config struct {
-	BaseDir          string       `json:"baseDir,omitempty"`
-	BaseBranch       string       `json:"baseBranch,omitempty"`
-	BranchPrefix     string       `json:"branchPrefix,omitempty"`
-	PostCheckoutHook string       `json:"postCheckoutHook,omitempty"`
-	Setup            []string     `json:"setup,omitempty"`
-	Teardown         []string     `json:"teardown,omitempty"`
-	Defaults         Defaults     `json:"defaults"`
-	Agent            AgentConfig  `json:"agent,omitempty"`
-	Tmux             TmuxConfig   `json:"tmux,omitempty"`
-	Notify           NotifyConfig `json:"notify,omitempty"`
-	Telemetry        *bool        `json:"telemetry,omitempty"`
+	BaseDir          string                   `json:"baseDir,omitempty"`
+	BaseBranch       string                   `json:"baseBranch,omitempty"`
+	BranchPrefix     string                   `json:"branchPrefix,omitempty"`
+	UpstreamRemote   string                   `json:"upstreamRemote,omitempty"`
+	PushRemote       string                   `json:"pushRemote,omitempty"`
+	PostCheckoutHook string                   `json:"postCheckoutHook,omitempty"`
+	Setup            []SetupStep              `json:"setup,omitempty"`
+	Teardown         []string                 `json:"teardown,omitempty"`
+	Copy             []string                 `json:"copy,omitempty"`
+	Symlink          []string                 `json:"symlink,omitempty"`
+	CopyFrom         string                   `json:"copyFrom,omitempty"`
+	Env              map[string]string        `json:"env,omitempty"`
+	Profiles         map[string]ProfileConfig `json:"profiles,omitempty"`
+	Defaults         Defaults                 `json:"defaults"`
+	Agent            AgentConfig              `json:"agent,omitempty"`
+	Tmux             TmuxConfig               `json:"tmux,omitempty"`
+	Notify           NotifyConfig             `json:"notify,omitempty"`
+	Clone            CloneConfig              `json:"clone,omitempty"`
+	GC               GCConfig                 `json:"gc,omitempty"`
+	Ports            PortsConfig              `json:"ports,omitempty"`
+	Telemetry        *bool                    `json:"telemetry,omitempty"`
+
+	// Profile is the name of the profile applied by WithProfile.
+	Profile string `json:"-"`
+}
+
+// ProfileConfig is a named worktree template selected with ww new
+// --profile. Set fields replace the top-level ones; env is merged over the
+// top-level env.
+type ProfileConfig struct {
+	BranchPrefix string            `json:"branchPrefix,omitempty"`
+	Setup        []SetupStep       `json:"setup,omitempty"`
+	Teardown     []string          `json:"teardown,omitempty"`
+	Copy         []string          `json:"copy,omitempty"`
+	Symlink      []string          `json:"symlink,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	Agent        string            `json:"agent,omitempty"`
+	Tmux         ProfileTmuxConfig `json:"tmux,omitempty"`
+}
+
+type ProfileTmuxConfig struct {
+	Layout []string     `json:"layout,omitempty"`
+	Panes  []PaneConfig `json:"panes,omitempty"`
+}
+
+// WithProfile returns a copy of c with the named profile applied. An empty
+// name returns c unchanged.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		names := c.ProfileNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile %q: no profiles are configured", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	out := *c
+	out.Profile = name
+	if p.BranchPrefix != "" {
+		out.BranchPrefix = p.BranchPrefix
+	}
+	if p.Setup != nil {
+		out.Setup = p.Setup
+	}
+	if p.Teardown != nil {
+		out.Teardown = p.Teardown
+	}
+	if p.Copy != nil {
+		out.Copy = p.Copy
+	}
+	if p.Symlink != nil {
+		out.Symlink = p.Symlink
+	}
+	if len(p.Env) > 0 {
+		out.Env = make(map[string]string, len(c.Env)+len(p.Env))
+		for k, v := range c.Env {
+			out.Env[k] = v
+		}
+		for k, v := range p.Env {
+			out.Env[k] = v
+		}
+	}
+	if p.Agent != "" {
+		out.Agent.Default = p.Agent
+	}
+	if p.Tmux.Layout != nil {
+		out.Tmux.Layout = p.Tmux.Layout
+	}
+	if p.Tmux.Panes != nil {
+		out.Tmux.Panes = p.Tmux.Panes
+	}
+	return &out, nil
+}
+
+// ProfileNames returns the configured profile names, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnvList returns Env as sorted KEY=VALUE pairs.
+func (c *Config) EnvList() []string {
+	env := make([]string, 0, len(c.Env))
+	for k, v := range c.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// CloneConfig records how a repo was cloned so later fetches and new
+// worktrees keep the same shape. ww clone writes it to the repo's
+// willow.json.
+type CloneConfig struct {
+	// Filter is a partial-clone filter: blob:none (blobless) or tree:0
+	// (treeless).
+	Filter string `json:"filter,omitempty"`
+	// Depth limits fetched history to this many commits per branch.
+	Depth int `json:"depth,omitempty"`
+	// Sparse lists cone-mode sparse-checkout directories applied to every
+	// new worktree.
+	Sparse []string `json:"sparse,omitempty"`
+}
+
+// GCConfig sets retention policies for ww gc. A zero value disables the
+// policy. The trash and session policies are read from the global config
+// only, since the trash and session archive are shared by every repo.
+type GCConfig struct {
+	// InactiveDays flags branch worktrees with no commits, checkouts, or
+	// agent activity for this many days.
+	InactiveDays int `json:"inactiveDays,omitempty"`
+	// DetachedDays flags detached worktrees untouched for this many days.
+	DetachedDays int `json:"detachedDays,omitempty"`
+	// TrashDays removes trash entries older than this many days.
+	TrashDays int `json:"trashDays,omitempty"`
+	// TrashMaxMB caps the trash size, removing the oldest entries first.
+	TrashMaxMB int `json:"trashMaxMB,omitempty"`
+	// SessionDays removes archived agent sessions that ended more than
+	// this many days ago.
+	SessionDays int `json:"sessionDays,omitempty"`
+}
+
+// HasTrashPolicy reports whether gc should keep trash entries that are
+// within the limits instead of emptying the trash.
+func (c GCConfig) HasTrashPolicy() bool {
+	return c.TrashDays > 0 || c.TrashMaxMB > 0
+}
+
+// DefaultPortStart is where port blocks are allocated from when
+// ports.start is unset.
+const DefaultPortStart = 4000
+
+// PortsConfig reserves a block of Count ports for each worktree, handed to
+// its commands as WILLOW_PORT, WILLOW_PORT_1, and so on.
+type PortsConfig struct {
+	Count int `json:"count,omitempty"`
+	Start int `json:"start,omitempty"`
+}
+
+// StartPort returns the first port blocks are allocated from.
+func (c PortsConfig) StartPort() int {
+	if c.Start > 0 {
+		return c.Start
+	}
+	return DefaultPortStart
+}
+
+// DefaultRemote is the remote used when upstreamRemote or pushRemote is
+// unset.
+const DefaultRemote = "origin"
+
+// Upstream returns the remote base branches are resolved from and pull
+// requests target.
+func (c *Config) Upstream() string {
+	if c.UpstreamRemote != "" {
+		return c.UpstreamRemote
+	}
+	return DefaultRemote
+}
+
+// Push returns the remote branches are pushed to. In a fork workflow this
+// is the fork while Upstream is the canonical repo.
+func (c *Config) Push() string {
+	if c.PushRemote != "" {
+		return c.PushRemote
+	}
+	return DefaultRemote
+}
+
+// Partial-clone filters accepted in clone.filter.
+const (
+	FilterBlobless = "blob:none"
+	FilterTreeless = "tree:0"
+)
+
+// IsZero reports whether c describes a plain full clone.
+func (c CloneConfig) IsZero() bool {
+	return c.Filter == "" && c.Depth == 0 && len(c.Sparse) == 0
 }
 
 type AgentConfig struct {
@@ -28,16 +226,63 @@ type AgentConfig struct {
 	Harnesses map[string]AgentHarnessConfig `json:"harnesses,omitempty"`
 }
 
+// AgentHarnessConfig overrides a built-in harness's launch command, or — when
+// keyed by an ID that is not built in and Command is set — declares a custom
+// command-driven harness.
 type AgentHarnessConfig struct {
-	Command  string   `json:"command,omitempty"`
-	Args     []string `json:"args,omitempty"`
-	YoloArgs []string `json:"yoloArgs,omitempty"`
+	Command        string             `json:"command,omitempty"`
+	Args           []string           `json:"args,omitempty"`
+	YoloArgs       []string           `json:"yoloArgs,omitempty"`
+	DisplayName    string             `json:"displayName,omitempty"`
+	PromptPosition string             `json:"promptPosition,omitempty"`
+	PromptFlag     string             `json:"promptFlag,omitempty"`
+	Hooks          *HarnessHookConfig `json:"hooks,omitempty"`
+	// ApproveKeys and DenyKeys are tmux send-keys arguments that answer the
+	// harness's permission prompt for ww approve and ww deny.
+	ApproveKeys []string `json:"approveKeys,omitempty"`
+	DenyKeys    []string `json:"denyKeys,omitempty"`
+}
+
+// HarnessHookConfig describes how a custom harness reports hook events.
+// Events maps harness event names to BUSY, WAIT, DONE, or END; Fields holds
+// dot-separated JSON paths into the hook payload.
+type HarnessHookConfig struct {
+	SettingsFile string              `json:"settingsFile,omitempty"`
+	Format       string              `json:"format,omitempty"`
+	Events       map[string]string   `json:"events,omitempty"`
+	ToolEvents   []string            `json:"toolEvents,omitempty"`
+	Fields       HarnessFieldsConfig `json:"fields,omitempty"`
+	// TranscriptFormat names the built-in transcript parser (claude or
+	// codex) used to read token usage from fields.transcript.
+	TranscriptFormat string `json:"transcriptFormat,omitempty"`
+}
+
+type HarnessFieldsConfig struct {
+	SessionID  string `json:"sessionId,omitempty"`
+	Event      string `json:"event,omitempty"`
+	Tool       string `json:"tool,omitempty"`
+	Model      string `json:"model,omitempty"`
+	FilePath   string `json:"filePath,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
 }
 
 type NotifyConfig struct {
-	Desktop *bool  `json:"desktop,omitempty"`
-	Sound   *bool  `json:"sound,omitempty"`
-	Command string `json:"command,omitempty"`
+	Desktop *bool              `json:"desktop,omitempty"`
+	Sound   *bool              `json:"sound,omitempty"`
+	Command string             `json:"command,omitempty"`
+	Sinks   []NotifySinkConfig `json:"sinks,omitempty"`
+}
+
+// NotifySinkConfig describes an HTTP notification target. Type is webhook
+// (JSON payload), slack (incoming webhook), or ntfy (topic URL). Statuses
+// filters which target statuses are delivered; empty means DONE and WAIT.
+type NotifySinkConfig struct {
+	Name     string            `json:"name,omitempty"`
+	Type     string            `json:"type"`
+	URL      string            `json:"url"`
+	Statuses []string          `json:"statuses,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Retries  *int              `json:"retries,omitempty"`
 }
 
 type TmuxConfig struct {
@@ -50,13 +295,60 @@ type TmuxConfig struct {
 	Panes             []PaneConfig `json:"panes,omitempty"`
 }
 
+// SetupStep is one setup command. In JSON it is either a command string or
+// an object. Consecutive steps sharing a Group run in parallel. A step with
+// Inputs is skipped when the files they match are unchanged since its last
+// successful run in the worktree.
+type SetupStep struct {
+	Run    string   `json:"run"`
+	Group  string   `json:"group,omitempty"`
+	Inputs []string `json:"inputs,omitempty"`
+}
+
+// SetupCommands turns plain commands into setup steps.
+func SetupCommands(commands ...string) []SetupStep {
+	steps := make([]SetupStep, len(commands))
+	for i, c := range commands {
+		steps[i] = SetupStep{Run: c}
+	}
+	return steps
+}
+
+func (s *SetupStep) UnmarshalJSON(data []byte) error {
+	var run string
+	if err := json.Unmarshal(data, &run); err == nil {
+		*s = SetupStep{Run: run}
+		return nil
+	}
+	type plain SetupStep
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// MarshalJSON writes steps without a group or inputs as plain strings.
+func (s SetupStep) MarshalJSON() ([]byte, error) {
+	if s.Group == "" && len(s.Inputs) == 0 {
+		return json.Marshal(s.Run)
+	}
+	type plain SetupStep
+	return json.Marshal(plain(s))
+}
+
+func (s SetupStep) String() string {
+	if s.Group == "" {
+		return s.Run
+	}
+	return fmt.Sprintf("[%s] %s", s.Group, s.Run)
+}
+
 type PaneConfig struct {
 	Command string `json:"command,omitempty"`
 }
 
 type Defaults struct {
-	Fetch           *bool `json:"fetch,omitempty"`
-	AutoSetupRemote *bool `json:"autoSetupRemote,omitempty"`
+	Fetch             *bool `json:"fetch,omitempty"`
+	AutoSetupRemote   *bool `json:"autoSetupRemote,omitempty"`
+	RetargetPRs       *bool `json:"retargetPRs,omitempty"`
+	UpdateStackTables *bool `json:"updateStackTables,omitempty"`
 }
 
 func BoolPtr(v bool) *bool { return &v }
@@ -64,8 +356,10 @@ func BoolPtr(v bool) *bool { return &v }
 func DefaultConfig() *Config {
 	return &Config{
 		Defaults: Defaults{
-			Fetch:           BoolPtr(true),
-			AutoSetupRemote: BoolPtr(true),
+			Fetch:             BoolPtr(true),
+			AutoSetupRemote:   BoolPtr(true),
+			RetargetPRs:       BoolPtr(false),
+			UpdateStackTables: BoolPtr(false),
 		},
 		Agent: AgentConfig{
 			Default: "claude",
@@ -264,6 +558,12 @@ func merge(base, overlay *Config) {
 	if overlay.BranchPrefix != "" {
 		base.BranchPrefix = overlay.BranchPrefix
 	}
+	if overlay.UpstreamRemote != "" {
+		base.UpstreamRemote = overlay.UpstreamRemote
+	}
+	if overlay.PushRemote != "" {
+		base.PushRemote = overlay.PushRemote
+	}
 	if overlay.PostCheckoutHook != "" {
 		base.PostCheckoutHook = overlay.PostCheckoutHook
 	}
@@ -273,12 +573,43 @@ func merge(base, overlay *Config) {
 	if overlay.Teardown != nil {
 		base.Teardown = overlay.Teardown
 	}
+	if overlay.Copy != nil {
+		base.Copy = overlay.Copy
+	}
+	if overlay.Symlink != nil {
+		base.Symlink = overlay.Symlink
+	}
+	if overlay.CopyFrom != "" {
+		base.CopyFrom = overlay.CopyFrom
+	}
+	if overlay.Env != nil {
+		if base.Env == nil {
+			base.Env = make(map[string]string)
+		}
+		for k, v := range overlay.Env {
+			base.Env[k] = v
+		}
+	}
+	if overlay.Profiles != nil {
+		if base.Profiles == nil {
+			base.Profiles = make(map[string]ProfileConfig)
+		}
+		for name, p := range overlay.Profiles {
+			base.Profiles[name] = p
+		}
+	}
 	if overlay.Defaults.Fetch != nil {
 		base.Defaults.Fetch = overlay.Defaults.Fetch
 	}
 	if overlay.Defaults.AutoSetupRemote != nil {
 		base.Defaults.AutoSetupRemote = overlay.Defaults.AutoSetupRemote
 	}
+	if overlay.Defaults.RetargetPRs != nil {
+		base.Defaults.RetargetPRs = overlay.Defaults.RetargetPRs
+	}
+	if overlay.Defaults.UpdateStackTables != nil {
+		base.Defaults.UpdateStackTables = overlay.Defaults.UpdateStackTables
+	}
 	if overlay.Agent.Default != "" {
 		base.Agent.Default = overlay.Agent.Default
 	}
@@ -297,6 +628,24 @@ func merge(base, overlay *Config) {
 			if h.YoloArgs != nil {
 				current.YoloArgs = h.YoloArgs
 			}
+			if h.DisplayName != "" {
+				current.DisplayName = h.DisplayName
+			}
+			if h.PromptPosition != "" {
+				current.PromptPosition = h.PromptPosition
+			}
+			if h.PromptFlag != "" {
+				current.PromptFlag = h.PromptFlag
+			}
+			if h.Hooks != nil {
+				current.Hooks = h.Hooks
+			}
+			if h.ApproveKeys != nil {
+				current.ApproveKeys = h.ApproveKeys
+			}
+			if h.DenyKeys != nil {
+				current.DenyKeys = h.DenyKeys
+			}
 			base.Agent.Harnesses[id] = current
 		}
 	}
@@ -321,6 +670,36 @@ func merge(base, overlay *Config) {
 	if overlay.Tmux.Panes != nil {
 		base.Tmux.Panes = overlay.Tmux.Panes
 	}
+	if overlay.Clone.Filter != "" {
+		base.Clone.Filter = overlay.Clone.Filter
+	}
+	if overlay.Clone.Depth != 0 {
+		base.Clone.Depth = overlay.Clone.Depth
+	}
+	if overlay.Clone.Sparse != nil {
+		base.Clone.Sparse = overlay.Clone.Sparse
+	}
+	if overlay.GC.InactiveDays != 0 {
+		base.GC.InactiveDays = overlay.GC.InactiveDays
+	}
+	if overlay.GC.DetachedDays != 0 {
+		base.GC.DetachedDays = overlay.GC.DetachedDays
+	}
+	if overlay.GC.TrashDays != 0 {
+		base.GC.TrashDays = overlay.GC.TrashDays
+	}
+	if overlay.GC.TrashMaxMB != 0 {
+		base.GC.TrashMaxMB = overlay.GC.TrashMaxMB
+	}
+	if overlay.GC.SessionDays != 0 {
+		base.GC.SessionDays = overlay.GC.SessionDays
+	}
+	if overlay.Ports.Count != 0 {
+		base.Ports.Count = overlay.Ports.Count
+	}
+	if overlay.Ports.Start != 0 {
+		base.Ports.Start = overlay.Ports.Start
+	}
 	if overlay.Telemetry != nil {
 		base.Telemetry = overlay.Telemetry
 	}
@@ -333,6 +712,9 @@ func merge(base, overlay *Config) {
 	if overlay.Notify.Command != "" {
 		base.Notify.Command = overlay.Notify.Command
 	}
+	if overlay.Notify.Sinks != nil {
+		base.Notify.Sinks = overlay.Notify.Sinks
+	}
 }
 
 // mergeLocal overlays repo-local settings onto base. Some fields, like BaseDir,
@@ -343,6 +725,36 @@ func mergeLocal(base, overlay *Config) {
 	merge(base, &overlayCopy)
 }
 
+// HookEventStatus returns the status a custom harness hook event maps to,
+// uppercased, or false when it is not one of BUSY, WAIT, DONE, IDLE, END.
+func HookEventStatus(status string) (string, bool) {
+	switch upper := strings.ToUpper(status); upper {
+	case "BUSY", "WAIT", "DONE", "IDLE", "END":
+		return upper, true
+	}
+	return "", false
+}
+
+// HookEventWarnings reports hook events mapped to a status that
+// HookEventStatus rejects. Hooks for those events are ignored.
+func (h AgentHarnessConfig) HookEventWarnings(id string) []string {
+	if h.Hooks == nil {
+		return nil
+	}
+	events := make([]string, 0, len(h.Hooks.Events))
+	for event := range h.Hooks.Events {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	var warnings []string
+	for _, event := range events {
+		if _, ok := HookEventStatus(h.Hooks.Events[event]); !ok {
+			warnings = append(warnings, fmt.Sprintf("agent.harnesses.%s.hooks.events.%s %q is not one of BUSY, WAIT, DONE, IDLE, END", id, event, h.Hooks.Events[event]))
+		}
+	}
+	return warnings
+}
+
 // Validate checks for common config issues and returns warnings.
 // Returns nil if config is valid.
 func (cfg *Config) Validate() []string {
@@ -366,6 +778,115 @@ func (cfg *Config) Validate() []string {
 		}
 	}
 
+	ids := make([]string, 0, len(cfg.Agent.Harnesses))
+	for id := range cfg.Agent.Harnesses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		h := cfg.Agent.Harnesses[id]
+		switch h.PromptPosition {
+		case "", "first", "last":
+		default:
+			warnings = append(warnings, fmt.Sprintf("agent.harnesses.%s.promptPosition %q is not one of first, last", id, h.PromptPosition))
+		}
+		if h.Hooks == nil {
+			continue
+		}
+		switch h.Hooks.Format {
+		case "", "nested", "flat":
+		default:
+			warnings = append(warnings, fmt.Sprintf("agent.harnesses.%s.hooks.format %q is not one of nested, flat", id, h.Hooks.Format))
+		}
+		switch h.Hooks.TranscriptFormat {
+		case "", "claude", "codex":
+		default:
+			warnings = append(warnings, fmt.Sprintf("agent.harnesses.%s.hooks.transcriptFormat %q is not one of claude, codex", id, h.Hooks.TranscriptFormat))
+		}
+		warnings = append(warnings, h.HookEventWarnings(id)...)
+	}
+
+	for i, step := range cfg.Setup {
+		if step.Run == "" {
+			warnings = append(warnings, fmt.Sprintf("setup[%d] has no run command", i))
+		}
+		for _, pattern := range step.Inputs {
+			if err := localfiles.ValidatePattern(pattern); err != nil {
+				warnings = append(warnings, fmt.Sprintf("setup[%d].inputs: %v", i, err))
+			}
+		}
+	}
+
+	for _, list := range []struct {
+		name     string
+		patterns []string
+	}{{"copy", cfg.Copy}, {"symlink", cfg.Symlink}} {
+		for _, pattern := range list.patterns {
+			if err := localfiles.ValidatePattern(pattern); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", list.name, err))
+			}
+		}
+	}
+
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		if len(p.Tmux.Panes) > 0 && len(p.Tmux.Layout) == 0 && len(cfg.Tmux.Layout) == 0 {
+			warnings = append(warnings, fmt.Sprintf("profiles.%s.tmux.panes configured but no tmux.layout is set — only pane 0 will receive commands", name))
+		}
+	}
+
+	switch cfg.Clone.Filter {
+	case "", FilterBlobless, FilterTreeless:
+	default:
+		warnings = append(warnings, fmt.Sprintf("clone.filter %q is not one of blob:none, tree:0", cfg.Clone.Filter))
+	}
+	if cfg.Clone.Depth < 0 {
+		warnings = append(warnings, "clone.depth must not be negative")
+	}
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"gc.inactiveDays", cfg.GC.InactiveDays},
+		{"gc.detachedDays", cfg.GC.DetachedDays},
+		{"gc.trashDays", cfg.GC.TrashDays},
+		{"gc.trashMaxMB", cfg.GC.TrashMaxMB},
+		{"gc.sessionDays", cfg.GC.SessionDays},
+		{"ports.count", cfg.Ports.Count},
+	} {
+		if f.value < 0 {
+			warnings = append(warnings, f.name+" must not be negative")
+		}
+	}
+	if cfg.Ports.Start < 0 || cfg.Ports.Start > 65535 {
+		warnings = append(warnings, fmt.Sprintf("ports.start %d is not a valid port", cfg.Ports.Start))
+	}
+
+	for i, sink := range cfg.Notify.Sinks {
+		label := fmt.Sprintf("notify.sinks[%d]", i)
+		if sink.Name != "" {
+			label = fmt.Sprintf("notify.sinks[%s]", sink.Name)
+		}
+		switch sink.Type {
+		case "webhook", "slack", "ntfy":
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s.type %q is not one of webhook, slack, ntfy", label, sink.Type))
+		}
+		if sink.URL == "" {
+			warnings = append(warnings, fmt.Sprintf("%s.url is empty", label))
+		}
+		for _, status := range sink.Statuses {
+			switch strings.ToUpper(status) {
+			case "BUSY", "WAIT", "DONE", "IDLE":
+			default:
+				warnings = append(warnings, fmt.Sprintf("%s.statuses %q is not one of BUSY, WAIT, DONE, IDLE", label, status))
+			}
+		}
+		if sink.Retries != nil && *sink.Retries < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s.retries must not be negative", label))
+		}
+	}
+
 	return warnings
 }
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -62,20 +63,20 @@ func TestMerge_EmptyStringDoesNotOverride(t *testing.T) {
 }
 
 func TestMerge_SliceOverride(t *testing.T) {
-	base := &Config{Setup: []string{"npm install"}}
-	overlay := &Config{Setup: []string{"yarn install", "yarn build"}}
+	base := &Config{Setup: SetupCommands("npm install")}
+	overlay := &Config{Setup: SetupCommands("yarn install", "yarn build")}
 
 	merge(base, overlay)
 
-	if len(base.Setup) != 2 || base.Setup[0] != "yarn install" {
+	if len(base.Setup) != 2 || base.Setup[0].Run != "yarn install" {
 		t.Errorf("Setup = %v, want [yarn install, yarn build]", base.Setup)
 	}
 }
 
 func TestMerge_EmptySliceOverridesNil(t *testing.T) {
-	base := &Config{Setup: []string{"npm install"}}
+	base := &Config{Setup: SetupCommands("npm install")}
 	// Explicit empty slice means "clear the setup commands"
-	overlay := &Config{Setup: []string{}}
+	overlay := &Config{Setup: []SetupStep{}}
 
 	merge(base, overlay)
 
@@ -85,12 +86,12 @@ func TestMerge_EmptySliceOverridesNil(t *testing.T) {
 }
 
 func TestMerge_NilSliceDoesNotOverride(t *testing.T) {
-	base := &Config{Setup: []string{"npm install"}}
+	base := &Config{Setup: SetupCommands("npm install")}
 	overlay := &Config{} // Setup is nil
 
 	merge(base, overlay)
 
-	if len(base.Setup) != 1 || base.Setup[0] != "npm install" {
+	if len(base.Setup) != 1 || base.Setup[0].Run != "npm install" {
 		t.Errorf("Setup = %v, want [npm install]", base.Setup)
 	}
 }
@@ -129,6 +130,35 @@ func TestMerge_AgentConfig(t *testing.T) {
 	}
 }
 
+func TestMerge_CustomHarness(t *testing.T) {
+	base := DefaultConfig()
+	overlay := &Config{
+		Agent: AgentConfig{
+			Harnesses: map[string]AgentHarnessConfig{
+				"aider": {
+					Command:        "aider",
+					DisplayName:    "Aider",
+					PromptPosition: "last",
+					PromptFlag:     "--message",
+					Hooks: &HarnessHookConfig{
+						Events: map[string]string{"start": "BUSY", "stop": "DONE"},
+					},
+				},
+			},
+		},
+	}
+
+	merge(base, overlay)
+
+	got := base.Agent.Harnesses["aider"]
+	if got.DisplayName != "Aider" || got.PromptPosition != "last" || got.PromptFlag != "--message" {
+		t.Errorf("aider = %+v, want custom fields merged", got)
+	}
+	if got.Hooks == nil || got.Hooks.Events["stop"] != "DONE" {
+		t.Errorf("aider hooks = %+v, want events merged", got.Hooks)
+	}
+}
+
 func TestMerge_BoolPointerOverride(t *testing.T) {
 	base := DefaultConfig() // fetch=true, autoSetupRemote=true
 	overlay := &Config{
@@ -143,6 +173,22 @@ func TestMerge_BoolPointerOverride(t *testing.T) {
 	if *base.Defaults.AutoSetupRemote != true {
 		t.Error("Defaults.AutoSetupRemote should remain true (not overridden)")
 	}
+	if *base.Defaults.RetargetPRs != false {
+		t.Error("Defaults.RetargetPRs should default to false")
+	}
+
+	merge(base, &Config{Defaults: Defaults{RetargetPRs: BoolPtr(true)}})
+	if *base.Defaults.RetargetPRs != true {
+		t.Error("Defaults.RetargetPRs should be true after override")
+	}
+	if *base.Defaults.UpdateStackTables != false {
+		t.Error("Defaults.UpdateStackTables should default to false")
+	}
+
+	merge(base, &Config{Defaults: Defaults{UpdateStackTables: BoolPtr(true)}})
+	if *base.Defaults.UpdateStackTables != true {
+		t.Error("Defaults.UpdateStackTables should be true after override")
+	}
 }
 
 func TestMerge_TmuxSwitcherPreviewFalseOverride(t *testing.T) {
@@ -219,7 +265,7 @@ func TestSaveAndLoad(t *testing.T) {
 	cfg := &Config{
 		BaseBranch:   "main",
 		BranchPrefix: "raj",
-		Setup:        []string{"npm install"},
+		Setup:        SetupCommands("npm install"),
 		Defaults: Defaults{
 			Fetch:           BoolPtr(false),
 			AutoSetupRemote: BoolPtr(true),
@@ -241,7 +287,7 @@ func TestSaveAndLoad(t *testing.T) {
 	if loaded.BranchPrefix != "raj" {
 		t.Errorf("BranchPrefix = %q, want %q", loaded.BranchPrefix, "raj")
 	}
-	if len(loaded.Setup) != 1 || loaded.Setup[0] != "npm install" {
+	if len(loaded.Setup) != 1 || loaded.Setup[0].Run != "npm install" {
 		t.Errorf("Setup = %v, want [npm install]", loaded.Setup)
 	}
 	if *loaded.Defaults.Fetch != false {
@@ -287,7 +333,7 @@ func TestLoad_TwoTierMerge(t *testing.T) {
 	if cfg.BaseBranch != "develop" {
 		t.Errorf("BaseBranch = %q, want %q", cfg.BaseBranch, "develop")
 	}
-	if len(cfg.Setup) != 1 || cfg.Setup[0] != "npm install" {
+	if len(cfg.Setup) != 1 || cfg.Setup[0].Run != "npm install" {
 		t.Errorf("Setup = %v, want [npm install]", cfg.Setup)
 	}
 	if *cfg.Defaults.Fetch != false {
@@ -791,6 +837,34 @@ func TestValidate_NoPanes(t *testing.T) {
 	}
 }
 
+func TestValidate_CustomHarness(t *testing.T) {
+	cfg := &Config{
+		Agent: AgentConfig{
+			Harnesses: map[string]AgentHarnessConfig{
+				"aider": {
+					Command:        "aider",
+					PromptPosition: "middle",
+					Hooks: &HarnessHookConfig{
+						Format:           "yaml",
+						Events:           map[string]string{"start": "busy", "pause": "idle", "stop": "FINISHED"},
+						TranscriptFormat: "aider",
+					},
+				},
+			},
+		},
+	}
+
+	warnings := cfg.Validate()
+	if len(warnings) != 4 {
+		t.Fatalf("expected 4 warnings, got %d: %v", len(warnings), warnings)
+	}
+	for i, want := range []string{"promptPosition", "hooks.format", "hooks.transcriptFormat", "hooks.events.stop"} {
+		if !strings.Contains(warnings[i], want) {
+			t.Errorf("warnings[%d] = %q, want mention of %s", i, warnings[i], want)
+		}
+	}
+}
+
 func TestValidate_SelectLayoutNotCounted(t *testing.T) {
 	cfg := &Config{
 		Tmux: TmuxConfig{
@@ -815,3 +889,208 @@ func TestMerge_NotifyWaitCommand_EmptyDoesNotOverride(t *testing.T) {
 		t.Errorf("NotifyWaitCommand = %q, want %q", base.Tmux.NotifyWaitCommand, "original")
 	}
 }
+
+func TestMerge_NotifySinks(t *testing.T) {
+	base := &Config{Notify: NotifyConfig{Sinks: []NotifySinkConfig{{Type: "slack", URL: "https://hooks.slack.com/a"}}}}
+	merge(base, &Config{})
+	if len(base.Notify.Sinks) != 1 {
+		t.Fatalf("nil sinks should not override, got %v", base.Notify.Sinks)
+	}
+
+	merge(base, &Config{Notify: NotifyConfig{Sinks: []NotifySinkConfig{{Type: "ntfy", URL: "https://ntfy.sh/willow"}}}})
+	if len(base.Notify.Sinks) != 1 || base.Notify.Sinks[0].Type != "ntfy" {
+		t.Errorf("Sinks = %v, want overlay's ntfy sink", base.Notify.Sinks)
+	}
+}
+
+func TestValidate_NotifySinks(t *testing.T) {
+	cfg := &Config{Notify: NotifyConfig{Sinks: []NotifySinkConfig{
+		{Type: "webhook", URL: "http://localhost:9000/hook", Statuses: []string{"done", "WAIT"}},
+		{Name: "pager", Type: "pagerduty", Statuses: []string{"FINISHED"}},
+	}}}
+
+	warnings := cfg.Validate()
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %d: %v", len(warnings), warnings)
+	}
+	for i, want := range []string{"notify.sinks[pager].type", "notify.sinks[pager].url", "notify.sinks[pager].statuses"} {
+		if !strings.Contains(warnings[i], want) {
+			t.Errorf("warnings[%d] = %q, want mention of %s", i, warnings[i], want)
+		}
+	}
+}
+
+func TestMerge_CloneConfig(t *testing.T) {
+	base := &Config{Clone: CloneConfig{Filter: FilterBlobless, Depth: 10}}
+	overlay := &Config{Clone: CloneConfig{Depth: 1, Sparse: []string{"app"}}}
+
+	merge(base, overlay)
+
+	if base.Clone.Filter != FilterBlobless || base.Clone.Depth != 1 || len(base.Clone.Sparse) != 1 {
+		t.Errorf("Clone = %+v, want blob:none filter, depth 1, sparse [app]", base.Clone)
+	}
+}
+
+func TestValidate_CloneConfig(t *testing.T) {
+	cfg := &Config{Clone: CloneConfig{Filter: "blob:limit=1m", Depth: -1}}
+	warnings := cfg.Validate()
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "clone.filter") || !strings.Contains(warnings[1], "clone.depth") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestMerge_GCConfig(t *testing.T) {
+	base := &Config{GC: GCConfig{InactiveDays: 30, TrashDays: 7}}
+	overlay := &Config{GC: GCConfig{InactiveDays: 14, TrashMaxMB: 500}}
+
+	merge(base, overlay)
+
+	want := GCConfig{InactiveDays: 14, TrashDays: 7, TrashMaxMB: 500}
+	if base.GC != want {
+		t.Errorf("GC = %+v, want %+v", base.GC, want)
+	}
+}
+
+func TestValidate_GCConfig(t *testing.T) {
+	cfg := &Config{GC: GCConfig{InactiveDays: -1, TrashMaxMB: -5}}
+	warnings := cfg.Validate()
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "gc.inactiveDays") || !strings.Contains(warnings[1], "gc.trashMaxMB") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	cfg := &Config{
+		BranchPrefix: "alice",
+		Setup:        SetupCommands("npm install"),
+		Env:          map[string]string{"A": "1", "B": "2"},
+		Profiles: map[string]ProfileConfig{
+			"review": {
+				BranchPrefix: "review",
+				Setup:        SetupCommands("make deps"),
+				Env:          map[string]string{"B": "3"},
+				Agent:        "codex",
+			},
+		},
+	}
+
+	got, err := cfg.WithProfile("review")
+	if err != nil {
+		t.Fatalf("WithProfile: %v", err)
+	}
+	if got.Profile != "review" || got.BranchPrefix != "review" || got.Agent.Default != "codex" {
+		t.Errorf("profile not applied: %+v", got)
+	}
+	if len(got.Setup) != 1 || got.Setup[0].Run != "make deps" {
+		t.Errorf("Setup = %v, want [make deps]", got.Setup)
+	}
+	if env := got.EnvList(); strings.Join(env, " ") != "A=1 B=3" {
+		t.Errorf("EnvList = %v, want [A=1 B=3]", env)
+	}
+	if cfg.Env["B"] != "2" || cfg.Profile != "" {
+		t.Error("WithProfile modified the original config")
+	}
+
+	if same, err := cfg.WithProfile(""); err != nil || same != cfg {
+		t.Errorf("WithProfile(\"\") = %p, %v; want the original config", same, err)
+	}
+	if _, err := cfg.WithProfile("nope"); err == nil || !strings.Contains(err.Error(), "available: review") {
+		t.Errorf("WithProfile(nope) error = %v", err)
+	}
+}
+
+func TestMerge_EnvAndProfiles(t *testing.T) {
+	base := &Config{
+		Env:      map[string]string{"A": "global", "B": "global"},
+		Profiles: map[string]ProfileConfig{"review": {Agent: "claude"}, "frontend": {BranchPrefix: "fe"}},
+	}
+	overlay := &Config{
+		Env:      map[string]string{"B": "local"},
+		Profiles: map[string]ProfileConfig{"review": {Agent: "codex"}},
+	}
+
+	merge(base, overlay)
+
+	if base.Env["A"] != "global" || base.Env["B"] != "local" {
+		t.Errorf("Env = %v", base.Env)
+	}
+	if base.Profiles["review"].Agent != "codex" || base.Profiles["frontend"].BranchPrefix != "fe" {
+		t.Errorf("Profiles = %+v", base.Profiles)
+	}
+}
+
+func TestMerge_LocalFiles(t *testing.T) {
+	base := &Config{Copy: []string{".env"}, Symlink: []string{"node_modules"}, CopyFrom: "main"}
+	overlay := &Config{Copy: []string{".env", ".vscode/settings.json"}}
+
+	merge(base, overlay)
+
+	if len(base.Copy) != 2 || len(base.Symlink) != 1 || base.CopyFrom != "main" {
+		t.Errorf("got copy=%v symlink=%v copyFrom=%q", base.Copy, base.Symlink, base.CopyFrom)
+	}
+}
+
+func TestValidate_LocalFilePatterns(t *testing.T) {
+	cfg := &Config{Copy: []string{".env", "../secrets"}, Symlink: []string{"/abs"}}
+	warnings := cfg.Validate()
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.HasPrefix(warnings[0], "copy:") || !strings.HasPrefix(warnings[1], "symlink:") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestSetupStep_JSON(t *testing.T) {
+	var cfg Config
+	data := `{"setup": ["cp .env.example .env", {"run": "npm ci", "group": "deps", "inputs": ["package-lock.json"]}]}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := []SetupStep{
+		{Run: "cp .env.example .env"},
+		{Run: "npm ci", Group: "deps", Inputs: []string{"package-lock.json"}},
+	}
+	if len(cfg.Setup) != 2 || cfg.Setup[0].Run != want[0].Run || cfg.Setup[1].Group != "deps" || cfg.Setup[1].Inputs[0] != "package-lock.json" {
+		t.Fatalf("Setup = %+v, want %+v", cfg.Setup, want)
+	}
+
+	out, err := json.Marshal(cfg.Setup)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got := string(out); got != `["cp .env.example .env",{"run":"npm ci","group":"deps","inputs":["package-lock.json"]}]` {
+		t.Errorf("Marshal = %s", got)
+	}
+}
+
+func TestValidate_SetupSteps(t *testing.T) {
+	cfg := &Config{Setup: []SetupStep{{Group: "deps"}, {Run: "npm ci", Inputs: []string{"../package-lock.json"}}}}
+	warnings := cfg.Validate()
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "setup[0]") || !strings.Contains(warnings[1], "setup[1].inputs") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestMerge_PortsConfig(t *testing.T) {
+	base := &Config{Ports: PortsConfig{Count: 2}}
+	overlay := &Config{Ports: PortsConfig{Start: 5000}}
+
+	merge(base, overlay)
+
+	if base.Ports != (PortsConfig{Count: 2, Start: 5000}) {
+		t.Errorf("Ports = %+v", base.Ports)
+	}
+	if (PortsConfig{}).StartPort() != DefaultPortStart {
+		t.Errorf("StartPort() = %d, want %d", (PortsConfig{}).StartPort(), DefaultPortStart)
+	}
+}
@@ -14,13 +14,19 @@ import (
 
 	"github.com/iamrajjoshi/willow/internal/agent"
 	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/gh"
 	"github.com/iamrajjoshi/willow/internal/tmux"
 	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
+	"github.com/iamrajjoshi/willow/internal/watcher"
 	"github.com/iamrajjoshi/willow/internal/worktree"
 )
 
 type Config struct {
 	RefreshInterval time.Duration
+	// Switch opens the tmux session for a worktree. Enter is disabled when
+	// it is nil.
+	Switch func(repo, wtDir, path string) error
 }
 
 type row struct {
@@ -34,6 +40,10 @@ type row struct {
 	Unread      bool
 	Merged      bool
 	StackPrefix string
+	// CostUSD is the estimated spend of the worktree's live sessions.
+	CostUSD float64
+	// PR is the branch's pull request, filled in asynchronously.
+	PR *gh.PRInfo
 }
 
 type summary struct {
@@ -41,8 +51,12 @@ type summary struct {
 	Worktrees int
 	Active    int
 	Unread    int
+	CostUSD   float64
 }
 
+// prRefreshInterval spaces out gh lookups for the PR column.
+const prRefreshInterval = time.Minute
+
 func Run(ctx context.Context, cfg Config) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -50,17 +64,32 @@ func Run(ctx context.Context, cfg Config) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	fmt.Print(ui.AltScreenOn())
-	fmt.Print(ui.HideCursor())
-	defer func() {
+	// Without a controlling terminal the dashboard is a passive monitor.
+	tty, _ := openTerminal()
+	var keys <-chan key
+	if tty != nil {
+		keys = tty.keys
+	}
+	enter := func() {
+		fmt.Print(ui.AltScreenOn())
+		fmt.Print(ui.HideCursor())
+	}
+	leave := func() {
 		fmt.Print(ui.ShowCursor())
 		fmt.Print(ui.AltScreenOff())
+	}
+	enter()
+	defer func() {
+		if tty != nil {
+			tty.close()
+		}
+		leave()
 	}()
 
 	winchCh := make(chan os.Signal, 1)
 	signal.Notify(winchCh, syscall.SIGWINCH)
 
-	cols := termWidth()
+	cols, lines := tty.size()
 	interval := cfg.RefreshInterval
 	if interval <= 0 {
 		interval = 2 * time.Second
@@ -68,16 +97,61 @@ func Run(ctx context.Context, cfg Config) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// With a watcher, hook writes redraw immediately and ticks only refresh
+	// git state and stale-status decay; without one, every tick rescans.
+	var w *watcher.Watcher
+	var changes <-chan watcher.Event
+	if wt, err := watcher.New(); err == nil {
+		w = wt
+		defer w.Close()
+		changes = w.Events()
+	}
+	sessionIndex := func() *agent.SessionIndex {
+		if w == nil {
+			return nil
+		}
+		return w.Index()
+	}
+
+	// PR lookups shell out to gh and can take seconds, so they run in the
+	// background and land on the next redraw.
+	prCh := make(chan map[string]*gh.PRInfo, 1)
+	prTicker := time.NewTicker(prRefreshInterval)
+	defer prTicker.Stop()
+	prPending := false
+	refreshPRs := func(rows []row) {
+		if prPending {
+			return
+		}
+		prPending = true
+		rows = append([]row(nil), rows...)
+		go func() { prCh <- fetchPRs(rows) }()
+	}
+
 	frame := 0
 	prevStatus := map[string]agent.Status{}
 	flashUntil := map[string]time.Time{}
 
-	rows, sum := collectData(ctx)
-	updateFlashes(rows, prevStatus, flashUntil)
-	output := render(rows, sum, cols, frame, flashUntil)
-	fmt.Print(ui.CursorHome())
-	fmt.Print(output)
-	fmt.Print(ui.ClearToEnd())
+	st := &state{}
+	draw := func() {
+		out := view(st, sessionIndex(), cols, lines, frame, flashUntil)
+		if tty != nil {
+			// Raw mode turns off newline translation.
+			out = strings.ReplaceAll(out, "\n", "\033[K\r\n")
+		}
+		fmt.Print(ui.CursorHome())
+		fmt.Print(out)
+		fmt.Print(ui.ClearToEnd())
+	}
+	reload := func() {
+		rows, sum := collectData(ctx, sessionIndex())
+		updateFlashes(rows, prevStatus, flashUntil)
+		st.setRows(rows, sum)
+	}
+
+	reload()
+	refreshPRs(st.rows)
+	draw()
 
 	for {
 		select {
@@ -86,19 +160,147 @@ func Run(ctx context.Context, cfg Config) error {
 		case <-sigCh:
 			return nil
 		case <-winchCh:
-			cols = termWidth()
+			cols, lines = tty.size()
+			draw()
+		case prs := <-prCh:
+			prPending = false
+			st.setPRs(prs)
+			draw()
+		case <-prTicker.C:
+			refreshPRs(st.rows)
+		case k, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			switch act := st.handleKey(k); act {
+			case actQuit:
+				return nil
+			case actSwitch:
+				r, ok := st.selected()
+				if !ok {
+					break
+				}
+				if cfg.Switch == nil {
+					st.message = "Switching is unavailable"
+					break
+				}
+				_ = agent.MarkRead(r.Repo, r.WtDirName)
+				if tmux.InTmux() {
+					if err := cfg.Switch(r.Repo, r.WtDirName, r.Path); err != nil {
+						st.message = fmt.Sprintf("Switch failed: %v", err)
+					}
+				} else {
+					// tmux attach takes over the terminal until detach.
+					tty.close()
+					leave()
+					err := cfg.Switch(r.Repo, r.WtDirName, r.Path)
+					enter()
+					keys = nil
+					if tty, _ = openTerminal(); tty != nil {
+						keys = tty.keys
+					}
+					if err != nil {
+						st.message = fmt.Sprintf("Switch failed: %v", err)
+					}
+				}
+				st.setRows(st.rows, refreshStatuses(st.rows, st.sum, sessionIndex()))
+			case actDismiss:
+				r, ok := st.selected()
+				if !ok {
+					break
+				}
+				if !r.Unread {
+					st.message = fmt.Sprintf("%s has nothing to dismiss", r.Branch)
+					break
+				}
+				_ = agent.MarkRead(r.Repo, r.WtDirName)
+				st.setRows(st.rows, refreshStatuses(st.rows, st.sum, sessionIndex()))
+				st.message = fmt.Sprintf("Marked %s as read", r.Branch)
+			case actPrepareRemove:
+				if r, ok := st.selected(); ok {
+					st.askRemove(r, removalWarnings(r))
+				}
+			case actRemove:
+				r := *st.confirm
+				st.confirm, st.confirmNote = nil, ""
+				st.message = fmt.Sprintf("Removing %s...", r.Branch)
+				draw()
+				st.message = removeRow(r)
+				reload()
+			case actApprove, actDeny:
+				r, ok := st.selected()
+				if !ok {
+					break
+				}
+				if r.Status != agent.StatusWait {
+					st.message = fmt.Sprintf("%s is not waiting on a permission prompt", r.Branch)
+					break
+				}
+				st.message = answerRow(r, act == actApprove)
+				st.setRows(st.rows, refreshStatuses(st.rows, st.sum, sessionIndex()))
+			}
+			draw()
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+			drainEvents(changes)
+			sum := refreshStatuses(st.rows, st.sum, w.Index())
+			updateFlashes(st.rows, prevStatus, flashUntil)
+			st.setRows(st.rows, sum)
+			draw()
 		case <-ticker.C:
 			frame++
-			rows, sum = collectData(ctx)
-			updateFlashes(rows, prevStatus, flashUntil)
-			output = render(rows, sum, cols, frame, flashUntil)
-			fmt.Print(ui.CursorHome())
-			fmt.Print(output)
-			fmt.Print(ui.ClearToEnd())
+			reload()
+			draw()
 		}
 	}
 }
 
+// removeRow runs ww rm for r and returns a one-line result for the footer.
+// Any tmux session for the worktree is killed once the removal succeeds.
+func removeRow(r row) string {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Sprintf("Remove failed: %v", err)
+	}
+	out, err := exec.Command(self, rmArgs(r)...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("Remove failed: %s", lastLine(string(out), err))
+	}
+	if sess := tmux.SessionNameForWorktree(r.Repo, r.WtDirName); tmux.SessionExists(sess) {
+		_ = tmux.KillSession(sess)
+	}
+	return fmt.Sprintf("Removed %s", r.Branch)
+}
+
+// answerRow runs ww approve or ww deny for r and returns a one-line result
+// for the footer.
+func answerRow(r row, approve bool) string {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Sprintf("Answer failed: %v", err)
+	}
+	out, err := exec.Command(self, permissionArgs(r, approve)...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("Answer failed: %s", lastLine(string(out), err))
+	}
+	if approve {
+		return fmt.Sprintf("Approved prompt in %s", r.Branch)
+	}
+	return fmt.Sprintf("Denied prompt in %s", r.Branch)
+}
+
+func lastLine(out string, fallback error) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return fallback.Error()
+}
+
 func rowKey(r row) string {
 	return r.Repo + "/" + r.WtDirName
 }
@@ -127,14 +329,50 @@ func updateFlashes(rows []row, prev map[string]agent.Status, flashUntil map[stri
 	}
 }
 
-func collectData(ctx context.Context) ([]row, summary) {
+// drainEvents discards queued watcher events so a burst of hook writes
+// produces a single redraw.
+func drainEvents(ch <-chan watcher.Event) {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// refreshStatuses recomputes agent status and unread state for existing rows
+// from idx, leaving git-derived fields from the last full collection intact.
+func refreshStatuses(rows []row, sum summary, idx *agent.SessionIndex) summary {
+	sum.Active, sum.Unread, sum.CostUSD = 0, 0, 0
+	for i := range rows {
+		r := &rows[i]
+		sessions := idx.ReadAllSessions(r.Repo, r.WtDirName)
+		r.Status = agent.AggregateStatus(sessions).Status
+		r.Unread = r.Status == agent.StatusDone && agent.CountUnreadIn(r.Repo, r.WtDirName, sessions) > 0
+		r.CostUSD = sessionsCost(sessions)
+		sum.CostUSD += r.CostUSD
+		if agent.IsActive(r.Status) {
+			sum.Active++
+		}
+		if r.Unread {
+			sum.Unread++
+		}
+	}
+	return sum
+}
+
+func collectData(ctx context.Context, idx *agent.SessionIndex) ([]row, summary) {
 	repos, err := config.ListRepos()
 	if err != nil {
 		return nil, summary{}
 	}
 
 	sum := summary{Repos: len(repos)}
-	items, err := tmux.BuildPickerItemsWithOptions(ctx, "", tmux.PickerBuildOptions{RefreshGitHubMerged: false})
+	items, err := tmux.BuildPickerItemsWithOptions(ctx, "", tmux.PickerBuildOptions{RefreshGitHubMerged: false, Sessions: idx})
 	if err != nil {
 		return nil, sum
 	}
@@ -152,9 +390,11 @@ func collectData(ctx context.Context) ([]row, summary) {
 			Unread:      item.Unread,
 			Merged:      item.Merged,
 			StackPrefix: item.StackPrefix,
+			CostUSD:     sessionsCost(idx.ReadAllSessions(item.RepoName, item.WtDirName)),
 		}
 		rows = append(rows, r)
 		sum.Worktrees++
+		sum.CostUSD += r.CostUSD
 		if agent.IsActive(item.Status) {
 			sum.Active++
 		}
@@ -166,13 +406,31 @@ func collectData(ctx context.Context) ([]row, summary) {
 	return rows, sum
 }
 
-func render(rows []row, sum summary, width int, frame int, flashUntil map[string]time.Time) string {
+func sessionsCost(sessions []*agent.SessionStatus) float64 {
+	var total float64
+	for _, ss := range sessions {
+		total += ss.CostUSD
+	}
+	return total
+}
+
+func headerStats(sum summary) string {
+	stats := fmt.Sprintf("%d repos | %d worktrees | %d active | %d unread", sum.Repos, sum.Worktrees, sum.Active, sum.Unread)
+	if sum.CostUSD > 0 {
+		stats += " | " + usage.FormatCost(sum.CostUSD)
+	}
+	return stats
+}
+
+// render draws the header and worktree table. selected is the index of the
+// highlighted row, or -1 for none.
+func render(rows []row, sum summary, width int, frame int, flashUntil map[string]time.Time, selected int) string {
 	var b strings.Builder
 	u := &ui.UI{}
 
 	if len(rows) == 0 {
 		title := "willow dashboard"
-		stats := fmt.Sprintf("%d repos | %d worktrees | %d active | %d unread", sum.Repos, sum.Worktrees, sum.Active, sum.Unread)
+		stats := headerStats(sum)
 		headerText := title + "  " + stats
 		pad := 0
 		if width > len(headerText) {
@@ -193,14 +451,30 @@ func render(rows []row, sum summary, width int, frame int, flashUntil map[string
 	home, _ := os.UserHomeDir()
 
 	type labels struct {
-		status string
-		name   string
-		path   string
+		status  string
+		name    string
+		cost    string
+		pr      string
+		prColor string
+		path    string
 	}
 	rowLabels := make([]labels, len(rows))
 	statusW := len("STATUS")
 	nameW := len("WORKTREE")
 	pathW := len("PATH")
+	// The cost column only appears once some session has reported usage.
+	costW := 0
+	if sum.CostUSD > 0 {
+		costW = len("COST")
+	}
+	// Likewise the PR column waits for the first gh lookup to find a PR.
+	prW := 0
+	for _, r := range rows {
+		if r.PR != nil {
+			prW = len("PR")
+			break
+		}
+	}
 
 	for i, r := range rows {
 		statusText := string(r.Status)
@@ -213,7 +487,18 @@ func render(rows []row, sum summary, width int, frame int, flashUntil map[string
 			namePlain += " [merged]"
 		}
 		path := shortenPathWithHome(r.Path, home)
-		rowLabels[i] = labels{status: statusText, name: name, path: path}
+		cost := ""
+		if costW > 0 && r.CostUSD > 0 {
+			cost = usage.FormatCost(r.CostUSD)
+		}
+		prPlain, prColor := prLabel(u, r.PR)
+		rowLabels[i] = labels{status: statusText, name: name, cost: cost, pr: prPlain, prColor: prColor, path: path}
+		if costW > 0 && len(cost) > costW {
+			costW = len(cost)
+		}
+		if prW > 0 && utf8.RuneCountInString(prPlain) > prW {
+			prW = utf8.RuneCountInString(prPlain)
+		}
 
 		if utf8.RuneCountInString(statusText) > statusW {
 			statusW = utf8.RuneCountInString(statusText)
@@ -227,8 +512,14 @@ func render(rows []row, sum summary, width int, frame int, flashUntil map[string
 	}
 
 	tableW := 2 + 2 + 1 + statusW + 2 + nameW + 2 + pathW
+	if costW > 0 {
+		tableW += costW + 2
+	}
+	if prW > 0 {
+		tableW += prW + 2
+	}
 	title := "willow dashboard"
-	stats := fmt.Sprintf("%d repos | %d worktrees | %d active | %d unread", sum.Repos, sum.Worktrees, sum.Active, sum.Unread)
+	stats := headerStats(sum)
 	headerText := title + "  " + stats
 	pad := 0
 	if tableW > len(headerText) {
@@ -238,8 +529,14 @@ func render(rows []row, sum summary, width int, frame int, flashUntil map[string
 	b.WriteString(u.Bold(headerText))
 	b.WriteString("\n\n")
 
-	headerLine := fmt.Sprintf("  %-2s %-*s  %-*s  %-*s",
-		"", statusW, "STATUS", nameW, "WORKTREE", pathW, "PATH")
+	headerLine := fmt.Sprintf("  %-2s %-*s  %-*s  ", "", statusW, "STATUS", nameW, "WORKTREE")
+	if costW > 0 {
+		headerLine += fmt.Sprintf("%*s  ", costW, "COST")
+	}
+	if prW > 0 {
+		headerLine += fmt.Sprintf("%-*s  ", prW, "PR")
+	}
+	headerLine += fmt.Sprintf("%-*s", pathW, "PATH")
 	b.WriteString(u.Bold(headerLine))
 	b.WriteString("\n")
 
@@ -273,8 +570,18 @@ func render(rows []row, sum summary, width int, frame int, flashUntil map[string
 		}
 		nameCol := nameDisplay + strings.Repeat(" ", namePadding)
 
+		if costW > 0 {
+			nameCol += "  " + fmt.Sprintf("%*s", costW, rowLabels[i].cost)
+		}
+		if prW > 0 {
+			nameCol += "  " + rowLabels[i].prColor + strings.Repeat(" ", prW-utf8.RuneCountInString(rowLabels[i].pr))
+		}
 		pathCol := fmt.Sprintf("%-*s", pathW, rowLabels[i].path)
-		line := fmt.Sprintf("  %s %s  %s  %s", icon, statusCol, nameCol, u.Dim(pathCol))
+		marker := "  "
+		if i == selected {
+			marker = u.Cyan("\u25b8") + " "
+		}
+		line := fmt.Sprintf("%s%s %s  %s  %s", marker, icon, statusCol, nameCol, u.Dim(pathCol))
 
 		if until, ok := flashUntil[rowKey(r)]; ok && now.Before(until) {
 			b.WriteString("\033[48;5;30m")
@@ -12,11 +12,12 @@ import (
 
 	"github.com/iamrajjoshi/willow/internal/agent"
 	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/gh"
 	"github.com/iamrajjoshi/willow/internal/stack"
 )
 
 func TestRenderNoWorktrees(t *testing.T) {
-	out := render(nil, summary{Repos: 2, Worktrees: 0, Active: 0, Unread: 0}, 120, 0, nil)
+	out := render(nil, summary{Repos: 2, Worktrees: 0, Active: 0, Unread: 0}, 120, 0, nil, -1)
 	if !strings.Contains(out, "no worktrees yet") {
 		t.Errorf("expected 'no worktrees yet' empty-state copy, got:\n%s", out)
 	}
@@ -35,7 +36,7 @@ func TestRenderWorktreeRows(t *testing.T) {
 			WtDirName: "feat--thing",
 		},
 	}
-	out := render(rows, summary{Repos: 1, Worktrees: 1, Active: 1, Unread: 0}, 120, 0, nil)
+	out := render(rows, summary{Repos: 1, Worktrees: 1, Active: 1, Unread: 0}, 120, 0, nil, -1)
 
 	for _, want := range []string{"WORKTREE", "PATH", "myrepo", "feat--thing", "/tmp/worktrees/myrepo/feat--thing"} {
 		if !strings.Contains(out, want) {
@@ -49,6 +50,25 @@ func TestRenderWorktreeRows(t *testing.T) {
 	}
 }
 
+func TestRenderCostColumnOnlyWithUsage(t *testing.T) {
+	rows := []row{
+		{Repo: "myrepo", Branch: "feat--a", Status: agent.StatusDone, Path: "/tmp/a", WtDirName: "feat--a", CostUSD: 1.25},
+		{Repo: "myrepo", Branch: "feat--b", Status: agent.StatusIdle, Path: "/tmp/b", WtDirName: "feat--b"},
+	}
+	out := render(rows, summary{Repos: 1, Worktrees: 2, CostUSD: 1.25}, 120, 0, nil, -1)
+	for _, want := range []string{"COST", "$1.25", "| $1.25"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	rows[0].CostUSD = 0
+	out = render(rows, summary{Repos: 1, Worktrees: 2}, 120, 0, nil, -1)
+	if strings.Contains(out, "COST") {
+		t.Errorf("cost column should be hidden without usage, got:\n%s", out)
+	}
+}
+
 func TestRenderUnreadMarker(t *testing.T) {
 	rows := []row{
 		{
@@ -60,7 +80,7 @@ func TestRenderUnreadMarker(t *testing.T) {
 			WtDirName: "feat--done",
 		},
 	}
-	out := render(rows, summary{Repos: 1, Worktrees: 1, Active: 1, Unread: 1}, 120, 0, nil)
+	out := render(rows, summary{Repos: 1, Worktrees: 1, Active: 1, Unread: 1}, 120, 0, nil, -1)
 
 	if !strings.Contains(out, "DONE \u25cf") {
 		t.Errorf("expected spaced unread marker in output, got:\n%s", out)
@@ -86,7 +106,7 @@ func TestRenderStackPrefixAndMergedMarker(t *testing.T) {
 			StackPrefix: "\u2514\u2500 ",
 		},
 	}
-	out := render(rows, summary{Repos: 1, Worktrees: 2, Active: 1, Unread: 0}, 120, 0, nil)
+	out := render(rows, summary{Repos: 1, Worktrees: 2, Active: 1, Unread: 0}, 120, 0, nil, -1)
 
 	if !strings.Contains(out, "\u2514\u2500 child") {
 		t.Errorf("expected stack prefix before child branch, got:\n%s", out)
@@ -114,7 +134,7 @@ func TestRenderMultiRepoStackNameMatchesPickerStyle(t *testing.T) {
 			StackPrefix: "\u2514\u2500 ",
 		},
 	}
-	out := render(rows, summary{Repos: 2, Worktrees: 2, Active: 1, Unread: 0}, 120, 0, nil)
+	out := render(rows, summary{Repos: 2, Worktrees: 2, Active: 1, Unread: 0}, 120, 0, nil, -1)
 
 	if !strings.Contains(out, "\u2514\u2500 repo-b/stack-child") {
 		t.Errorf("expected multi-repo stack label to match picker style, got:\n%s", out)
@@ -220,7 +240,7 @@ func TestCollectDataIncludesAllWorktreesUnreadAndStackPrefixes(t *testing.T) {
 	writeDashboardSession(t, "dashrepo", "feature-b", "busy-session", agent.StatusBusy, "Edit", now)
 	writeDashboardSession(t, "dashrepo", "feature-b", "done-session", agent.StatusDone, "", now)
 
-	rows, sum := collectData(context.Background())
+	rows, sum := collectData(context.Background(), nil)
 	if sum.Repos != 1 {
 		t.Fatalf("Repos = %d, want 1", sum.Repos)
 	}
@@ -268,6 +288,24 @@ func TestCollectDataIncludesAllWorktreesUnreadAndStackPrefixes(t *testing.T) {
 	}
 }
 
+func TestRefreshStatusesAppliesIndexWithoutRescanning(t *testing.T) {
+	rows := []row{
+		{Repo: "repo", Branch: "a", WtDirName: "a", Status: agent.StatusBusy},
+		{Repo: "repo", Branch: "b", WtDirName: "b", Status: agent.StatusOffline},
+	}
+	idx := agent.NewSessionIndex([]agent.SessionFileInfo{
+		{RepoName: "repo", WorktreeDir: "b", Session: agent.SessionStatus{SessionID: "s1", Status: agent.StatusWait, Timestamp: time.Now()}},
+	})
+
+	sum := refreshStatuses(rows, summary{Repos: 1, Worktrees: 2, Active: 1}, idx)
+	if rows[0].Status != agent.StatusOffline || rows[1].Status != agent.StatusWait {
+		t.Fatalf("statuses = %s, %s; want offline, WAIT", rows[0].Status, rows[1].Status)
+	}
+	if sum.Active != 1 || sum.Worktrees != 2 || sum.Repos != 1 {
+		t.Fatalf("summary = %+v", sum)
+	}
+}
+
 func TestUpdateFlashesRecordsBusyToDoneAndPrunesMissingRows(t *testing.T) {
 	doneRow := row{
 		Repo:      "repo",
@@ -301,3 +339,183 @@ func TestTermWidthReturnsPositiveFallback(t *testing.T) {
 		t.Fatalf("termWidth() = %d, want positive width", got)
 	}
 }
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1bOB\r\x7f\x03/é\x1b"))
+	want := []key{
+		{code: keyRune, r: 'j'},
+		{code: keyUp},
+		{code: keyDown},
+		{code: keyEnter},
+		{code: keyBackspace},
+		{code: keyCtrlC},
+		{code: keyRune, r: '/'},
+		{code: keyRune, r: 'é'},
+		{code: keyEsc},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseKeys = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("key[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func testState() *state {
+	return &state{rows: []row{
+		{Repo: "api", Branch: "auth", WtDirName: "auth", Path: "/wt/api/auth", Status: agent.StatusDone, Unread: true},
+		{Repo: "api", Branch: "billing", WtDirName: "billing", Path: "/wt/api/billing"},
+		{Repo: "web", Branch: "nav", WtDirName: "nav", Path: "/wt/web/nav"},
+	}}
+}
+
+func runeKey(r rune) key { return key{code: keyRune, r: r} }
+
+func TestHandleKeyNavigationClampsAndActions(t *testing.T) {
+	s := testState()
+	s.handleKey(key{code: keyUp})
+	if s.cursor != 0 {
+		t.Fatalf("cursor = %d after up at top, want 0", s.cursor)
+	}
+	for range 5 {
+		s.handleKey(runeKey('j'))
+	}
+	if s.cursor != 2 {
+		t.Fatalf("cursor = %d after moving past end, want 2", s.cursor)
+	}
+	s.handleKey(runeKey('g'))
+	if s.cursor != 0 {
+		t.Fatalf("g should jump to top, cursor = %d", s.cursor)
+	}
+
+	for k, want := range map[key]action{
+		{code: keyEnter}: actSwitch,
+		runeKey('d'):     actDismiss,
+		runeKey('x'):     actPrepareRemove,
+		runeKey('a'):     actApprove,
+		runeKey('n'):     actDeny,
+		runeKey('q'):     actQuit,
+		{code: keyCtrlC}: actQuit,
+	} {
+		if got := s.handleKey(k); got != want {
+			t.Errorf("handleKey(%+v) = %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestHandleKeyFilter(t *testing.T) {
+	s := testState()
+	s.handleKey(runeKey('/'))
+	for _, r := range "web" {
+		s.handleKey(runeKey(r))
+	}
+	if !s.filtering || s.filter != "web" {
+		t.Fatalf("filter state = %v %q", s.filtering, s.filter)
+	}
+	// While filtering, letters are typed rather than acted on.
+	if got := s.handleKey(runeKey('q')); got != actNone || s.filter != "webq" {
+		t.Fatalf("q while filtering = %v, filter %q", got, s.filter)
+	}
+	s.handleKey(key{code: keyBackspace})
+	s.handleKey(key{code: keyEnter})
+	if s.filtering || s.filter != "web" {
+		t.Fatalf("after enter: filtering=%v filter=%q", s.filtering, s.filter)
+	}
+	if r, ok := s.selected(); !ok || r.Branch != "nav" {
+		t.Fatalf("selected = %+v, want nav", r)
+	}
+
+	s.handleKey(key{code: keyEsc})
+	if s.filter != "" || len(s.visible()) != 3 {
+		t.Fatalf("esc should clear filter, filter=%q visible=%d", s.filter, len(s.visible()))
+	}
+}
+
+func TestHandleKeyRemoveConfirmation(t *testing.T) {
+	s := testState()
+	s.askRemove(s.rows[1], []string{"uncommitted changes"})
+	if !strings.Contains(footer(s), "Remove api/billing?") || !strings.Contains(footer(s), "uncommitted changes") {
+		t.Fatalf("footer = %q", footer(s))
+	}
+	if got := s.handleKey(runeKey('y')); got != actRemove {
+		t.Fatalf("y = %v, want actRemove", got)
+	}
+
+	s.askRemove(s.rows[1], nil)
+	if got := s.handleKey(runeKey('n')); got != actNone || s.confirm != nil {
+		t.Fatalf("n should cancel: action=%v confirm=%v", got, s.confirm)
+	}
+}
+
+func TestSetRowsKeepsCursorOnSameWorktree(t *testing.T) {
+	s := testState()
+	s.cursor = 1
+	rows := []row{
+		{Repo: "api", Branch: "new", WtDirName: "new"},
+		{Repo: "api", Branch: "auth", WtDirName: "auth"},
+		{Repo: "api", Branch: "billing", WtDirName: "billing"},
+	}
+	s.setRows(rows, summary{})
+	if r, _ := s.selected(); r.Branch != "billing" {
+		t.Fatalf("selected = %q, want billing", r.Branch)
+	}
+}
+
+func TestRmArgsKeepSafetyChecks(t *testing.T) {
+	got := strings.Join(rmArgs(row{Repo: "api", Branch: "auth", WtDirName: "auth"}), " ")
+	if got != "rm auth --repo api" {
+		t.Errorf("rmArgs = %q", got)
+	}
+	got = strings.Join(rmArgs(row{Repo: "api", Detached: true, WtDirName: "scratch"}), " ")
+	if got != "rm scratch --repo api" {
+		t.Errorf("detached rmArgs = %q", got)
+	}
+	got = strings.Join(permissionArgs(row{Repo: "api", Branch: "auth", WtDirName: "auth"}, false), " ")
+	if got != "deny auth --repo api" {
+		t.Errorf("permissionArgs = %q", got)
+	}
+}
+
+func TestDetailShowsPendingTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeDashboardSession(t, "api", "auth", "sess-1", agent.StatusWait, "Bash", time.Now())
+	pending := `{"tool":"Bash","input":{"command":"make deploy"}}`
+	if err := os.WriteFile(agent.PendingPathForHarness("api", "auth", "claude", "sess-1"), []byte(pending), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := strings.Join(renderDetail(row{Repo: "api", Branch: "auth", WtDirName: "auth", Path: "/wt/api/auth"},
+		agent.ReadAllSessions("api", "auth"), 120), "\n")
+	if !strings.Contains(out, "waiting on") || !strings.Contains(out, "Bash: make deploy") {
+		t.Errorf("detail missing pending tool:\n%s", out)
+	}
+}
+
+func TestViewShowsSelectionPRAndDetail(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	s := testState()
+	s.setPRs(map[string]*gh.PRInfo{
+		prKey("api", "auth"): {Number: 42, Title: "Add auth", State: "OPEN"},
+	})
+	writeDashboardSession(t, "api", "auth", "sess-1", agent.StatusDone, "", time.Now())
+	filesDir := filepath.Join(agent.StatusDir(), "api", "auth", "claude")
+	if err := os.WriteFile(filepath.Join(filesDir, "sess-1.files"), []byte("/wt/api/auth/main.go\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := view(s, nil, 120, 40, 0, nil)
+	for _, want := range []string{"\u25b8", "PR", "#42", "PR #42  Add auth", "claude:sess-1", "files touched (1):", "main.go", "enter switch"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+
+	s.filter = "zzz"
+	if out := view(s, nil, 120, 40, 0, nil); !strings.Contains(out, `no worktrees match "zzz"`) {
+		t.Errorf("filtered-out view:\n%s", out)
+	}
+}
@@ -0,0 +1,421 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/gh"
+	"github.com/iamrajjoshi/willow/internal/git"
+	"github.com/iamrajjoshi/willow/internal/termfmt"
+	"github.com/iamrajjoshi/willow/internal/ui"
+	"github.com/iamrajjoshi/willow/internal/usage"
+)
+
+type action int
+
+const (
+	actNone action = iota
+	actQuit
+	actSwitch
+	actDismiss
+	actPrepareRemove
+	actRemove
+	actApprove
+	actDeny
+)
+
+// state is the interactive dashboard's view model. rows holds every
+// worktree; the cursor indexes the filtered list.
+type state struct {
+	rows      []row
+	sum       summary
+	prs       map[string]*gh.PRInfo
+	cursor    int
+	filter    string
+	filtering bool
+	// confirm is the worktree awaiting a y/N removal answer.
+	confirm     *row
+	confirmNote string
+	message     string
+}
+
+// visible returns rows whose repo, branch, or path contains the filter.
+func (s *state) visible() []row {
+	if s.filter == "" {
+		return s.rows
+	}
+	q := strings.ToLower(s.filter)
+	var out []row
+	for _, r := range s.rows {
+		if strings.Contains(strings.ToLower(r.Repo+"/"+r.Branch+" "+r.WtDirName+" "+r.Path), q) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (s *state) selected() (row, bool) {
+	vis := s.visible()
+	if len(vis) == 0 {
+		return row{}, false
+	}
+	return vis[s.cursor], true
+}
+
+// setRows replaces the rows while keeping the cursor on the same worktree
+// when it is still present.
+func (s *state) setRows(rows []row, sum summary) {
+	prev, had := s.selected()
+	s.rows, s.sum = rows, sum
+	applyPRs(s.rows, s.prs)
+	if had {
+		for i, r := range s.visible() {
+			if rowKey(r) == rowKey(prev) {
+				s.cursor = i
+				return
+			}
+		}
+	}
+	s.clampCursor()
+}
+
+func (s *state) setPRs(prs map[string]*gh.PRInfo) {
+	s.prs = prs
+	applyPRs(s.rows, prs)
+}
+
+func (s *state) clampCursor() {
+	n := len(s.visible())
+	if s.cursor >= n {
+		s.cursor = n - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+}
+
+func (s *state) move(delta int) {
+	s.cursor += delta
+	s.clampCursor()
+}
+
+// handleKey updates navigation, filter, and confirmation state for k and
+// returns the side effect Run should perform.
+func (s *state) handleKey(k key) action {
+	if k.code == keyCtrlC {
+		return actQuit
+	}
+
+	if s.confirm != nil {
+		if k.code == keyRune && (k.r == 'y' || k.r == 'Y') {
+			return actRemove
+		}
+		s.confirm, s.confirmNote = nil, ""
+		s.message = "Removal cancelled"
+		return actNone
+	}
+
+	if s.filtering {
+		switch k.code {
+		case keyEsc:
+			s.filtering, s.filter = false, ""
+		case keyEnter:
+			s.filtering = false
+		case keyBackspace:
+			if r := []rune(s.filter); len(r) > 0 {
+				s.filter = string(r[:len(r)-1])
+			}
+		case keyUp:
+			s.move(-1)
+		case keyDown:
+			s.move(1)
+		case keyRune:
+			s.filter += string(k.r)
+		}
+		s.clampCursor()
+		return actNone
+	}
+
+	s.message = ""
+	switch k.code {
+	case keyUp:
+		s.move(-1)
+	case keyDown:
+		s.move(1)
+	case keyEnter:
+		return actSwitch
+	case keyEsc:
+		s.filter = ""
+		s.clampCursor()
+	case keyRune:
+		switch k.r {
+		case 'q':
+			return actQuit
+		case 'k':
+			s.move(-1)
+		case 'j':
+			s.move(1)
+		case 'g':
+			s.cursor = 0
+		case 'G':
+			s.cursor = len(s.visible()) - 1
+			s.clampCursor()
+		case 'd':
+			return actDismiss
+		case 'x':
+			return actPrepareRemove
+		case 'a':
+			return actApprove
+		case 'n':
+			return actDeny
+		case '/':
+			s.filtering = true
+		}
+	}
+	return actNone
+}
+
+// askRemove starts a removal confirmation for r, noting anything ww rm
+// would warn about.
+func (s *state) askRemove(r row, warnings []string) {
+	s.confirm = &r
+	s.confirmNote = strings.Join(warnings, ", ")
+}
+
+// removalWarnings mirrors the non-blocking checks ww rm warns about, so
+// they can be shown before the user confirms.
+func removalWarnings(r row) []string {
+	g := &git.Git{Dir: r.Path}
+	var warnings []string
+	if dirty, err := g.IsDirty(); err == nil && dirty {
+		warnings = append(warnings, "uncommitted changes")
+	}
+	if !r.Detached {
+		if unpushed, err := g.HasUnpushedCommits(); err == nil && unpushed {
+			warnings = append(warnings, "unpushed commits")
+		}
+	}
+	return warnings
+}
+
+// rmArgs builds the ww rm invocation for r. --force is deliberately left
+// off so ww rm's blocking checks (stacked children, unanchored detached
+// commits) still apply.
+func rmArgs(r row) []string {
+	return []string{"rm", rowTarget(r), "--repo", r.Repo}
+}
+
+// permissionArgs builds the ww approve or ww deny invocation for r.
+func permissionArgs(r row, approve bool) []string {
+	verb := "approve"
+	if !approve {
+		verb = "deny"
+	}
+	return []string{verb, rowTarget(r), "--repo", r.Repo}
+}
+
+// rowTarget names r the way ww commands resolve worktrees.
+func rowTarget(r row) string {
+	if r.Detached {
+		return r.WtDirName
+	}
+	return r.Branch
+}
+
+// prKey identifies a PR lookup result for a worktree.
+func prKey(repo, branch string) string {
+	return repo + "/" + branch
+}
+
+func applyPRs(rows []row, prs map[string]*gh.PRInfo) {
+	for i := range rows {
+		rows[i].PR = prs[prKey(rows[i].Repo, rows[i].Branch)]
+	}
+}
+
+// fetchPRs looks up PRs for every branch row, one gh call per repo. Repos
+// where gh fails (not installed, not a GitHub remote) are skipped.
+func fetchPRs(rows []row) map[string]*gh.PRInfo {
+	type repoBranches struct {
+		dir      string
+		branches []string
+	}
+	byRepo := map[string]*repoBranches{}
+	var order []string
+	for _, r := range rows {
+		if r.Detached || r.Branch == "" {
+			continue
+		}
+		rb, ok := byRepo[r.Repo]
+		if !ok {
+			rb = &repoBranches{dir: r.Path}
+			byRepo[r.Repo] = rb
+			order = append(order, r.Repo)
+		}
+		rb.branches = append(rb.branches, r.Branch)
+	}
+
+	prs := map[string]*gh.PRInfo{}
+	for _, repo := range order {
+		rb := byRepo[repo]
+		found, err := gh.BatchPRInfo(rb.dir, rb.branches)
+		if err != nil {
+			continue
+		}
+		for branch, pr := range found {
+			prs[prKey(repo, branch)] = pr
+		}
+	}
+	return prs
+}
+
+func prLabel(u *ui.UI, pr *gh.PRInfo) (plain, colored string) {
+	if pr == nil {
+		return "", ""
+	}
+	num := fmt.Sprintf("#%d", pr.Number)
+	if pr.State == "MERGED" || pr.State == "CLOSED" {
+		text := num + " " + strings.ToLower(pr.State)
+		return text, u.Dim(text)
+	}
+	switch pr.CIStatus() {
+	case "pass":
+		return num + " ✓", num + " " + u.Green("✓")
+	case "fail":
+		return num + " ✗", num + " " + u.Red("✗")
+	case "pending":
+		return num + " ○", num + " " + u.Yellow("○")
+	}
+	return num, num
+}
+
+const (
+	detailTimelineWindow  = time.Hour
+	detailTimelineBuckets = 30
+	detailMaxFiles        = 6
+)
+
+// renderDetail shows the selected worktree's sessions with a timeline
+// sparkline, the files they touched, and its PR.
+func renderDetail(r row, sessions []*agent.SessionStatus, width int) []string {
+	u := &ui.UI{}
+	home, _ := os.UserHomeDir()
+
+	lines := []string{
+		"  " + u.Dim(strings.Repeat("─", max(1, min(width, 100)-2))),
+		"  " + u.Bold(r.Repo+"/"+r.Branch) + "  " + u.Dim(shortenPathWithHome(r.Path, home)),
+	}
+	if r.PR != nil {
+		_, label := prLabel(u, r.PR)
+		lines = append(lines, fmt.Sprintf("  PR %s  %s", label, r.PR.Title))
+	}
+	if len(sessions) == 0 {
+		return append(lines, "  "+u.Dim("no agent sessions"))
+	}
+
+	since := time.Now().Add(-detailTimelineWindow)
+	var files []string
+	seen := map[string]bool{}
+	for _, ss := range sessions {
+		status := agent.EffectiveStatus(ss.Status, ss.Timestamp)
+		entries, _ := agent.ReadTimeline(r.Repo, r.WtDirName, ss.SessionID, since)
+		spark := agent.Sparkline(entries, detailTimelineBuckets, detailTimelineWindow)
+		line := fmt.Sprintf("  %s %-8s %s  %s", agent.StatusIcon(status),
+			ss.Harness+":"+agent.ShortSessionID(ss.SessionID), colorStatus(u, status, fmt.Sprintf("%-4s", status)), spark)
+		if ss.ToolCount > 0 {
+			line += u.Dim(fmt.Sprintf("  %d tools", ss.ToolCount))
+		}
+		if ss.CostUSD > 0 {
+			line += u.Dim("  " + usage.FormatCost(ss.CostUSD))
+		}
+		lines = append(lines, line)
+		if status == agent.StatusWait {
+			if p := agent.ReadPending(r.Repo, r.WtDirName, ss.Harness, ss.SessionID); p != nil {
+				lines = append(lines, "    "+u.Yellow("waiting on ")+termfmt.TruncateEnd(p.Summary(), max(20, min(width, 100)-16)))
+			}
+		}
+		for _, f := range agent.ReadFilesTouched(r.Repo, r.WtDirName, ss.SessionID) {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+
+	if len(files) > 0 {
+		lines = append(lines, fmt.Sprintf("  %s", u.Dim(fmt.Sprintf("files touched (%d):", len(files)))))
+		for i, f := range files {
+			if i == detailMaxFiles {
+				lines = append(lines, "    "+u.Dim(fmt.Sprintf("… %d more", len(files)-detailMaxFiles)))
+				break
+			}
+			lines = append(lines, "    "+strings.TrimPrefix(f, r.Path+string(os.PathSeparator)))
+		}
+	}
+	return lines
+}
+
+func footer(s *state) string {
+	u := &ui.UI{}
+	switch {
+	case s.confirm != nil:
+		prompt := fmt.Sprintf("Remove %s/%s?", s.confirm.Repo, s.confirm.Branch)
+		if s.confirmNote != "" {
+			prompt += " " + u.Yellow("("+s.confirmNote+")")
+		}
+		return "  " + prompt + " [y/N] "
+	case s.filtering:
+		return "  /" + s.filter + "█"
+	case s.message != "":
+		return "  " + s.message
+	}
+	help := "↑/↓ select  enter switch  a approve  n deny  d dismiss  x remove  / filter  q quit"
+	if s.filter != "" {
+		help = fmt.Sprintf("filter: %s (esc to clear)  ", s.filter) + help
+	}
+	return "  " + u.Dim(help)
+}
+
+// view composes the table, detail pane, and footer for one frame, scrolling
+// the table so the cursor stays visible within height.
+func view(s *state, idx *agent.SessionIndex, width, height, frame int, flashUntil map[string]time.Time) string {
+	vis := s.visible()
+	sel, hasSel := s.selected()
+
+	var detail []string
+	if hasSel {
+		detail = renderDetail(sel, idx.ReadAllSessions(sel.Repo, sel.WtDirName), width)
+	}
+
+	// Header (2 lines) + column header + separator + footer + spacing.
+	room := height - len(detail) - 6
+	if room < 3 {
+		room = 3
+	}
+	start := 0
+	if len(vis) > room {
+		start = min(max(0, s.cursor-room/2), len(vis)-room)
+	}
+	end := min(len(vis), start+room)
+
+	var b strings.Builder
+	if len(s.rows) > 0 && len(vis) == 0 {
+		u := &ui.UI{}
+		b.WriteString(u.Bold("willow dashboard  " + headerStats(s.sum)))
+		b.WriteString("\n\n  ")
+		b.WriteString(u.Dim(fmt.Sprintf("no worktrees match %q", s.filter)))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(render(vis[start:end], s.sum, width, frame, flashUntil, s.cursor-start))
+	}
+	for _, line := range detail {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footer(s))
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyCtrlC
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// parseKeys decodes one read from a raw-mode terminal. Arrow keys arrive as
+// a single escape sequence per read, so a lone ESC is the Escape key.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for i := 0; i < len(b); {
+		switch c := b[i]; {
+		case c == 0x1b:
+			if i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				switch b[i+2] {
+				case 'A':
+					keys = append(keys, key{code: keyUp})
+				case 'B':
+					keys = append(keys, key{code: keyDown})
+				}
+				i += 3
+				continue
+			}
+			keys = append(keys, key{code: keyEsc})
+			i++
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{code: keyEnter})
+			i++
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+			i++
+		case c == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+			i++
+		case c == 0x0e: // Ctrl-N
+			keys = append(keys, key{code: keyDown})
+			i++
+		case c == 0x10: // Ctrl-P
+			keys = append(keys, key{code: keyUp})
+			i++
+		case c < 0x20:
+			i++
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			keys = append(keys, key{code: keyRune, r: r})
+			i += size
+		}
+	}
+	return keys
+}
+
+// terminal owns the controlling tty while the dashboard is interactive:
+// raw mode for single-key input and a reader goroutine feeding keys. The
+// reader polls with a timeout rather than blocking in read, so close can
+// stop it before handing the tty to another program (tmux attach) without
+// it swallowing that program's first keystroke.
+type terminal struct {
+	tty   *os.File
+	state *term.State
+	keys  chan key
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// openTerminal puts /dev/tty into raw mode. It fails when there is no
+// controlling terminal, in which case the dashboard stays read-only.
+func openTerminal() (*terminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+	t := &terminal{
+		tty:   tty,
+		state: state,
+		keys:  make(chan key, 16),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go t.read()
+	return t, nil
+}
+
+func (t *terminal) read() {
+	defer close(t.done)
+	fd := int(t.tty.Fd())
+	buf := make([]byte, 64)
+	for {
+		select {
+		case <-t.stop:
+			return
+		default:
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, 100)
+		if err == unix.EINTR || n == 0 {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		n, err = unix.Read(fd, buf)
+		if err != nil || n == 0 {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			select {
+			case t.keys <- k:
+			case <-t.stop:
+				return
+			}
+		}
+	}
+}
+
+// size returns the terminal's columns and rows, falling back to 120x40.
+func (t *terminal) size() (int, int) {
+	if t != nil {
+		if w, h, err := term.GetSize(int(t.tty.Fd())); err == nil && w > 0 && h > 0 {
+			return w, h
+		}
+	}
+	return termWidth(), 40
+}
+
+func (t *terminal) close() {
+	close(t.stop)
+	<-t.done
+	_ = term.Restore(int(t.tty.Fd()), t.state)
+	_ = t.tty.Close()
+}
@@ -79,18 +79,31 @@ func EnsureCLI(feature string) error {
 	return nil
 }
 
-func prLookupArgs(branch string) []string {
-	return []string{
+// repoArgs targets an explicit [HOST/]OWNER/REPO instead of letting gh pick
+// one from the git remotes. Empty repo keeps gh's default.
+func repoArgs(repo string) []string {
+	if repo == "" {
+		return nil
+	}
+	return []string{"--repo", repo}
+}
+
+func prLookupArgs(repo, branch string) []string {
+	args := []string{
 		"pr", "list",
 		"--head", branch,
 		"--state", "open",
 		"--json", prJSONFields,
 		"--limit", fmt.Sprintf("%d", openPRLookupLimit),
 	}
+	return append(args, repoArgs(repo)...)
 }
 
-func prCreateArgs(base, head string, draft bool) []string {
+// prCreateArgs builds gh pr create. head is a branch name, or owner:branch
+// when the branch lives on a fork of repo.
+func prCreateArgs(repo, base, head string, draft bool) []string {
 	args := []string{"pr", "create", "--fill", "--base", base, "--head", head}
+	args = append(args, repoArgs(repo)...)
 	if draft {
 		args = append(args, "--draft")
 	}
@@ -143,12 +156,14 @@ func selectMatchingPR(prs []*PRInfo, headOID string) *PRInfo {
 	return nil
 }
 
-func FindOpenPR(dir, branch, headOID string) (*PRInfo, error) {
+// FindOpenPR returns the open PR for branch whose head matches headOID, in
+// repo when set or gh's default repo otherwise.
+func FindOpenPR(dir, repo, branch, headOID string) (*PRInfo, error) {
 	if err := EnsureCLI("PR creation"); err != nil {
 		return nil, err
 	}
 
-	cmd := exec.Command("gh", prLookupArgs(branch)...)
+	cmd := exec.Command("gh", prLookupArgs(repo, branch)...)
 	cmd.Dir = dir
 	cmd.Env = append(os.Environ(),
 		"GH_PROMPT_DISABLED=1",
@@ -171,12 +186,14 @@ func FindOpenPR(dir, branch, headOID string) (*PRInfo, error) {
 	return selectMatchingPR(prs, headOID), nil
 }
 
-func CreatePR(dir, base, head string, draft bool) (string, error) {
+// CreatePR opens a PR from head into base, in repo when set or gh's default
+// repo otherwise, and returns its URL.
+func CreatePR(dir, repo, base, head string, draft bool) (string, error) {
 	if err := EnsureCLI("PR creation"); err != nil {
 		return "", err
 	}
 
-	cmd := exec.Command("gh", prCreateArgs(base, head, draft)...)
+	cmd := exec.Command("gh", prCreateArgs(repo, base, head, draft)...)
 	cmd.Dir = dir
 	cmd.Env = append(os.Environ(),
 		"GH_PROMPT_DISABLED=1",
@@ -195,6 +212,77 @@ func CreatePR(dir, base, head string, draft bool) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// RetargetPR changes the base branch of an existing pull request, in repo
+// when set or gh's default repo otherwise.
+func RetargetPR(dir, repo string, number int, base string) error {
+	if err := EnsureCLI("PR retargeting"); err != nil {
+		return err
+	}
+
+	args := append([]string{"pr", "edit", fmt.Sprintf("%d", number), "--base", base}, repoArgs(repo)...)
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GH_PROMPT_DISABLED=1",
+		"GH_NO_UPDATE_NOTIFIER=1",
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return fmt.Errorf("gh pr edit failed: %s", msg)
+		}
+		return fmt.Errorf("gh pr edit failed: %w", err)
+	}
+	return nil
+}
+
+// PRBody returns the markdown body of a pull request.
+func PRBody(dir string, number int) (string, error) {
+	if err := EnsureCLI("PR updates"); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("gh", "pr", "view", fmt.Sprintf("%d", number), "--json", "body", "-q", ".body")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GH_PROMPT_DISABLED=1",
+		"GH_NO_UPDATE_NOTIFIER=1",
+	)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("gh pr view failed: %w", err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// EditPRBody replaces the markdown body of a pull request.
+func EditPRBody(dir string, number int, body string) error {
+	if err := EnsureCLI("PR updates"); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("gh", "pr", "edit", fmt.Sprintf("%d", number), "--body-file", "-")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"GH_PROMPT_DISABLED=1",
+		"GH_NO_UPDATE_NOTIFIER=1",
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return fmt.Errorf("gh pr edit failed: %s", msg)
+		}
+		return fmt.Errorf("gh pr edit failed: %w", err)
+	}
+	return nil
+}
+
 // BatchPRInfo fetches PR info for multiple branches in a single gh call.
 func BatchPRInfo(dir string, branches []string) (map[string]*PRInfo, error) {
 	if err := EnsureCLI("stack status"); err != nil {
@@ -154,7 +154,7 @@ func TestGHPRJSONParsing(t *testing.T) {
 }
 
 func TestPRLookupArgs(t *testing.T) {
-	got := prLookupArgs("feature-x")
+	got := prLookupArgs("", "feature-x")
 	want := []string{
 		"pr", "list",
 		"--head", "feature-x",
@@ -171,24 +171,34 @@ func TestPRLookupArgs(t *testing.T) {
 func TestPRCreateArgs(t *testing.T) {
 	tests := []struct {
 		name  string
+		repo  string
+		head  string
 		draft bool
 		want  []string
 	}{
 		{
 			name:  "ready for review",
+			head:  "feature-x",
 			draft: false,
 			want:  []string{"pr", "create", "--fill", "--base", "main", "--head", "feature-x"},
 		},
 		{
 			name:  "draft",
+			head:  "feature-x",
 			draft: true,
 			want:  []string{"pr", "create", "--fill", "--base", "main", "--head", "feature-x", "--draft"},
 		},
+		{
+			name: "fork",
+			repo: "org/repo",
+			head: "me:feature-x",
+			want: []string{"pr", "create", "--fill", "--base", "main", "--head", "me:feature-x", "--repo", "org/repo"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := prCreateArgs("main", "feature-x", tt.draft)
+			got := prCreateArgs(tt.repo, "main", tt.head, tt.draft)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Fatalf("prCreateArgs() = %v, want %v", got, tt.want)
 			}
@@ -304,7 +314,7 @@ JSON
 	t.Setenv("PATH", binDir)
 
 	dir := t.TempDir()
-	got, err := FindOpenPR(dir, "feature-a", "sha-match")
+	got, err := FindOpenPR(dir, "", "feature-a", "sha-match")
 	if err != nil {
 		t.Fatalf("FindOpenPR() error = %v", err)
 	}
@@ -331,7 +341,7 @@ func TestFindOpenPRReturnsCommandOutputOnFailure(t *testing.T) {
 	}
 	t.Setenv("PATH", binDir)
 
-	got, err := FindOpenPR(t.TempDir(), "feature-a", "")
+	got, err := FindOpenPR(t.TempDir(), "", "feature-a", "")
 	if err == nil || got != nil {
 		t.Fatalf("FindOpenPR failure = %+v, %v; want nil error", got, err)
 	}
@@ -356,7 +366,7 @@ printf 'https://github.com/org/repo/pull/99\n'
 	t.Setenv("PATH", binDir)
 
 	dir := t.TempDir()
-	got, err := CreatePR(dir, "main", "feature-a", true)
+	got, err := CreatePR(dir, "", "main", "feature-a", true)
 	if err != nil {
 		t.Fatalf("CreatePR() error = %v", err)
 	}
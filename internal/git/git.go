@@ -13,6 +13,21 @@ import (
 type Git struct {
 	Dir     string
 	Verbose bool
+	// Remote is the remote whose tracking refs the remote-branch helpers
+	// read. Empty means origin.
+	Remote string
+}
+
+func (g *Git) remote() string {
+	if g.Remote != "" {
+		return g.Remote
+	}
+	return "origin"
+}
+
+// RemoteRef returns the remote-tracking ref for branch, e.g. origin/main.
+func (g *Git) RemoteRef(branch string) string {
+	return g.remote() + "/" + branch
 }
 
 func (g *Git) Run(args ...string) (string, error) {
@@ -111,19 +126,19 @@ func (g *Git) IsDirty() (bool, error) {
 	return out != "", nil
 }
 
-// MergedBranches returns branches that have been merged into origin/<base>.
-// Branches whose tip SHA equals origin/<base> are excluded — a brand-new
-// branch forked from origin/<base> has zero unique commits and would
+// MergedBranches returns branches that have been merged into <remote>/<base>.
+// Branches whose tip SHA equals <remote>/<base> are excluded — a brand-new
+// branch forked from <remote>/<base> has zero unique commits and would
 // otherwise be reported as "merged" before any work has happened.
 func (g *Git) MergedBranches(base string) ([]string, error) {
-	out, err := g.Run("branch", "--merged", "origin/"+base, "--format=%(refname:short) %(objectname)")
+	out, err := g.Run("branch", "--merged", g.remote()+"/"+base, "--format=%(refname:short) %(objectname)")
 	if err != nil {
 		return nil, err
 	}
 	if out == "" {
 		return nil, nil
 	}
-	baseSHA, _ := g.Run("rev-parse", "origin/"+base)
+	baseSHA, _ := g.Run("rev-parse", g.remote()+"/"+base)
 	var branches []string
 	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
@@ -146,18 +161,18 @@ func (g *Git) MergedBranches(base string) ([]string, error) {
 }
 
 // MergedBranchSet returns the subset of the given branches merged into
-// origin/<base>. Uses for-each-ref with exact refname paths rather than
+// <remote>/<base>. Uses for-each-ref with exact refname paths rather than
 // `git branch --merged`, which scans every local ref and would take
 // hundreds of ms on repos with thousands of branches. Excludes trivial
-// forks whose tip SHA equals origin/<base> (same filter as MergedBranches).
-// The origin/<base> ref is included in the query so we can reuse its SHA
+// forks whose tip SHA equals <remote>/<base> (same filter as MergedBranches).
+// The <remote>/<base> ref is included in the query so we can reuse its SHA
 // without spawning a second git process.
 func (g *Git) MergedBranchSet(base string, branches []string) map[string]bool {
 	if len(branches) == 0 {
 		return map[string]bool{}
 	}
-	args := []string{"for-each-ref", "--merged=origin/" + base, "--format=%(refname:short) %(objectname)"}
-	baseRef := "refs/remotes/origin/" + base
+	args := []string{"for-each-ref", "--merged=" + g.remote() + "/" + base, "--format=%(refname:short) %(objectname)"}
+	baseRef := "refs/remotes/" + g.remote() + "/" + base
 	args = append(args, baseRef)
 	for _, b := range branches {
 		args = append(args, "refs/heads/"+b)
@@ -167,7 +182,7 @@ func (g *Git) MergedBranchSet(base string, branches []string) map[string]bool {
 		return map[string]bool{}
 	}
 
-	baseName := "origin/" + base
+	baseName := g.remote() + "/" + base
 	baseSHA := ""
 	type refInfo struct {
 		name string
@@ -203,10 +218,10 @@ func (g *Git) MergedBranchSet(base string, branches []string) map[string]bool {
 	return set
 }
 
-// RemoteBranches returns remote branch names from origin, stripping the
-// "origin/" prefix. The HEAD pointer is excluded.
+// RemoteBranches returns remote branch names from the remote, stripping the
+// "<remote>/" prefix. The HEAD pointer is excluded.
 func (g *Git) RemoteBranches() ([]string, error) {
-	out, err := g.Run("for-each-ref", "--format=%(refname:short)", "refs/remotes/origin")
+	out, err := g.Run("for-each-ref", "--format=%(refname:short)", "refs/remotes/"+g.remote())
 	if err != nil {
 		return nil, err
 	}
@@ -216,17 +231,17 @@ func (g *Git) RemoteBranches() ([]string, error) {
 	var branches []string
 	for _, b := range strings.Split(out, "\n") {
 		b = strings.TrimSpace(b)
-		if b == "" || b == "origin/HEAD" || strings.HasSuffix(b, "/HEAD") {
+		if b == "" || b == g.remote()+"/HEAD" || strings.HasSuffix(b, "/HEAD") {
 			continue
 		}
-		branches = append(branches, strings.TrimPrefix(b, "origin/"))
+		branches = append(branches, strings.TrimPrefix(b, g.remote()+"/"))
 	}
 	return branches, nil
 }
 
-// RemoteBranchExists checks if a branch exists on origin.
+// RemoteBranchExists checks if a branch exists on the remote.
 func (g *Git) RemoteBranchExists(branch string) bool {
-	out, _ := g.Run("branch", "-r", "--list", "origin/"+branch)
+	out, _ := g.Run("branch", "-r", "--list", g.remote()+"/"+branch)
 	return strings.TrimSpace(out) != ""
 }
 
@@ -242,6 +257,20 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// RebaseOnto replays the commits after upstream onto newBase. Used when the
+// old parent tip is known, so commits that already landed in the parent (for
+// example via squash merge or an amended parent) are not replayed.
+func (g *Git) RebaseOnto(newBase, upstream string) error {
+	_, err := g.Run("rebase", "--onto", newBase, upstream)
+	return err
+}
+
+// RebaseContinue continues an in-progress rebase without opening an editor.
+func (g *Git) RebaseContinue() error {
+	_, err := g.Run("-c", "core.editor=true", "rebase", "--continue")
+	return err
+}
+
 // RebaseAbort aborts an in-progress rebase.
 func (g *Git) RebaseAbort() error {
 	_, err := g.Run("rebase", "--abort")
@@ -271,6 +300,28 @@ func (g *Git) IsRebaseInProgress() bool {
 	return false
 }
 
+// RevParse resolves ref to a full commit SHA.
+func (g *Git) RevParse(ref string) (string, error) {
+	return g.Run("rev-parse", "--verify", ref+"^{commit}")
+}
+
+// MergeBase returns the best common ancestor of a and b.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.Run("merge-base", a, b)
+}
+
+// ForkPoint returns the commit where branch forked from ref, consulting ref's
+// reflog so a rewritten ref still yields the commit branch was built on.
+func (g *Git) ForkPoint(ref, branch string) (string, error) {
+	return g.Run("merge-base", "--fork-point", ref, branch)
+}
+
+// IsAncestor reports whether ancestor is reachable from descendant.
+func (g *Git) IsAncestor(ancestor, descendant string) bool {
+	_, err := g.Run("merge-base", "--is-ancestor", ancestor, descendant)
+	return err == nil
+}
+
 // CommitsAhead returns the number of commits HEAD is ahead of base.
 func (g *Git) CommitsAhead(base string) (int, error) {
 	out, err := g.Run("rev-list", "--count", base+"..HEAD")
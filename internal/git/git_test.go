@@ -426,3 +426,21 @@ func TestDirtyAheadUnpushedAndRebaseHelpers(t *testing.T) {
 		t.Fatal("removed rebase dir should not be detected")
 	}
 }
+
+func TestRemoteFieldSelectsTrackingRefs(t *testing.T) {
+	work := setupRemoteAndClone(t, "main")
+	if out, err := exec.Command("git", "-C", work, "remote", "rename", "origin", "upstream").CombinedOutput(); err != nil {
+		t.Fatalf("git remote rename: %v\n%s", err, out)
+	}
+
+	g := &Git{Dir: work, Remote: "upstream"}
+	if got := g.RemoteRef("main"); got != "upstream/main" {
+		t.Errorf("RemoteRef = %q, want upstream/main", got)
+	}
+	if !g.RemoteBranchExists("main") {
+		t.Error("RemoteBranchExists(main) = false on upstream")
+	}
+	if (&Git{Dir: work}).RemoteBranchExists("main") {
+		t.Error("RemoteBranchExists(main) = true on missing origin")
+	}
+}
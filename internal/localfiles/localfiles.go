@@ -0,0 +1,165 @@
+// Package localfiles brings untracked files, such as .env files, editor
+// settings, and dependency caches, from an existing worktree into a new one.
+package localfiles
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Result lists the paths Apply handled, relative to the worktree root.
+type Result struct {
+	Copied  []string
+	Linked  []string
+	Skipped []string // already present in the destination
+}
+
+// Apply matches each pattern against src and copies (copyPatterns) or
+// symlinks (linkPatterns) the matches to the same relative path under dst.
+// Patterns use filepath.Match syntax; a matched directory is handled as a
+// whole. Paths that already exist under dst are left alone. Errors for
+// individual paths are joined and returned after the rest are applied.
+func Apply(src, dst string, copyPatterns, linkPatterns []string) (Result, error) {
+	var res Result
+	var errs []error
+	apply := func(patterns []string, link bool) {
+		for _, pattern := range patterns {
+			matches, err := Match(src, pattern)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for _, rel := range matches {
+				target := filepath.Join(dst, rel)
+				if _, err := os.Lstat(target); err == nil {
+					res.Skipped = append(res.Skipped, rel)
+					continue
+				}
+				if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if link {
+					err = os.Symlink(filepath.Join(src, rel), target)
+				} else {
+					err = copyPath(filepath.Join(src, rel), target)
+				}
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", rel, err))
+					continue
+				}
+				if link {
+					res.Linked = append(res.Linked, rel)
+				} else {
+					res.Copied = append(res.Copied, rel)
+				}
+			}
+		}
+	}
+	apply(copyPatterns, false)
+	apply(linkPatterns, true)
+	return res, errors.Join(errs...)
+}
+
+// Match returns the paths under root matching pattern, relative to root.
+// Patterns must stay inside root.
+func Match(root, pattern string) ([]string, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(root, filepath.Clean(pattern)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	rels := make([]string, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m)
+		if err != nil || rel == "." || rel == ".git" || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+			continue
+		}
+		rels = append(rels, rel)
+	}
+	return rels, nil
+}
+
+// ValidatePattern rejects patterns that are absolute or climb out of the
+// worktree.
+func ValidatePattern(pattern string) error {
+	clean := filepath.Clean(pattern)
+	if pattern == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("pattern %q must be relative to the worktree", pattern)
+	}
+	if _, err := filepath.Match(clean, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyEntry(src, dst, info)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyEntry(path, target, info)
+	})
+}
+
+// copyEntry copies one file or symlink. Regular files are cloned when the
+// filesystem supports copy-on-write, so large caches cost no extra space.
+func copyEntry(src, dst string, info fs.FileInfo) error {
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	case info.Mode().IsRegular():
+		if err := reflink(src, dst); err == nil {
+			return nil
+		}
+		return copyFile(src, dst, info.Mode().Perm())
+	default:
+		// Sockets, pipes and devices have no place in a worktree.
+		return nil
+	}
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,82 @@
+package localfiles
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyCopiesAndLinks(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(src, ".env"), "SECRET=1\n")
+	writeFile(t, filepath.Join(src, ".env.local"), "LOCAL=1\n")
+	writeFile(t, filepath.Join(src, ".vscode", "settings.json"), "{}\n")
+	writeFile(t, filepath.Join(src, "node_modules", "pkg", "index.js"), "module.exports = 1\n")
+	if err := os.Symlink("pkg", filepath.Join(src, "node_modules", "alias")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(src, "README.md"), "source\n")
+	writeFile(t, filepath.Join(dst, "README.md"), "tracked\n")
+
+	res, err := Apply(src, dst, []string{".env*", ".vscode/settings.json", "node_modules", "README.md", "missing"}, nil)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	wantCopied := []string{".env", ".env.local", ".vscode/settings.json", "node_modules"}
+	if !slices.Equal(res.Copied, wantCopied) {
+		t.Errorf("Copied = %v, want %v", res.Copied, wantCopied)
+	}
+	if !slices.Equal(res.Skipped, []string{"README.md"}) {
+		t.Errorf("Skipped = %v, want [README.md]", res.Skipped)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "README.md")); string(data) != "tracked\n" {
+		t.Errorf("existing file overwritten: %q", data)
+	}
+	if info, err := os.Stat(filepath.Join(dst, ".env")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf(".env copy = %v, %v; want mode 0600", info, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "node_modules", "pkg", "index.js")); string(data) != "module.exports = 1\n" {
+		t.Errorf("nested file not copied: %q", data)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "node_modules", "alias")); err != nil || link != "pkg" {
+		t.Errorf("symlink inside copied dir = %q, %v", link, err)
+	}
+}
+
+func TestApplySymlinksToSource(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(src, "cache", "blob"), "x")
+
+	res, err := Apply(src, dst, nil, []string{"cache"})
+	if err != nil || !slices.Equal(res.Linked, []string{"cache"}) {
+		t.Fatalf("Apply = %+v, %v", res, err)
+	}
+	link, err := os.Readlink(filepath.Join(dst, "cache"))
+	if err != nil || link != filepath.Join(src, "cache") {
+		t.Errorf("link = %q, %v; want %s", link, err, filepath.Join(src, "cache"))
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	for _, p := range []string{".env", "config/*.local.json", "node_modules"} {
+		if err := ValidatePattern(p); err != nil {
+			t.Errorf("ValidatePattern(%q) = %v", p, err)
+		}
+	}
+	for _, p := range []string{"", "/etc/passwd", "../secrets", "a/../../b", "[bad"} {
+		if err := ValidatePattern(p); err == nil {
+			t.Errorf("ValidatePattern(%q) accepted", p)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package localfiles
+
+import "golang.org/x/sys/unix"
+
+// reflink clones src to dst with clonefile(2), which APFS supports.
+func reflink(src, dst string) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+const (
+	defaultSinkRetries = 2
+	sinkTimeout        = 5 * time.Second
+)
+
+// retryBaseDelay is the first backoff between sink attempts; each retry
+// doubles it. Tests shrink it.
+var retryBaseDelay = 500 * time.Millisecond
+
+// defaultSinkStatuses are delivered when a sink does not set statuses,
+// matching the transitions desktop notifications fire on.
+var defaultSinkStatuses = []string{"DONE", "WAIT"}
+
+// Payload is the JSON body posted to webhook sinks. Statuses are the
+// strings willow writes to session files (BUSY, WAIT, DONE, IDLE, --).
+type Payload struct {
+	Repo            string    `json:"repo"`
+	Worktree        string    `json:"worktree"`
+	Harness         string    `json:"harness,omitempty"`
+	Session         string    `json:"session,omitempty"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Time            time.Time `json:"time"`
+	Message         string    `json:"message"`
+}
+
+// SinkName returns the sink's configured name, falling back to its type.
+func SinkName(sink config.NotifySinkConfig) string {
+	if sink.Name != "" {
+		return sink.Name
+	}
+	return sink.Type
+}
+
+// SinkWants reports whether sink is configured to receive transitions to
+// status.
+func SinkWants(sink config.NotifySinkConfig, status string) bool {
+	statuses := sink.Statuses
+	if len(statuses) == 0 {
+		statuses = defaultSinkStatuses
+	}
+	for _, s := range statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// SendSinks delivers p to every sink whose status filter matches p.To.
+// Failures are collected so one broken sink does not block the others.
+func SendSinks(ctx context.Context, sinks []config.NotifySinkConfig, p Payload) error {
+	var errs []error
+	for _, sink := range sinks {
+		if !SinkWants(sink, p.To) {
+			continue
+		}
+		if err := SendSink(ctx, sink, p); err != nil {
+			errs = append(errs, fmt.Errorf("notify sink %s: %w", SinkName(sink), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendSink delivers p to a single sink, retrying transient failures with
+// exponential backoff. The status filter is not applied.
+func SendSink(ctx context.Context, sink config.NotifySinkConfig, p Payload) error {
+	if sink.URL == "" {
+		return fmt.Errorf("url is empty")
+	}
+	retries := defaultSinkRetries
+	if sink.Retries != nil && *sink.Retries >= 0 {
+		retries = *sink.Retries
+	}
+
+	delay := retryBaseDelay
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		retryable, err = postSink(ctx, sink, p)
+		if err == nil || !retryable || attempt >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// postSink makes one delivery attempt. Network errors, 429s, and 5xx
+// responses are retryable; other failures are not.
+func postSink(ctx context.Context, sink config.NotifySinkConfig, p Payload) (bool, error) {
+	req, err := buildSinkRequest(ctx, sink, p)
+	if err != nil {
+		return false, err
+	}
+	client := &http.Client{Timeout: sinkTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+func buildSinkRequest(ctx context.Context, sink config.NotifySinkConfig, p Payload) (*http.Request, error) {
+	var body []byte
+	contentType := "application/json"
+	headers := map[string]string{}
+
+	switch sink.Type {
+	case "webhook":
+		data, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	case "slack":
+		data, err := json.Marshal(map[string]string{"text": p.Message})
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	case "ntfy":
+		body = []byte(p.Message)
+		contentType = "text/plain; charset=utf-8"
+		headers["Title"] = fmt.Sprintf("willow: %s/%s", p.Repo, p.Worktree)
+		switch p.To {
+		case "DONE":
+			headers["Tags"] = "white_check_mark"
+		case "WAIT":
+			headers["Tags"] = "hourglass"
+			headers["Priority"] = "high"
+		}
+	default:
+		return nil, fmt.Errorf("unknown sink type %q (want webhook, slack, or ntfy)", sink.Type)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "willow")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range sink.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+type recordedRequest struct {
+	header http.Header
+	body   string
+}
+
+// stubSink records requests and answers with the queued status codes,
+// falling back to 200 once they run out.
+type stubSink struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	statuses []int
+}
+
+func newStubSink(t *testing.T, statuses ...int) (*stubSink, *httptest.Server) {
+	t.Helper()
+	stub := &stubSink{statuses: statuses}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, recordedRequest{header: r.Header.Clone(), body: string(body)})
+		code := http.StatusOK
+		if len(stub.statuses) > 0 {
+			code, stub.statuses = stub.statuses[0], stub.statuses[1:]
+		}
+		stub.mu.Unlock()
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return stub, srv
+}
+
+func (s *stubSink) all() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest(nil), s.requests...)
+}
+
+func fastRetries(t *testing.T) {
+	t.Helper()
+	prev := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = prev })
+}
+
+func intPtr(v int) *int { return &v }
+
+func samplePayload(to string) Payload {
+	return Payload{
+		Repo:            "repo",
+		Worktree:        "wt",
+		Harness:         "claude",
+		Session:         "s1",
+		From:            "BUSY",
+		To:              to,
+		DurationSeconds: 90,
+		Time:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message:         "✅ repo/wt finished",
+	}
+}
+
+func TestSendSink_WebhookPostsJSONPayload(t *testing.T) {
+	stub, srv := newStubSink(t)
+	sink := config.NotifySinkConfig{Type: "webhook", URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer x"}}
+
+	if err := SendSink(context.Background(), sink, samplePayload("DONE")); err != nil {
+		t.Fatalf("SendSink: %v", err)
+	}
+	reqs := stub.all()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1", len(reqs))
+	}
+	var got Payload
+	if err := json.Unmarshal([]byte(reqs[0].body), &got); err != nil {
+		t.Fatalf("decode body %q: %v", reqs[0].body, err)
+	}
+	if got != samplePayload("DONE") {
+		t.Errorf("payload = %+v, want %+v", got, samplePayload("DONE"))
+	}
+	if reqs[0].header.Get("Content-Type") != "application/json" || reqs[0].header.Get("Authorization") != "Bearer x" {
+		t.Errorf("headers = %v", reqs[0].header)
+	}
+}
+
+func TestSendSink_SlackPostsText(t *testing.T) {
+	stub, srv := newStubSink(t)
+	if err := SendSink(context.Background(), config.NotifySinkConfig{Type: "slack", URL: srv.URL}, samplePayload("DONE")); err != nil {
+		t.Fatalf("SendSink: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(stub.all()[0].body), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["text"] != "✅ repo/wt finished" || len(got) != 1 {
+		t.Errorf("slack body = %v", got)
+	}
+}
+
+func TestSendSink_NtfyPostsPlainTextWithHeaders(t *testing.T) {
+	stub, srv := newStubSink(t)
+	if err := SendSink(context.Background(), config.NotifySinkConfig{Type: "ntfy", URL: srv.URL}, samplePayload("WAIT")); err != nil {
+		t.Fatalf("SendSink: %v", err)
+	}
+	req := stub.all()[0]
+	if req.body != "✅ repo/wt finished" {
+		t.Errorf("body = %q", req.body)
+	}
+	if req.header.Get("Title") != "willow: repo/wt" || req.header.Get("Tags") != "hourglass" || req.header.Get("Priority") != "high" {
+		t.Errorf("headers = %v", req.header)
+	}
+}
+
+func TestSendSink_RetriesTransientFailures(t *testing.T) {
+	fastRetries(t)
+	stub, srv := newStubSink(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	if err := SendSink(context.Background(), config.NotifySinkConfig{Type: "webhook", URL: srv.URL}, samplePayload("DONE")); err != nil {
+		t.Fatalf("SendSink: %v", err)
+	}
+	if n := len(stub.all()); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+}
+
+func TestSendSink_GivesUpAfterRetries(t *testing.T) {
+	fastRetries(t)
+	stub, srv := newStubSink(t, 502, 502, 502, 502)
+	err := SendSink(context.Background(), config.NotifySinkConfig{Type: "webhook", URL: srv.URL, Retries: intPtr(1)}, samplePayload("DONE"))
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("err = %v, want 502 failure", err)
+	}
+	if n := len(stub.all()); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestSendSink_DoesNotRetryClientErrors(t *testing.T) {
+	fastRetries(t)
+	stub, srv := newStubSink(t, http.StatusNotFound)
+	if err := SendSink(context.Background(), config.NotifySinkConfig{Type: "webhook", URL: srv.URL}, samplePayload("DONE")); err == nil {
+		t.Fatal("expected error for 404")
+	}
+	if n := len(stub.all()); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+}
+
+func TestSendSink_UnknownType(t *testing.T) {
+	err := SendSink(context.Background(), config.NotifySinkConfig{Type: "pager", URL: "http://127.0.0.1"}, samplePayload("DONE"))
+	if err == nil || !strings.Contains(err.Error(), "unknown sink type") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSendSinks_AppliesStatusFilters(t *testing.T) {
+	doneStub, doneSrv := newStubSink(t)
+	busyStub, busySrv := newStubSink(t)
+	sinks := []config.NotifySinkConfig{
+		{Name: "default", Type: "webhook", URL: doneSrv.URL},
+		{Name: "busy-only", Type: "webhook", URL: busySrv.URL, Statuses: []string{"busy"}},
+	}
+
+	if err := SendSinks(context.Background(), sinks, samplePayload("DONE")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SendSinks(context.Background(), sinks, samplePayload("BUSY")); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(doneStub.all()); n != 1 {
+		t.Errorf("default sink got %d requests, want 1 (DONE only)", n)
+	}
+	if n := len(busyStub.all()); n != 1 {
+		t.Errorf("busy-only sink got %d requests, want 1 (BUSY only)", n)
+	}
+}
+
+func TestSendSinks_ReportsFailuresWithoutSkippingOthers(t *testing.T) {
+	fastRetries(t)
+	okStub, okSrv := newStubSink(t)
+	_, badSrv := newStubSink(t, http.StatusBadRequest)
+	sinks := []config.NotifySinkConfig{
+		{Name: "bad", Type: "webhook", URL: badSrv.URL},
+		{Name: "ok", Type: "slack", URL: okSrv.URL},
+	}
+	err := SendSinks(context.Background(), sinks, samplePayload("WAIT"))
+	if err == nil || !strings.Contains(err.Error(), "notify sink bad") {
+		t.Fatalf("err = %v, want failure naming sink", err)
+	}
+	if n := len(okStub.all()); n != 1 {
+		t.Errorf("ok sink got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,234 @@
+// Package ports hands each worktree a stable block of TCP ports so dev
+// servers in different worktrees don't collide. Allocations live in
+// <willow-base>/ports.json, shared by every repo.
+package ports
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+const maxPort = 65535
+
+// Allocation is the port block held by one worktree.
+type Allocation struct {
+	Repo  string `json:"repo"`
+	Path  string `json:"path"`
+	Base  int    `json:"base"`
+	Count int    `json:"count"`
+}
+
+// Ports lists the allocation's ports in order.
+func (a Allocation) Ports() []int {
+	ports := make([]int, a.Count)
+	for i := range ports {
+		ports[i] = a.Base + i
+	}
+	return ports
+}
+
+// Env exposes the block as WILLOW_PORT (the first port) and WILLOW_PORT_1
+// through WILLOW_PORT_<count-1>.
+func (a Allocation) Env() []string {
+	env := make([]string, 0, a.Count)
+	for i, p := range a.Ports() {
+		name := "WILLOW_PORT"
+		if i > 0 {
+			name += "_" + strconv.Itoa(i)
+		}
+		env = append(env, name+"="+strconv.Itoa(p))
+	}
+	return env
+}
+
+// Range formats the block as "4000" or "4000-4002".
+func (a Allocation) Range() string {
+	if a.Count <= 1 {
+		return strconv.Itoa(a.Base)
+	}
+	return fmt.Sprintf("%d-%d", a.Base, a.Base+a.Count-1)
+}
+
+func (a Allocation) overlaps(base, count int) bool {
+	return base < a.Base+a.Count && a.Base < base+count
+}
+
+// Registry is the set of allocations, sorted by port.
+type Registry struct {
+	Allocations []Allocation `json:"allocations"`
+}
+
+func FilePath() string {
+	return filepath.Join(config.WillowHome(), "ports.json")
+}
+
+// Load reads the registry. A missing or unreadable file is empty.
+func Load() *Registry {
+	r := &Registry{}
+	if data, err := os.ReadFile(FilePath()); err == nil {
+		_ = json.Unmarshal(data, r)
+	}
+	return r
+}
+
+func (r *Registry) save() error {
+	path := FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	sort.Slice(r.Allocations, func(i, j int) bool {
+		return r.Allocations[i].Base < r.Allocations[j].Base
+	})
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Update performs a locked read-modify-write cycle on the registry, so
+// worktrees created in parallel get distinct blocks.
+func Update(fn func(*Registry) error) error {
+	path := FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("open lock file: %w", err)
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("lock ports.json: %w", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	r := Load()
+	if err := fn(r); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+// Lookup returns the allocation held by the worktree at path.
+func (r *Registry) Lookup(path string) (Allocation, bool) {
+	for _, a := range r.Allocations {
+		if a.Path == path {
+			return a, true
+		}
+	}
+	return Allocation{}, false
+}
+
+// Release drops the worktree's allocation, reporting whether it had one.
+func (r *Registry) Release(path string) bool {
+	for i, a := range r.Allocations {
+		if a.Path == path {
+			r.Allocations = append(r.Allocations[:i], r.Allocations[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Move hands an allocation over to a worktree's new path.
+func (r *Registry) Move(oldPath, newPath string) {
+	for i := range r.Allocations {
+		if r.Allocations[i].Path == oldPath {
+			r.Allocations[i].Path = newPath
+		}
+	}
+}
+
+// portFree reports whether nothing is listening on port.
+var portFree = func(port int) bool {
+	l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// Allocate returns the worktree's block, reserving the first free block of
+// count ports at or above start if it has none (or one of another size).
+// Blocks start at multiples of count from start, so a worktree's ports stay
+// predictable. Allocations whose worktree no longer exists are dropped.
+func (r *Registry) Allocate(repo, path string, start, count int) (Allocation, error) {
+	kept := r.Allocations[:0]
+	for _, a := range r.Allocations {
+		if _, err := os.Stat(a.Path); err == nil || a.Path == path {
+			kept = append(kept, a)
+		}
+	}
+	r.Allocations = kept
+
+	if a, ok := r.Lookup(path); ok {
+		if a.Count == count {
+			return a, nil
+		}
+		r.Release(path)
+	}
+
+	for base := start; base+count-1 <= maxPort; base += count {
+		if r.taken(base, count) || !blockFree(base, count) {
+			continue
+		}
+		a := Allocation{Repo: repo, Path: path, Base: base, Count: count}
+		r.Allocations = append(r.Allocations, a)
+		return a, nil
+	}
+	return Allocation{}, fmt.Errorf("no free block of %d ports at or above %d", count, start)
+}
+
+func (r *Registry) taken(base, count int) bool {
+	for _, a := range r.Allocations {
+		if a.overlaps(base, count) {
+			return true
+		}
+	}
+	return false
+}
+
+func blockFree(base, count int) bool {
+	for p := base; p < base+count; p++ {
+		if !portFree(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Release frees the block held by the worktree at path, if any.
+func Release(path string) error {
+	if _, ok := Load().Lookup(path); !ok {
+		return nil
+	}
+	return Update(func(r *Registry) error {
+		r.Release(path)
+		return nil
+	})
+}
+
+// Move hands the block held by the worktree at oldPath, if any, to newPath.
+func Move(oldPath, newPath string) error {
+	if _, ok := Load().Lookup(oldPath); !ok {
+		return nil
+	}
+	return Update(func(r *Registry) error {
+		r.Move(oldPath, newPath)
+		return nil
+	})
+}
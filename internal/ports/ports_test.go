@@ -0,0 +1,91 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAllocateIsStableAndSkipsTakenBlocks(t *testing.T) {
+	busy := map[int]bool{4004: true}
+	orig := portFree
+	portFree = func(p int) bool { return !busy[p] }
+	t.Cleanup(func() { portFree = orig })
+
+	dir := t.TempDir()
+	a, b, c := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "c")
+	for _, p := range []string{a, b, c} {
+		if err := os.Mkdir(p, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Registry{}
+	first, err := r.Allocate("repo", a, 4000, 3)
+	if err != nil || first.Base != 4000 {
+		t.Fatalf("first = %+v, %v; want base 4000", first, err)
+	}
+	// 4003-4005 includes a busy port, so b gets the next block.
+	second, err := r.Allocate("repo", b, 4000, 3)
+	if err != nil || second.Base != 4006 {
+		t.Fatalf("second = %+v, %v; want base 4006", second, err)
+	}
+	if again, _ := r.Allocate("repo", a, 4000, 3); again != first {
+		t.Errorf("reallocating a = %+v, want %+v", again, first)
+	}
+
+	if !r.Release(a) {
+		t.Fatal("Release(a) = false")
+	}
+	if third, _ := r.Allocate("repo", c, 4000, 3); third.Base != 4000 {
+		t.Errorf("third = %+v, want the released block at 4000", third)
+	}
+
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	r.Allocate("repo", c, 4000, 3)
+	if _, ok := r.Lookup(b); ok {
+		t.Error("allocation for a removed worktree was kept")
+	}
+}
+
+func TestAllocationEnv(t *testing.T) {
+	a := Allocation{Base: 5000, Count: 3}
+	want := []string{"WILLOW_PORT=5000", "WILLOW_PORT_1=5001", "WILLOW_PORT_2=5002"}
+	if got := a.Env(); !slices.Equal(got, want) {
+		t.Errorf("Env = %v, want %v", got, want)
+	}
+	if a.Range() != "5000-5002" || (Allocation{Base: 5000, Count: 1}).Range() != "5000" {
+		t.Errorf("Range = %q", a.Range())
+	}
+}
+
+func TestUpdatePersistsAndMoves(t *testing.T) {
+	t.Setenv("WILLOW_BASE_DIR", t.TempDir())
+	wt := t.TempDir()
+
+	if err := Update(func(r *Registry) error {
+		_, err := r.Allocate("repo", wt, 6000, 2)
+		return err
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if a, ok := Load().Lookup(wt); !ok || a.Count != 2 {
+		t.Fatalf("Lookup after Update = %+v, %v", a, ok)
+	}
+
+	if err := Move(wt, wt+"-renamed"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if _, ok := Load().Lookup(wt + "-renamed"); !ok {
+		t.Error("allocation did not follow the move")
+	}
+	if err := Release(wt + "-renamed"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if len(Load().Allocations) != 0 {
+		t.Errorf("allocations = %+v, want none", Load().Allocations)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+)
+
+// clientTimeout bounds how long short-lived commands wait on a running
+// server before falling back to reading the status directory themselves.
+const clientTimeout = 250 * time.Millisecond
+
+// FetchSessions asks a ww serve listening on socket for its session index.
+// It fails fast when no server is running.
+func FetchSessions(socket string) ([]agent.SessionFileInfo, error) {
+	if _, err := os.Stat(socket); err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Timeout: clientTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	resp, err := client.Get("http://willow/v1/sessions")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ww serve returned %s", resp.Status)
+	}
+
+	var payload []sessionJSON
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	out := make([]agent.SessionFileInfo, 0, len(payload))
+	for _, s := range payload {
+		out = append(out, agent.SessionFileInfo{
+			RepoName:    s.Repo,
+			WorktreeDir: s.WorktreeDir,
+			Session:     s.SessionStatus,
+		})
+	}
+	return out, nil
+}
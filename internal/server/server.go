@@ -0,0 +1,435 @@
+// Package server exposes willow's worktree, session, timeline, and activity
+// log data as a local HTTP/JSON API, plus a server-sent-events stream of
+// agent status transitions.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/tmux"
+	"github.com/iamrajjoshi/willow/internal/watcher"
+)
+
+const (
+	defaultPollInterval = time.Second
+	subscriberBuffer    = 64
+)
+
+type Options struct {
+	// PollInterval controls how often session status is re-evaluated for the
+	// event stream. File changes are picked up immediately by the watcher;
+	// the interval only matters for time-based BUSY/WAIT → IDLE decay, or
+	// when the watcher is unavailable and the status directory is rescanned.
+	// Defaults to one second.
+	PollInterval time.Duration
+}
+
+// Event is a single status transition published on /v1/events.
+type Event struct {
+	Key      string       `json:"key"`
+	Repo     string       `json:"repo"`
+	Worktree string       `json:"worktree"`
+	From     agent.Status `json:"from"`
+	To       agent.Status `json:"to"`
+	Time     time.Time    `json:"time"`
+}
+
+type Server struct {
+	interval time.Duration
+
+	// watch is set for the lifetime of Serve; without it sessions are read
+	// by scanning the status directory.
+	watch *watcher.Watcher
+
+	mu     sync.Mutex
+	prev   map[string]agent.Status
+	primed bool
+	subs   map[chan Event]struct{}
+}
+
+func New(opts Options) *Server {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return &Server{
+		interval: interval,
+		subs:     make(map[chan Event]struct{}),
+	}
+}
+
+// DefaultSocketPath returns the Unix socket ww serve listens on by default.
+func DefaultSocketPath() string {
+	return filepath.Join(config.WillowHome(), "willow.sock")
+}
+
+// Handler returns the API's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+	mux.HandleFunc("GET /v1/worktrees", s.handleWorktrees)
+	mux.HandleFunc("GET /v1/sessions", s.handleSessions)
+	mux.HandleFunc("GET /v1/timeline", s.handleTimeline)
+	mux.HandleFunc("GET /v1/log", s.handleLog)
+	mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return mux
+}
+
+// loopbackOnly guards a TCP listener against DNS rebinding: a web page that
+// points its own hostname at 127.0.0.1 reaches the port with a foreign Host
+// header, and browsers add an Origin to cross-origin requests. Local tools
+// send neither. Unix sockets are out of a browser's reach and skip this.
+func loopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, fmt.Errorf("browser requests are not allowed"))
+			return
+		}
+		if !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	switch strings.Trim(host, "[]") {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// Serve handles requests on ln and publishes status transitions until ctx is
+// cancelled.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	if w, err := watcher.New(); err == nil {
+		s.watch = w
+		defer w.Close()
+	}
+	handler := s.Handler()
+	if ln.Addr().Network() == "tcp" {
+		handler = loopbackOnly(handler)
+	}
+	srv := &http.Server{Handler: handler}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.Poll()
+	go s.pollLoop(pollCtx)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, done := context.WithTimeout(context.Background(), 2*time.Second)
+		defer done()
+		s.closeSubscribers()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	var changes <-chan watcher.Event
+	if s.watch != nil {
+		changes = s.watch.Events()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+			s.Poll()
+		case <-ticker.C:
+			s.Poll()
+		}
+	}
+}
+
+// Poll re-evaluates session status and publishes any transitions since the
+// last call. The first call only records a baseline.
+func (s *Server) Poll() {
+	sessions, err := s.sessions()
+	if err != nil {
+		return
+	}
+	s.publish(agent.WorktreeStatuses(sessions))
+}
+
+func (s *Server) sessions() ([]agent.SessionFileInfo, error) {
+	if s.watch != nil {
+		return s.watch.Sessions(), nil
+	}
+	return agent.ScanAllSessions()
+}
+
+func (s *Server) publish(current map[string]agent.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.primed {
+		s.prev = current
+		s.primed = true
+		return
+	}
+	now := time.Now().UTC()
+	for _, tr := range agent.DiffStatuses(s.prev, current) {
+		repo, wt, _ := strings.Cut(tr.Key, "/")
+		ev := Event{Key: tr.Key, Repo: repo, Worktree: wt, From: tr.FromStatus, To: tr.ToStatus, Time: now}
+		for ch := range s.subs {
+			select {
+			case ch <- ev:
+			default:
+				// Slow subscriber; drop rather than stall the poller.
+			}
+		}
+	}
+	s.prev = current
+}
+
+func (s *Server) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	s.mu.Lock()
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	if _, ok := s.subs[ch]; ok {
+		delete(s.subs, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) closeSubscribers() {
+	s.mu.Lock()
+	for ch := range s.subs {
+		delete(s.subs, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+type worktreeJSON struct {
+	Repo     string       `json:"repo"`
+	Branch   string       `json:"branch"`
+	Dir      string       `json:"dir"`
+	Path     string       `json:"path"`
+	Head     string       `json:"head,omitempty"`
+	Detached bool         `json:"detached,omitempty"`
+	Status   agent.Status `json:"status"`
+	Unread   bool         `json:"unread,omitempty"`
+	Merged   bool         `json:"merged,omitempty"`
+	Stack    string       `json:"stack_prefix,omitempty"`
+}
+
+func (s *Server) handleWorktrees(w http.ResponseWriter, r *http.Request) {
+	opts := tmux.PickerBuildOptions{}
+	if s.watch != nil {
+		opts.Sessions = s.watch.Index()
+	}
+	items, err := tmux.BuildPickerItemsWithOptions(r.Context(), r.URL.Query().Get("repo"), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	out := make([]worktreeJSON, 0, len(items))
+	for _, item := range items {
+		out = append(out, worktreeJSON{
+			Repo:     item.RepoName,
+			Branch:   item.Branch,
+			Dir:      item.WtDirName,
+			Path:     item.WtPath,
+			Head:     item.Head,
+			Detached: item.Detached,
+			Status:   item.Status,
+			Unread:   item.Unread,
+			Merged:   item.Merged,
+			Stack:    item.StackPrefix,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type sessionJSON struct {
+	Repo        string `json:"repo"`
+	WorktreeDir string `json:"worktree_dir"`
+	agent.SessionStatus
+	EffectiveStatus agent.Status `json:"effective_status"`
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.sessions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	repo := r.URL.Query().Get("repo")
+	wt := r.URL.Query().Get("worktree")
+	out := make([]sessionJSON, 0, len(sessions))
+	for _, info := range sessions {
+		if (repo != "" && info.RepoName != repo) || (wt != "" && info.WorktreeDir != wt) {
+			continue
+		}
+		out = append(out, sessionJSON{
+			Repo:            info.RepoName,
+			WorktreeDir:     info.WorktreeDir,
+			SessionStatus:   info.Session,
+			EffectiveStatus: agent.EffectiveStatus(info.Session.Status, info.Session.Timestamp),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	repo, wt, session := q.Get("repo"), q.Get("worktree"), q.Get("session")
+	if repo == "" || wt == "" || session == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("repo, worktree, and session are required"))
+		return
+	}
+	// The three values are joined into a path under the status dir.
+	for _, v := range []string{repo, wt, session} {
+		if !isPathSegment(v) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid path segment %q", v))
+			return
+		}
+	}
+	since, err := parseSince(q.Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	entries, err := agent.ReadTimeline(repo, wt, session, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if entries == nil {
+		entries = []agent.TimelineEntry{}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// isPathSegment reports whether v is a single file name that cannot escape
+// the directory it is joined onto.
+func isPathSegment(v string) bool {
+	return v != "." && !strings.ContainsAny(v, `/\`) && !strings.Contains(v, "..")
+}
+
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := log.ReadOpts{Repo: q.Get("repo"), Branch: q.Get("branch")}
+	since, err := parseSince(q.Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts.Since = since
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", limit))
+			return
+		}
+		opts.Limit = n
+	}
+	events, err := log.Read(opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if events == nil {
+		events = []log.Event{}
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "event: transition\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// parseSince accepts an RFC 3339 timestamp. Empty means no lower bound.
+func parseSince(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q (use RFC 3339, e.g. 2026-01-02T15:04:05Z)", v)
+	}
+	return t, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/log"
+	"github.com/iamrajjoshi/willow/internal/testutil"
+)
+
+func getJSON(t *testing.T, ts *httptest.Server, path string, status int, v any) {
+	t.Helper()
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		t.Fatalf("GET %s status = %d, want %d", path, resp.StatusCode, status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+}
+
+func TestSessionsEndpointFilters(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt-a", "s1", agent.StatusBusy)
+	testutil.WriteSession(t, "repo", "wt-b", "s2", agent.StatusDone)
+	testutil.WriteSession(t, "other", "wt-c", "s3", agent.StatusWait)
+
+	ts := httptest.NewServer(New(Options{}).Handler())
+	defer ts.Close()
+
+	var all []sessionJSON
+	getJSON(t, ts, "/v1/sessions", http.StatusOK, &all)
+	if len(all) != 3 {
+		t.Fatalf("sessions = %d, want 3", len(all))
+	}
+
+	var filtered []sessionJSON
+	getJSON(t, ts, "/v1/sessions?repo=repo&worktree=wt-b", http.StatusOK, &filtered)
+	if len(filtered) != 1 || filtered[0].SessionID != "s2" || filtered[0].EffectiveStatus != agent.StatusDone {
+		t.Fatalf("filtered sessions = %+v", filtered)
+	}
+	if filtered[0].Repo != "repo" || filtered[0].WorktreeDir != "wt-b" || filtered[0].Harness != "claude" {
+		t.Fatalf("session location = %+v", filtered[0])
+	}
+}
+
+func TestTimelineEndpoint(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+	path := agent.TimelinePathForHarness("repo", "wt", "claude", "s1")
+	lines := `{"s":"BUSY","t":"2026-01-01T10:00:00Z"}` + "\n" + `{"s":"DONE","t":"2026-01-01T11:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(New(Options{}).Handler())
+	defer ts.Close()
+
+	var entries []agent.TimelineEntry
+	getJSON(t, ts, "/v1/timeline?repo=repo&worktree=wt&session=s1&since=2026-01-01T10:30:00Z", http.StatusOK, &entries)
+	if len(entries) != 1 || entries[0].Status != agent.StatusDone {
+		t.Fatalf("timeline = %+v, want single DONE entry", entries)
+	}
+
+	var errBody map[string]string
+	getJSON(t, ts, "/v1/timeline?repo=repo", http.StatusBadRequest, &errBody)
+	if !strings.Contains(errBody["error"], "required") {
+		t.Fatalf("missing-param error = %v", errBody)
+	}
+	getJSON(t, ts, "/v1/timeline?repo=repo&worktree=wt&session=s1&since=7d", http.StatusBadRequest, &errBody)
+	if !strings.Contains(errBody["error"], "invalid since") {
+		t.Fatalf("bad-since error = %v", errBody)
+	}
+}
+
+func TestTimelineEndpointRejectsPathTraversal(t *testing.T) {
+	testutil.SetupHome(t)
+	ts := httptest.NewServer(New(Options{}).Handler())
+	defer ts.Close()
+
+	for _, query := range []string{
+		"repo=..&worktree=wt&session=s1",
+		"repo=repo&worktree=../..&session=s1",
+		"repo=repo&worktree=wt&session=..%2F..%2Fconfig",
+		"repo=repo%2F..&worktree=wt&session=s1",
+		`repo=repo&worktree=wt%5C..&session=s1`,
+	} {
+		var errBody map[string]string
+		getJSON(t, ts, "/v1/timeline?"+query, http.StatusBadRequest, &errBody)
+		if !strings.Contains(errBody["error"], "invalid path segment") {
+			t.Errorf("%s error = %v", query, errBody)
+		}
+	}
+}
+
+func TestLogEndpoint(t *testing.T) {
+	testutil.SetupHome(t)
+	for _, e := range []log.Event{
+		{Action: "create", Repo: "repo", Branch: "a"},
+		{Action: "create", Repo: "repo", Branch: "b"},
+		{Action: "remove", Repo: "other", Branch: "c"},
+	} {
+		if err := log.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ts := httptest.NewServer(New(Options{}).Handler())
+	defer ts.Close()
+
+	var events []log.Event
+	getJSON(t, ts, "/v1/log?repo=repo&limit=1", http.StatusOK, &events)
+	if len(events) != 1 || events[0].Repo != "repo" {
+		t.Fatalf("log events = %+v", events)
+	}
+
+	var errBody map[string]string
+	getJSON(t, ts, "/v1/log?limit=abc", http.StatusBadRequest, &errBody)
+}
+
+func TestEventsStreamPublishesTransitions(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+
+	srv := New(Options{})
+	srv.Poll() // baseline: no events for pre-existing state
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("first line = %q, want connected comment", line)
+	}
+
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusDone)
+	srv.Poll()
+
+	var data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			data = strings.TrimPrefix(strings.TrimSpace(line), "data: ")
+		}
+	}
+	var ev Event
+	if err := json.Unmarshal([]byte(data), &ev); err != nil {
+		t.Fatalf("decode event %q: %v", data, err)
+	}
+	if ev.Key != "repo/wt" || ev.Repo != "repo" || ev.Worktree != "wt" || ev.From != agent.StatusBusy || ev.To != agent.StatusDone {
+		t.Fatalf("event = %+v, want repo/wt BUSY→DONE", ev)
+	}
+}
+
+func TestServePublishesWatchedWritesWithoutPolling(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// An hour-long interval means only the watcher can deliver the event.
+	srv := New(Options{PollInterval: time.Hour})
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/v1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("first line = %q, want connected comment", line)
+	}
+
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusWait)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if !strings.Contains(line, `"to":"WAIT"`) {
+				t.Fatalf("event = %q, want transition to WAIT", line)
+			}
+			break
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("Serve returned %v", err)
+	}
+}
+
+func TestServeTCPRejectsForeignHostsAndBrowsers(t *testing.T) {
+	testutil.SetupHome(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- New(Options{PollInterval: time.Hour}).Serve(ctx, ln) }()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	for _, tc := range []struct {
+		host, origin string
+		want         int
+	}{
+		{host: ln.Addr().String(), want: http.StatusOK},
+		{host: "localhost:" + port, want: http.StatusOK},
+		{host: "[::1]:" + port, want: http.StatusOK},
+		{host: "attacker.example:" + port, want: http.StatusForbidden},
+		{host: "localhost:" + port, origin: "http://attacker.example", want: http.StatusForbidden},
+	} {
+		req, err := http.NewRequest("GET", "http://"+ln.Addr().String()+"/v1/log", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = tc.host
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("Host %q Origin %q status = %d, want %d", tc.host, tc.origin, resp.StatusCode, tc.want)
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("Serve returned %v", err)
+	}
+}
+
+func TestFetchSessionsOverSocket(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+
+	if _, err := FetchSessions(DefaultSocketPath()); err == nil {
+		t.Fatal("FetchSessions with no server should fail")
+	}
+
+	ln, err := net.Listen("unix", DefaultSocketPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewUnstartedServer(New(Options{}).Handler())
+	ts.Listener = ln
+	ts.Start()
+	defer ts.Close()
+
+	sessions, err := FetchSessions(DefaultSocketPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].RepoName != "repo" || sessions[0].WorktreeDir != "wt" || sessions[0].Session.Status != agent.StatusBusy {
+		t.Fatalf("sessions = %+v", sessions)
+	}
+}
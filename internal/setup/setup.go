@@ -0,0 +1,243 @@
+// Package setup runs a worktree's setup steps, recording their output and
+// the state of their input files in the worktree's git admin dir.
+package setup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+	"github.com/iamrajjoshi/willow/internal/localfiles"
+	"github.com/iamrajjoshi/willow/internal/parallel"
+)
+
+// Files written to a Runner's StateDir.
+const (
+	LogFile   = "willow-setup.log"
+	StateFile = "willow-setup.json"
+)
+
+// State is what the last setup run left behind.
+type State struct {
+	LastRun time.Time            `json:"lastRun"`
+	Failed  string               `json:"failed,omitempty"`
+	Steps   map[string]StepState `json:"steps,omitempty"`
+}
+
+// StepState records the last successful run of a step, keyed by command.
+type StepState struct {
+	Inputs string    `json:"inputs,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// LoadState reads the state in dir. A missing or unreadable file is an
+// empty state.
+func LoadState(dir string) *State {
+	s := &State{}
+	if data, err := os.ReadFile(filepath.Join(dir, StateFile)); err == nil {
+		_ = json.Unmarshal(data, s)
+	}
+	if s.Steps == nil {
+		s.Steps = map[string]StepState{}
+	}
+	return s
+}
+
+func (s *State) save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, StateFile), append(data, '\n'), 0o644)
+}
+
+// Runner runs setup steps in Dir.
+type Runner struct {
+	Dir      string
+	StateDir string // holds LogFile and StateFile; empty disables both
+	Env      []string
+	Stdout   io.Writer
+	Stderr   io.Writer
+	Force    bool             // run steps even when their inputs are unchanged
+	Progress func(msg string) // called before each step
+}
+
+type stepResult struct {
+	step    config.SetupStep
+	inputs  string
+	skipped bool
+	output  []byte
+	elapsed time.Duration
+	err     error
+}
+
+// Run runs steps in order, running consecutive steps that share a group in
+// parallel. It stops after the first batch with a failure. Serial steps
+// stream to Stdout and Stderr; parallel steps are buffered and only shown
+// when they fail. Every step's output goes to the log.
+func (r *Runner) Run(steps []config.SetupStep) error {
+	state := &State{Steps: map[string]StepState{}}
+	logw := io.Discard
+	if r.StateDir != "" {
+		state = LoadState(r.StateDir)
+		f, err := os.Create(filepath.Join(r.StateDir, LogFile))
+		if err != nil {
+			return fmt.Errorf("failed to create setup log: %w", err)
+		}
+		defer f.Close()
+		logw = f
+	}
+	start := time.Now()
+	fmt.Fprintf(logw, "# setup in %s at %s\n", r.Dir, start.Format(time.RFC3339))
+
+	var runErr error
+	state.Failed = ""
+	for _, batch := range Batches(steps) {
+		results := parallel.Map(batch, func(_ int, s config.SetupStep) stepResult {
+			return r.runStep(s, state, logw, len(batch) > 1)
+		})
+		for _, res := range results {
+			if res.skipped {
+				fmt.Fprintf(logw, "==> %s\n<== skipped: inputs unchanged\n", res.step.Run)
+				continue
+			}
+			if len(batch) > 1 {
+				fmt.Fprintf(logw, "==> %s\n", res.step.Run)
+				logw.Write(res.output)
+			}
+			if res.err != nil {
+				fmt.Fprintf(logw, "<== failed after %s: %v\n", res.elapsed.Round(time.Millisecond), res.err)
+				if len(batch) > 1 {
+					r.stderr().Write(res.output)
+				}
+				if runErr == nil {
+					runErr = fmt.Errorf("hook failed: %s: %w", res.step.Run, res.err)
+					state.Failed = res.step.Run
+				}
+				continue
+			}
+			fmt.Fprintf(logw, "<== ok in %s\n", res.elapsed.Round(time.Millisecond))
+			state.Steps[res.step.Run] = StepState{Inputs: res.inputs, At: start}
+		}
+		if runErr != nil {
+			break
+		}
+	}
+
+	if r.StateDir != "" {
+		state.LastRun = start
+		if err := state.save(r.StateDir); err != nil && runErr == nil {
+			return fmt.Errorf("failed to save setup state: %w", err)
+		}
+	}
+	return runErr
+}
+
+func (r *Runner) runStep(s config.SetupStep, state *State, logw io.Writer, buffered bool) stepResult {
+	res := stepResult{step: s}
+	if len(s.Inputs) > 0 {
+		res.inputs = HashInputs(r.Dir, s.Inputs)
+		if prev, ok := state.Steps[s.Run]; ok && !r.Force && prev.Inputs == res.inputs {
+			res.skipped = true
+			r.progress(fmt.Sprintf("  ↷ %s (inputs unchanged)", s.Run))
+			return res
+		}
+	}
+
+	r.progress(fmt.Sprintf("  → %s", s))
+	sh := exec.Command("sh", "-c", s.Run)
+	sh.Dir = r.Dir
+	if len(r.Env) > 0 {
+		sh.Env = append(os.Environ(), r.Env...)
+	}
+	var buf bytes.Buffer
+	if buffered {
+		sh.Stdout, sh.Stderr = &buf, &buf
+	} else {
+		fmt.Fprintf(logw, "==> %s\n", s.Run)
+		sh.Stdout = io.MultiWriter(r.stdout(), logw)
+		sh.Stderr = io.MultiWriter(r.stderr(), logw)
+	}
+	began := time.Now()
+	res.err = sh.Run()
+	res.elapsed = time.Since(began)
+	res.output = buf.Bytes()
+	return res
+}
+
+func (r *Runner) progress(msg string) {
+	if r.Progress != nil {
+		r.Progress(msg)
+	}
+}
+
+func (r *Runner) stdout() io.Writer {
+	if r.Stdout != nil {
+		return r.Stdout
+	}
+	return io.Discard
+}
+
+func (r *Runner) stderr() io.Writer {
+	if r.Stderr != nil {
+		return r.Stderr
+	}
+	return io.Discard
+}
+
+// Batches splits steps into runs of consecutive steps that share a
+// non-empty group. Ungrouped steps are batches of one.
+func Batches(steps []config.SetupStep) [][]config.SetupStep {
+	var batches [][]config.SetupStep
+	for i, s := range steps {
+		if i > 0 && s.Group != "" && s.Group == steps[i-1].Group {
+			batches[len(batches)-1] = append(batches[len(batches)-1], s)
+			continue
+		}
+		batches = append(batches, []config.SetupStep{s})
+	}
+	return batches
+}
+
+// HashInputs hashes the paths and contents of the files under dir matched
+// by patterns. Directories are hashed file by file.
+func HashInputs(dir string, patterns []string) string {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := localfiles.Match(dir, pattern)
+		if err != nil {
+			continue
+		}
+		for _, rel := range matches {
+			_ = filepath.WalkDir(filepath.Join(dir, rel), func(path string, d fs.DirEntry, err error) error {
+				if err == nil && d.Type().IsRegular() {
+					files = append(files, path)
+				}
+				return nil
+			})
+		}
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "%s\x00", rel)
+		if f, err := os.Open(path); err == nil {
+			_, _ = io.Copy(h, f)
+			f.Close()
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,112 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+func TestBatchesGroupsConsecutiveSteps(t *testing.T) {
+	steps := []config.SetupStep{
+		{Run: "a"},
+		{Run: "b", Group: "deps"},
+		{Run: "c", Group: "deps"},
+		{Run: "d"},
+		{Run: "e", Group: "deps"},
+	}
+	var got []string
+	for _, b := range Batches(steps) {
+		var runs []string
+		for _, s := range b {
+			runs = append(runs, s.Run)
+		}
+		got = append(got, strings.Join(runs, "+"))
+	}
+	if strings.Join(got, " ") != "a b+c d e" {
+		t.Errorf("batches = %v, want [a b+c d e]", got)
+	}
+}
+
+func TestRunLogsAndSkipsUnchangedInputs(t *testing.T) {
+	dir, stateDir := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	steps := []config.SetupStep{
+		{Run: "echo install >> runs", Inputs: []string{"package-lock.json"}},
+		{Run: "echo one >> runs", Group: "g"},
+		{Run: "echo two >> runs", Group: "g"},
+	}
+	r := &Runner{Dir: dir, StateDir: stateDir}
+	runs := func() string {
+		data, _ := os.ReadFile(filepath.Join(dir, "runs"))
+		return string(data)
+	}
+
+	if err := r.Run(steps); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if got := runs(); strings.Count(got, "install") != 1 || !strings.Contains(got, "one") || !strings.Contains(got, "two") {
+		t.Fatalf("runs after first Run = %q", got)
+	}
+
+	if err := r.Run(steps); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if got := runs(); strings.Count(got, "install") != 1 {
+		t.Errorf("install reran with unchanged inputs: %q", got)
+	}
+	logData, _ := os.ReadFile(filepath.Join(stateDir, LogFile))
+	if !strings.Contains(string(logData), "skipped: inputs unchanged") {
+		t.Errorf("log missing skip:\n%s", logData)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run(steps); err != nil {
+		t.Fatalf("third Run: %v", err)
+	}
+	if got := runs(); strings.Count(got, "install") != 2 {
+		t.Errorf("install did not rerun after inputs changed: %q", got)
+	}
+
+	r.Force = true
+	if err := r.Run(steps[:1]); err != nil {
+		t.Fatalf("forced Run: %v", err)
+	}
+	if got := runs(); strings.Count(got, "install") != 3 {
+		t.Errorf("Force did not rerun install: %q", got)
+	}
+}
+
+func TestRunStopsAfterFailedBatch(t *testing.T) {
+	dir, stateDir := t.TempDir(), t.TempDir()
+	steps := []config.SetupStep{
+		{Run: "echo boom; exit 3", Group: "g"},
+		{Run: "echo fine", Group: "g"},
+		{Run: "touch never"},
+	}
+	err := (&Runner{Dir: dir, StateDir: stateDir}).Run(steps)
+	if err == nil || !strings.Contains(err.Error(), "hook failed: echo boom; exit 3") {
+		t.Fatalf("Run error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); err == nil {
+		t.Error("step after the failed batch ran")
+	}
+
+	state := LoadState(stateDir)
+	if state.Failed != "echo boom; exit 3" {
+		t.Errorf("Failed = %q", state.Failed)
+	}
+	if _, ok := state.Steps["echo fine"]; !ok {
+		t.Error("successful parallel step not recorded")
+	}
+	logData, _ := os.ReadFile(filepath.Join(stateDir, LogFile))
+	if log := string(logData); !strings.Contains(log, "boom") || !strings.Contains(log, "failed after") || !strings.Contains(log, "fine") {
+		t.Errorf("log = %q", log)
+	}
+}
@@ -0,0 +1,95 @@
+package stack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Restack step states.
+const (
+	StepPending  = "pending"
+	StepDone     = "done"
+	StepConflict = "conflict"
+	StepSkipped  = "skipped"
+)
+
+// RestackPlan is the persisted state of an in-progress restack. It is written
+// to restack.json next to branches.json before any branch is rebased, so a
+// conflicted restack can be resumed after the user resolves it.
+type RestackPlan struct {
+	Started time.Time     `json:"started"`
+	Steps   []RestackStep `json:"steps"`
+}
+
+// RestackStep rebases Branch onto Onto, replaying only the commits after
+// OldBase — the parent tip recorded when the plan was created.
+type RestackStep struct {
+	Branch  string `json:"branch"`
+	Parent  string `json:"parent"`
+	Onto    string `json:"onto"`
+	OldBase string `json:"oldBase"`
+	State   string `json:"state"`
+}
+
+func restackPath(bareDir string) string {
+	return filepath.Join(bareDir, "restack.json")
+}
+
+// LoadRestackPlan reads restack.json. Returns nil with no error when no
+// restack is in progress.
+func LoadRestackPlan(bareDir string) (*RestackPlan, error) {
+	data, err := os.ReadFile(restackPath(bareDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var p RestackPlan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse restack.json: %w", err)
+	}
+	return &p, nil
+}
+
+// Save writes the plan to restack.json.
+func (p *RestackPlan) Save(bareDir string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicWrite(restackPath(bareDir), append(data, '\n'))
+}
+
+// ClearRestackPlan removes restack.json. A missing file is not an error.
+func ClearRestackPlan(bareDir string) error {
+	err := os.Remove(restackPath(bareDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Step returns the step for branch, or nil if the branch is not in the plan.
+func (p *RestackPlan) Step(branch string) *RestackStep {
+	for i := range p.Steps {
+		if p.Steps[i].Branch == branch {
+			return &p.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Finished reports whether every step is done or skipped.
+func (p *RestackPlan) Finished() bool {
+	for _, step := range p.Steps {
+		if step.State != StepDone && step.State != StepSkipped {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package stack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestackPlanRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	if p, err := LoadRestackPlan(dir); err != nil || p != nil {
+		t.Fatalf("LoadRestackPlan() on empty dir = %+v, %v; want nil, nil", p, err)
+	}
+
+	plan := &RestackPlan{
+		Started: time.Now().UTC().Truncate(time.Second),
+		Steps: []RestackStep{
+			{Branch: "a", Parent: "main", Onto: "origin/main", OldBase: "abc", State: StepDone},
+			{Branch: "b", Parent: "a", Onto: "a", OldBase: "def", State: StepConflict},
+		},
+	}
+	if err := plan.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadRestackPlan(dir)
+	if err != nil {
+		t.Fatalf("LoadRestackPlan() error = %v", err)
+	}
+	if !loaded.Started.Equal(plan.Started) || len(loaded.Steps) != 2 {
+		t.Fatalf("loaded plan = %+v, want %+v", loaded, plan)
+	}
+	if step := loaded.Step("b"); step == nil || step.OldBase != "def" || step.State != StepConflict {
+		t.Fatalf("Step(b) = %+v", step)
+	}
+	if loaded.Step("missing") != nil {
+		t.Fatal("Step(missing) should be nil")
+	}
+	if loaded.Finished() {
+		t.Fatal("plan with a conflicted step should not be finished")
+	}
+
+	loaded.Step("b").State = StepSkipped
+	if !loaded.Finished() {
+		t.Fatal("plan with only done/skipped steps should be finished")
+	}
+
+	if err := ClearRestackPlan(dir); err != nil {
+		t.Fatalf("ClearRestackPlan() error = %v", err)
+	}
+	if err := ClearRestackPlan(dir); err != nil {
+		t.Fatalf("ClearRestackPlan() twice error = %v", err)
+	}
+	if p, _ := LoadRestackPlan(dir); p != nil {
+		t.Fatalf("plan should be gone after clear, got %+v", p)
+	}
+}
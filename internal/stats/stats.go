@@ -0,0 +1,289 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/log"
+)
+
+// Session is one agent session's status timeline, live or archived.
+type Session struct {
+	Repo     string
+	Worktree string
+	Harness  string
+	Timeline []agent.TimelineEntry
+	// End closes the last timeline interval: when the session ended, or
+	// now for a live one.
+	End time.Time
+}
+
+// Start is when the session first reported a status.
+func (s Session) Start() time.Time {
+	if len(s.Timeline) == 0 {
+		return s.End
+	}
+	return s.Timeline[0].Time
+}
+
+// Lifetime is a worktree's span from ww new to ww rm, taken from the
+// activity log. Harness is the agent it was dispatched with, if any.
+type Lifetime struct {
+	Repo    string
+	Branch  string
+	Harness string
+	Created time.Time
+	Removed time.Time
+}
+
+// Groupings for ww stats --by.
+const (
+	GroupRepo    = "repo"
+	GroupHarness = "harness"
+)
+
+// Summary aggregates agent activity for one group key. Durations are in
+// seconds so JSON and CSV consumers don't have to parse Go durations.
+type Summary struct {
+	Key            string  `json:"key"`
+	Sessions       int     `json:"sessions"`
+	SessionsPerDay float64 `json:"sessions_per_day"`
+	BusySeconds    float64 `json:"busy_seconds"`
+	WaitSeconds    float64 `json:"wait_seconds"`
+	// Responses counts DONE → BUSY transitions: a finished agent getting
+	// its next prompt. MeanResponseSeconds is how long that took on average.
+	Responses           int     `json:"responses"`
+	MeanResponseSeconds float64 `json:"mean_response_seconds"`
+	// Worktrees counts worktrees removed in the window, and
+	// MeanLifetimeSeconds is their average age at removal.
+	Worktrees           int     `json:"worktrees"`
+	MeanLifetimeSeconds float64 `json:"mean_lifetime_seconds"`
+}
+
+// Input is what Summarize reports on: sessions, worktree lifetimes, and
+// the window [Since, Now].
+type Input struct {
+	Sessions  []Session
+	Lifetimes []Lifetime
+	Since     time.Time
+	Now       time.Time
+}
+
+// Days is the window's length in days, at least one.
+func (in Input) Days() float64 {
+	return math.Max(1, in.Now.Sub(in.Since).Hours()/24)
+}
+
+type accumulator struct {
+	sessions      int
+	busy, wait    time.Duration
+	responses     int
+	responseTotal time.Duration
+	worktrees     int
+	lifetimeTotal time.Duration
+}
+
+func (a *accumulator) addSession(s Session, since time.Time) {
+	if !s.Start().Before(since) {
+		a.sessions++
+	}
+	for i, e := range s.Timeline {
+		end := s.End
+		if i+1 < len(s.Timeline) {
+			end = s.Timeline[i+1].Time
+		}
+		// Only the part of each interval inside the window counts.
+		start := e.Time
+		if start.Before(since) {
+			start = since
+		}
+		d := max(0, end.Sub(start))
+		switch e.Status {
+		case agent.StatusBusy:
+			a.busy += d
+		case agent.StatusWait:
+			a.wait += d
+		case agent.StatusDone:
+			if i+1 < len(s.Timeline) && s.Timeline[i+1].Status == agent.StatusBusy && !end.Before(since) {
+				a.responses++
+				a.responseTotal += end.Sub(e.Time)
+			}
+		}
+	}
+}
+
+func (a *accumulator) addLifetime(l Lifetime) {
+	a.worktrees++
+	a.lifetimeTotal += l.Removed.Sub(l.Created)
+}
+
+func (a *accumulator) summary(key string, days float64) Summary {
+	s := Summary{
+		Key:            key,
+		Sessions:       a.sessions,
+		SessionsPerDay: float64(a.sessions) / days,
+		BusySeconds:    a.busy.Seconds(),
+		WaitSeconds:    a.wait.Seconds(),
+		Responses:      a.responses,
+		Worktrees:      a.worktrees,
+	}
+	if a.responses > 0 {
+		s.MeanResponseSeconds = a.responseTotal.Seconds() / float64(a.responses)
+	}
+	if a.worktrees > 0 {
+		s.MeanLifetimeSeconds = a.lifetimeTotal.Seconds() / float64(a.worktrees)
+	}
+	return s
+}
+
+// Summarize groups activity by GroupRepo or GroupHarness, sorted by busy time,
+// highest first. Worktree lifetimes count toward the harness they were
+// dispatched with; worktrees created without ww dispatch have none.
+func Summarize(in Input, by string) ([]Summary, error) {
+	var sessionKey func(Session) string
+	var lifetimeKey func(Lifetime) string
+	switch by {
+	case GroupRepo:
+		sessionKey = func(s Session) string { return s.Repo }
+		lifetimeKey = func(l Lifetime) string { return l.Repo }
+	case GroupHarness:
+		sessionKey = func(s Session) string { return s.Harness }
+		lifetimeKey = func(l Lifetime) string { return l.Harness }
+	default:
+		return nil, fmt.Errorf("unknown grouping %q (use %s or %s)", by, GroupRepo, GroupHarness)
+	}
+
+	groups := map[string]*accumulator{}
+	get := func(key string) *accumulator {
+		if groups[key] == nil {
+			groups[key] = &accumulator{}
+		}
+		return groups[key]
+	}
+	for _, s := range in.Sessions {
+		get(sessionKey(s)).addSession(s, in.Since)
+	}
+	for _, l := range in.Lifetimes {
+		if key := lifetimeKey(l); key != "" {
+			get(key).addLifetime(l)
+		}
+	}
+
+	out := make([]Summary, 0, len(groups))
+	for key, a := range groups {
+		out = append(out, a.summary(key, in.Days()))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].BusySeconds != out[j].BusySeconds {
+			return out[i].BusySeconds > out[j].BusySeconds
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, nil
+}
+
+// Total summarizes every session and lifetime as one group.
+func Total(in Input) Summary {
+	var a accumulator
+	for _, s := range in.Sessions {
+		a.addSession(s, in.Since)
+	}
+	for _, l := range in.Lifetimes {
+		a.addLifetime(l)
+	}
+	return a.summary("total", in.Days())
+}
+
+// Lifetimes pairs create and remove events, following worktrees through
+// renames and promotions. events must be oldest first. Only worktrees
+// removed at or after since are returned.
+func Lifetimes(events []log.Event, since time.Time) []Lifetime {
+	type open struct {
+		created time.Time
+		harness string
+	}
+	live := map[string]open{}
+	var out []Lifetime
+	for _, e := range events {
+		key := e.Repo + "/" + e.Branch
+		switch e.Action {
+		case "create", "restore":
+			if _, ok := live[key]; !ok {
+				live[key] = open{created: e.Timestamp}
+			}
+		case "dispatch":
+			o, ok := live[key]
+			if !ok {
+				o = open{created: e.Timestamp}
+			}
+			o.harness = e.Metadata["agent"]
+			live[key] = o
+		case "rename", "promote":
+			from := e.Repo + "/" + e.Metadata["from"]
+			if o, ok := live[from]; ok {
+				delete(live, from)
+				live[key] = o
+			}
+		case "remove":
+			o, ok := live[key]
+			if !ok {
+				continue
+			}
+			delete(live, key)
+			if !e.Timestamp.Before(since) {
+				out = append(out, Lifetime{Repo: e.Repo, Branch: e.Branch, Harness: o.harness, Created: o.created, Removed: e.Timestamp})
+			}
+		}
+	}
+	return out
+}
+
+type LoadOpts struct {
+	Repo  string
+	Since time.Time
+	Now   time.Time
+}
+
+// Load gathers archived and live session timelines and the activity log
+// into an Input for Summarize.
+func Load(opts LoadOpts) (Input, error) {
+	in := Input{Since: opts.Since, Now: opts.Now}
+
+	archived, err := agent.ReadArchive(agent.ArchiveReadOpts{Repo: opts.Repo, Since: opts.Since})
+	if err != nil {
+		return in, fmt.Errorf("read session archive: %w", err)
+	}
+	for _, a := range archived {
+		in.Sessions = append(in.Sessions, Session{Repo: a.Repo, Worktree: a.Worktree, Harness: a.Harness, Timeline: a.Timeline, End: a.EndTime})
+	}
+
+	live, err := agent.ScanAllSessions()
+	if err != nil {
+		return in, fmt.Errorf("scan sessions: %w", err)
+	}
+	for _, si := range live {
+		if opts.Repo != "" && si.RepoName != opts.Repo {
+			continue
+		}
+		timeline, _ := agent.ReadTimeline(si.RepoName, si.WorktreeDir, si.Session.SessionID, time.Time{})
+		end := opts.Now
+		// A stale BUSY or WAIT session is presumed dead since its last hook.
+		if agent.EffectiveStatus(si.Session.Status, si.Session.Timestamp) != si.Session.Status {
+			end = si.Session.Timestamp
+		}
+		in.Sessions = append(in.Sessions, Session{Repo: si.RepoName, Worktree: si.WorktreeDir, Harness: si.Session.Harness, Timeline: timeline, End: end})
+	}
+
+	events, err := log.Read(log.ReadOpts{Repo: opts.Repo, Limit: math.MaxInt})
+	if err != nil {
+		return in, fmt.Errorf("read log: %w", err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	in.Lifetimes = Lifetimes(events, opts.Since)
+	return in, nil
+}
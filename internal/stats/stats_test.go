@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/log"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func at(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+
+func TestSummarizeBusyWaitAndResponseTimes(t *testing.T) {
+	in := Input{
+		Since: at(0),
+		Now:   at(2 * 24 * 60),
+		Sessions: []Session{
+			{
+				Repo: "api", Harness: "claude", End: at(100),
+				Timeline: []agent.TimelineEntry{
+					{Status: agent.StatusBusy, Time: at(10)},
+					{Status: agent.StatusWait, Time: at(30)},
+					{Status: agent.StatusBusy, Time: at(35)},
+					{Status: agent.StatusDone, Time: at(50)},
+					{Status: agent.StatusBusy, Time: at(60)},
+					{Status: agent.StatusDone, Time: at(80)},
+				},
+			},
+			{
+				// Started before the window: busy time is clipped and the
+				// session isn't counted as started in it.
+				Repo: "web", Harness: "codex", End: at(20),
+				Timeline: []agent.TimelineEntry{
+					{Status: agent.StatusBusy, Time: at(-30)},
+					{Status: agent.StatusDone, Time: at(20)},
+				},
+			},
+		},
+	}
+
+	got, err := Summarize(in, GroupRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Key != "api" || got[1].Key != "web" {
+		t.Fatalf("Summarize keys = %+v", got)
+	}
+	api := got[0]
+	if api.Sessions != 1 || api.SessionsPerDay != 0.5 {
+		t.Errorf("api sessions = %d (%v/day), want 1 (0.5/day)", api.Sessions, api.SessionsPerDay)
+	}
+	if api.BusySeconds != (20+15+20)*60 || api.WaitSeconds != 5*60 {
+		t.Errorf("api busy/wait = %v/%v", api.BusySeconds, api.WaitSeconds)
+	}
+	if api.Responses != 1 || api.MeanResponseSeconds != 10*60 {
+		t.Errorf("api responses = %d, mean %v", api.Responses, api.MeanResponseSeconds)
+	}
+	web := got[1]
+	if web.Sessions != 0 || web.BusySeconds != 20*60 {
+		t.Errorf("web = %+v, want 0 sessions and 20m busy", web)
+	}
+
+	if total := Total(in); total.BusySeconds != (55+20)*60 || total.Sessions != 1 {
+		t.Errorf("Total = %+v", total)
+	}
+	if _, err := Summarize(in, "model"); err == nil {
+		t.Error("Summarize by model should fail")
+	}
+}
+
+func TestLifetimesFollowRenamesAndDispatch(t *testing.T) {
+	events := []log.Event{
+		{Action: "create", Repo: "api", Branch: "auth", Timestamp: at(0)},
+		{Action: "dispatch", Repo: "api", Branch: "auth", Timestamp: at(1), Metadata: map[string]string{"agent": "claude"}},
+		{Action: "rename", Repo: "api", Branch: "auth-v2", Timestamp: at(30), Metadata: map[string]string{"from": "auth"}},
+		{Action: "create", Repo: "api", Branch: "old", Timestamp: at(-120)},
+		{Action: "remove", Repo: "api", Branch: "old", Timestamp: at(-60)},
+		{Action: "create", Repo: "web", Branch: "nav", Timestamp: at(10)},
+		{Action: "remove", Repo: "api", Branch: "auth-v2", Timestamp: at(90)},
+		{Action: "remove", Repo: "web", Branch: "gone", Timestamp: at(95)},
+	}
+
+	got := Lifetimes(events, at(0))
+	if len(got) != 1 {
+		t.Fatalf("Lifetimes = %+v, want just api/auth-v2", got)
+	}
+	l := got[0]
+	if l.Branch != "auth-v2" || l.Harness != "claude" || l.Removed.Sub(l.Created) != 90*time.Minute {
+		t.Errorf("lifetime = %+v", l)
+	}
+
+	in := Input{Since: at(0), Now: at(100), Lifetimes: got}
+	byHarness, err := Summarize(in, GroupHarness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byHarness) != 1 || byHarness[0].Key != "claude" || byHarness[0].Worktrees != 1 || byHarness[0].MeanLifetimeSeconds != 90*60 {
+		t.Errorf("Summarize by harness = %+v", byHarness)
+	}
+}
@@ -0,0 +1,37 @@
+// Package testutil holds fixtures shared by tests across packages.
+package testutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+)
+
+// SetupHome points HOME at a fresh temp dir so willow state stays isolated.
+func SetupHome(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("WILLOW_BASE_DIR", "")
+}
+
+// WriteSession writes a claude session status file for repo/wt.
+func WriteSession(t *testing.T, repo, wt, sessionID string, status agent.Status) {
+	t.Helper()
+	path := agent.SessionPath(repo, wt, "claude", sessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(agent.SessionStatus{SessionID: sessionID, Status: status, Timestamp: time.Now()})
+	// Write via rename like the hook handler so watchers never see partial files.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
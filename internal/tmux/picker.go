@@ -57,6 +57,9 @@ type pickerGroup struct {
 
 type PickerBuildOptions struct {
 	RefreshGitHubMerged bool
+	// Sessions supplies agent sessions from an existing snapshot. When nil,
+	// each worktree's status directory is read.
+	Sessions *agent.SessionIndex
 }
 
 func BuildPickerItems(ctx context.Context, repoFilter string) ([]PickerItem, error) {
@@ -132,9 +135,9 @@ func buildPickerItemsForRepo(ctx context.Context, repoName string, opts PickerBu
 	if err != nil {
 		candidates = nil
 	}
-	candidatesByBranch := make(map[string]cleanup.Candidate, len(candidates))
+	candidatesByPath := make(map[string]cleanup.Candidate, len(candidates))
 	for _, candidate := range candidates {
-		candidatesByBranch[candidate.Branch] = candidate
+		candidatesByPath[candidate.Path] = candidate
 	}
 
 	done = trace.Span(ctx, "per-wt-loop/"+repoName)
@@ -143,9 +146,9 @@ func buildPickerItemsForRepo(ctx context.Context, repoName string, opts PickerBu
 			continue
 		}
 		wtDir := filepath.Base(wt.Path)
-		sessions := agent.ReadAllSessions(repoName, wtDir)
+		sessions := opts.Sessions.ReadAllSessions(repoName, wtDir)
 		ws := agent.AggregateStatus(sessions)
-		candidate := candidatesByBranch[wt.Branch]
+		candidate := candidatesByPath[wt.Path]
 		result.items = append(result.items, PickerItem{
 			RepoName:        repoName,
 			Branch:          wt.Branch,
@@ -500,6 +503,8 @@ func staleTags(item PickerItem) []string {
 			tags = append(tags, "merged")
 		case cleanup.ReasonGoneUpstream:
 			tags = append(tags, "gone")
+		case cleanup.ReasonInactive, cleanup.ReasonStaleDetached:
+			tags = append(tags, "idle")
 		}
 	}
 	return tags
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 
 	"github.com/iamrajjoshi/willow/internal/config"
 )
@@ -49,12 +50,58 @@ func SendKeys(target string, keys ...string) error {
 	return err
 }
 
+// Pane is a tmux pane with its working directory and foreground command.
+type Pane struct {
+	ID      string
+	Session string
+	Path    string
+	Command string
+}
+
+// ListPanes returns every pane on the server, or nil if tmux isn't running.
+func ListPanes() []Pane {
+	out, err := run("list-panes", "-a", "-F", "#{pane_id}\t#{session_name}\t#{pane_current_path}\t#{pane_current_command}")
+	if err != nil || out == "" {
+		return nil
+	}
+	var panes []Pane
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		panes = append(panes, Pane{ID: fields[0], Session: fields[1], Path: fields[2], Command: fields[3]})
+	}
+	return panes
+}
+
+// pasteSeq numbers paste buffers within a process.
+var pasteSeq atomic.Int64
+
+// PasteText pastes text into target as a bracketed paste, so a multi-line
+// message arrives as one input rather than being submitted line by line.
+// Each call uses its own buffer so concurrent pastes can't swap text.
+func PasteText(target, text string) error {
+	buffer := fmt.Sprintf("willow-paste-%d-%d", os.Getpid(), pasteSeq.Add(1))
+	if _, err := run("set-buffer", "-b", buffer, "--", text); err != nil {
+		return err
+	}
+	_, err := run("paste-buffer", "-p", "-d", "-b", buffer, "-t", target)
+	return err
+}
+
 // NewSession creates a tmux session and applies layout commands.
 // Layout entries are raw tmux subcommands (e.g. "split-window -h").
 // The session target (-t) and working directory (-c) are auto-injected.
 // After layout setup, each pane receives its configured command (by index).
-func NewSession(name, dir string, layout []string, panes []config.PaneConfig) error {
-	if _, err := run("new-session", "-d", "-s", name, "-c", dir); err != nil {
+// env entries (KEY=VALUE) are set in the session environment, so every pane
+// inherits them.
+func NewSession(name, dir string, env, layout []string, panes []config.PaneConfig) error {
+	args := []string{"new-session", "-d", "-s", name, "-c", dir}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	if _, err := run(args...); err != nil {
 		return err
 	}
 
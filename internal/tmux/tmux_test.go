@@ -3,6 +3,7 @@ package tmux
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -19,7 +20,7 @@ func installFakeTmux(t *testing.T) string {
 		"  list-sessions) printf 'alpha\\nbeta\\n' ;;\n" +
 		"  display-message) printf 'current-session\\n' ;;\n" +
 		"  capture-pane) printf 'pane output\\n' ;;\n" +
-		"  list-panes) printf '%%1\\n%%2\\n' ;;\n" +
+		"  list-panes) if [ \"$2\" = \"-a\" ]; then printf '%%1\\talpha\\t/work/a\\tclaude\\nbad line\\n'; else printf '%%1\\n%%2\\n'; fi ;;\n" +
 		"  has-session) [ \"$3\" = \"exists\" ] ;;\n" +
 		"  *) exit 0 ;;\n" +
 		"esac\n"
@@ -161,6 +162,15 @@ func TestTmuxCommandWrappersUseTmuxCLI(t *testing.T) {
 	if got, err := CapturePane("target"); err != nil || got != "pane output" {
 		t.Fatalf("CapturePane() = %q, %v; want pane output", got, err)
 	}
+	if panes := ListPanes(); len(panes) != 1 || panes[0] != (Pane{ID: "%1", Session: "alpha", Path: "/work/a", Command: "claude"}) {
+		t.Fatalf("ListPanes() = %+v, want one claude pane", panes)
+	}
+	if err := PasteText("target", "-line one\nline two"); err != nil {
+		t.Fatalf("PasteText: %v", err)
+	}
+	if err := PasteText("other", "second"); err != nil {
+		t.Fatalf("PasteText: %v", err)
+	}
 
 	logData, err := os.ReadFile(logPath)
 	if err != nil {
@@ -179,12 +189,24 @@ func TestTmuxCommandWrappersUseTmuxCLI(t *testing.T) {
 			t.Fatalf("tmux log missing %q:\n%s", want, logText)
 		}
 	}
+
+	// Each paste gets its own buffer, set and pasted under the same name.
+	var buffers []string
+	for _, m := range regexp.MustCompile(`set-buffer -b (\S+) -- (?:-line one|second)`).FindAllStringSubmatch(logText, -1) {
+		if !strings.Contains(logText, "paste-buffer -p -d -b "+m[1]+" -t ") {
+			t.Errorf("buffer %s was set but not pasted:\n%s", m[1], logText)
+		}
+		buffers = append(buffers, m[1])
+	}
+	if len(buffers) != 2 || buffers[0] == buffers[1] || !strings.HasPrefix(buffers[0], "willow-paste-") {
+		t.Errorf("paste buffers = %v, want two distinct willow-paste-* names", buffers)
+	}
 }
 
 func TestNewSessionRunsLayoutAndPaneCommands(t *testing.T) {
 	logPath := installFakeTmux(t)
 
-	err := NewSession("sess", "/work", []string{"split-window -h"}, []config.PaneConfig{
+	err := NewSession("sess", "/work", []string{"APP_MODE=review"}, []string{"split-window -h"}, []config.PaneConfig{
 		{Command: "echo one"},
 		{Command: "echo two"},
 	})
@@ -198,7 +220,7 @@ func TestNewSessionRunsLayoutAndPaneCommands(t *testing.T) {
 	}
 	logText := string(logData)
 	for _, want := range []string{
-		"new-session -d -s sess -c /work",
+		"new-session -d -s sess -c /work -e APP_MODE=review",
 		"split-window -t sess -h -c /work",
 		"list-panes -t sess -s -F #{pane_id}",
 		"send-keys -t %1 echo one Enter",
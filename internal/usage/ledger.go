@@ -0,0 +1,230 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+	"github.com/iamrajjoshi/willow/internal/config"
+)
+
+// Record is one ledger entry: the usage a session added since its previous
+// record. Summing records therefore never double counts a session.
+type Record struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Repo      string        `json:"repo"`
+	Worktree  string        `json:"worktree"`
+	Harness   string        `json:"harness"`
+	Session   string        `json:"session"`
+	Usage     harness.Usage `json:"usage"`
+	CostUSD   float64       `json:"cost_usd"`
+}
+
+type ReadOpts struct {
+	Repo  string
+	Since time.Time
+}
+
+func Dir() string {
+	return filepath.Join(config.WillowHome(), "usage")
+}
+
+// monthFile returns the path for a given month's ledger file.
+func monthFile(t time.Time) string {
+	return filepath.Join(Dir(), t.Format("2006-01")+".jsonl")
+}
+
+// Append writes a record to the current month's JSONL file.
+func Append(r Record) error {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("create usage dir: %w", err)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal usage record: %w", err)
+	}
+	data = append(data, '\n')
+
+	f, err := os.OpenFile(monthFile(r.Timestamp), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open usage file: %w", err)
+	}
+	defer f.Close()
+	// Hooks from parallel agents append concurrently; the lock keeps their
+	// lines from interleaving.
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("lock usage file: %w", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	_, err = f.Write(data)
+	return err
+}
+
+// Read returns records matching the given filters, oldest first.
+func Read(opts ReadOpts) ([]Record, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []Record
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		// Skip months that end before opts.Since.
+		if !opts.Since.IsZero() {
+			if t, err := time.Parse("2006-01", strings.TrimSuffix(e.Name(), ".jsonl")); err == nil {
+				if t.AddDate(0, 1, 0).Before(opts.Since) {
+					continue
+				}
+			}
+		}
+
+		records, err := readFile(filepath.Join(Dir(), e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, r := range records {
+			if !opts.Since.IsZero() && r.Timestamp.Before(opts.Since) {
+				continue
+			}
+			if opts.Repo != "" && r.Repo != opts.Repo {
+				continue
+			}
+			result = append(result, r)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+func readFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Group keys accepted by Summarize.
+const (
+	ByRepo     = "repo"
+	ByWorktree = "worktree"
+	ByHarness  = "harness"
+	ByModel    = "model"
+	ByDay      = "day"
+)
+
+// Summary aggregates records sharing a group key.
+type Summary struct {
+	Key      string             `json:"key"`
+	Tokens   harness.TokenUsage `json:"tokens"`
+	CostUSD  float64            `json:"cost_usd"`
+	Sessions int                `json:"sessions"`
+	// Unpriced is set when some tokens came from models without a price.
+	Unpriced bool `json:"unpriced,omitempty"`
+}
+
+// Summarize groups records by one of the By* keys, sorted by cost then
+// tokens, highest first. Day groups are sorted chronologically instead.
+func Summarize(records []Record, by string) ([]Summary, error) {
+	switch by {
+	case ByRepo, ByWorktree, ByHarness, ByModel, ByDay:
+	default:
+		return nil, fmt.Errorf("unknown grouping %q (want repo, worktree, harness, model, or day)", by)
+	}
+
+	groups := map[string]*Summary{}
+	sessions := map[string]map[string]bool{}
+	add := func(key, session, model string, t harness.TokenUsage) {
+		s, ok := groups[key]
+		if !ok {
+			s = &Summary{Key: key}
+			groups[key] = s
+			sessions[key] = map[string]bool{}
+		}
+		s.Tokens = s.Tokens.Add(t)
+		cost, complete := Cost(harness.Usage{model: t})
+		s.CostUSD += cost
+		if !complete {
+			s.Unpriced = true
+		}
+		if !sessions[key][session] {
+			sessions[key][session] = true
+			s.Sessions++
+		}
+	}
+
+	for _, r := range records {
+		session := r.Repo + "/" + r.Worktree + "/" + r.Harness + "/" + r.Session
+		for model, t := range r.Usage {
+			var key string
+			switch by {
+			case ByRepo:
+				key = r.Repo
+			case ByWorktree:
+				key = r.Repo + "/" + r.Worktree
+			case ByHarness:
+				key = r.Harness
+			case ByModel:
+				key = model
+			case ByDay:
+				key = r.Timestamp.Local().Format("2006-01-02")
+			}
+			add(key, session, model, t)
+		}
+	}
+
+	result := make([]Summary, 0, len(groups))
+	for _, s := range groups {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if by == ByDay {
+			return result[i].Key < result[j].Key
+		}
+		if result[i].CostUSD != result[j].CostUSD {
+			return result[i].CostUSD > result[j].CostUSD
+		}
+		if result[i].Tokens.Total() != result[j].Tokens.Total() {
+			return result[i].Tokens.Total() > result[j].Tokens.Total()
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
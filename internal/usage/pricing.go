@@ -0,0 +1,91 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+)
+
+// Price is a model's list price in USD per million tokens.
+type Price struct {
+	Input      float64
+	Output     float64
+	CacheRead  float64
+	CacheWrite float64
+}
+
+// prices is matched by substring in order, so more specific model families
+// come before the ones they would otherwise fall into.
+var prices = []struct {
+	match string
+	price Price
+}{
+	{"claude-opus-4-1", Price{Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75}},
+	{"claude-opus-4-0", Price{Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75}},
+	{"claude-opus-4-2025", Price{Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75}},
+	{"claude-3-opus", Price{Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75}},
+	{"claude-opus-4", Price{Input: 5, Output: 25, CacheRead: 0.5, CacheWrite: 6.25}},
+	{"claude-sonnet-4", Price{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75}},
+	{"claude-3-7-sonnet", Price{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75}},
+	{"claude-3-5-sonnet", Price{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75}},
+	{"claude-haiku-4", Price{Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25}},
+	{"claude-3-5-haiku", Price{Input: 0.8, Output: 4, CacheRead: 0.08, CacheWrite: 1}},
+	{"gpt-5-nano", Price{Input: 0.05, Output: 0.4, CacheRead: 0.005}},
+	{"gpt-5-mini", Price{Input: 0.25, Output: 2, CacheRead: 0.025}},
+	{"gpt-5", Price{Input: 1.25, Output: 10, CacheRead: 0.125}},
+	{"gpt-4.1-mini", Price{Input: 0.4, Output: 1.6, CacheRead: 0.1}},
+	{"gpt-4.1", Price{Input: 2, Output: 8, CacheRead: 0.5}},
+	{"o4-mini", Price{Input: 1.1, Output: 4.4, CacheRead: 0.275}},
+	{"o3", Price{Input: 2, Output: 8, CacheRead: 0.5}},
+}
+
+// PriceFor returns the list price for model. ok is false for models willow
+// has no price for; their tokens are still counted but cost nothing.
+func PriceFor(model string) (Price, bool) {
+	model = strings.ToLower(model)
+	for _, p := range prices {
+		if strings.Contains(model, p.match) {
+			return p.price, true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost estimates the USD cost of u. complete is false when some model in u
+// has no known price.
+func Cost(u harness.Usage) (usd float64, complete bool) {
+	complete = true
+	for model, t := range u {
+		p, ok := PriceFor(model)
+		if !ok {
+			complete = false
+			continue
+		}
+		usd += (float64(t.Input)*p.Input +
+			float64(t.Output)*p.Output +
+			float64(t.CacheRead)*p.CacheRead +
+			float64(t.CacheWrite)*p.CacheWrite) / 1e6
+	}
+	return usd, complete
+}
+
+// FormatTokens renders a token count compactly: 950, 12.3k, 4.1M.
+func FormatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// FormatCost renders a USD estimate, flooring tiny non-zero amounts so they
+// do not read as free.
+func FormatCost(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
@@ -0,0 +1,169 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent/harness"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestPriceForMatchesMostSpecificFamily(t *testing.T) {
+	tests := []struct {
+		model string
+		input float64
+	}{
+		{"claude-opus-4-1-20250805", 15},
+		{"claude-opus-4-20250514", 15},
+		{"claude-opus-4-5-20251101", 5},
+		{"claude-sonnet-4-5-20250929", 3},
+		{"gpt-5-mini", 0.25},
+		{"gpt-5-codex", 1.25},
+	}
+	for _, tt := range tests {
+		p, ok := PriceFor(tt.model)
+		if !ok || p.Input != tt.input {
+			t.Errorf("PriceFor(%q) = %+v, %v; want input %v", tt.model, p, ok, tt.input)
+		}
+	}
+	if _, ok := PriceFor("mystery-model"); ok {
+		t.Error("unknown model should not be priced")
+	}
+}
+
+func TestCost(t *testing.T) {
+	u := harness.Usage{
+		"claude-sonnet-4-5": {Input: 1_000_000, Output: 100_000, CacheRead: 1_000_000, CacheWrite: 1_000_000},
+	}
+	cost, complete := Cost(u)
+	// 3 + 1.5 + 0.3 + 3.75
+	if !complete || !approx(cost, 8.55) {
+		t.Fatalf("Cost = %v, %v; want 8.55, true", cost, complete)
+	}
+
+	u["mystery"] = harness.TokenUsage{Input: 1_000_000}
+	cost, complete = Cost(u)
+	if complete || !approx(cost, 8.55) {
+		t.Fatalf("Cost with unpriced model = %v, %v; want 8.55, false", cost, complete)
+	}
+}
+
+func TestFormatTokensAndCost(t *testing.T) {
+	for n, want := range map[int64]string{950: "950", 12_345: "12.3k", 4_100_000: "4.1M"} {
+		if got := FormatTokens(n); got != want {
+			t.Errorf("FormatTokens(%d) = %q, want %q", n, got, want)
+		}
+	}
+	for usd, want := range map[float64]string{0: "$0.00", 0.004: "<$0.01", 1.234: "$1.23"} {
+		if got := FormatCost(usd); got != want {
+			t.Errorf("FormatCost(%v) = %q, want %q", usd, got, want)
+		}
+	}
+}
+
+func TestAppendAndReadFilters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now().UTC()
+	records := []Record{
+		{Timestamp: now.Add(-40 * 24 * time.Hour), Repo: "old", Worktree: "a", Harness: "claude", Session: "s0", Usage: harness.Usage{"m": {Input: 1}}},
+		{Timestamp: now.Add(-2 * time.Hour), Repo: "api", Worktree: "a", Harness: "claude", Session: "s1", Usage: harness.Usage{"m": {Input: 2}}},
+		{Timestamp: now.Add(-1 * time.Hour), Repo: "web", Worktree: "b", Harness: "codex", Session: "s2", Usage: harness.Usage{"m": {Input: 3}}},
+	}
+	for _, r := range records {
+		if err := Append(r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := Read(ReadOpts{Since: now.Add(-7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 2 || got[0].Session != "s1" || got[1].Session != "s2" {
+		t.Fatalf("Read(since 7d) = %+v, want s1 then s2", got)
+	}
+
+	got, err = Read(ReadOpts{Repo: "web"})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 1 || got[0].Session != "s2" {
+		t.Fatalf("Read(repo=web) = %+v", got)
+	}
+}
+
+func TestAppendConcurrentWritersKeepLinesWhole(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	// Long model names push each record past the pipe-atomic size, so
+	// unlocked writers could interleave.
+	model := strings.Repeat("m", 8192)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = Append(Record{Repo: "r", Session: fmt.Sprintf("s%d", i), Usage: harness.Usage{model: {Input: 1}}})
+		}()
+	}
+	wg.Wait()
+
+	records, err := Read(ReadOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 20 {
+		t.Errorf("read %d records, want 20", len(records))
+	}
+}
+
+func TestReadMissingDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	got, err := Read(ReadOpts{})
+	if err != nil || len(got) != 0 {
+		t.Fatalf("Read = %v, %v; want empty", got, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local)
+	records := []Record{
+		{Timestamp: ts, Repo: "api", Worktree: "a", Harness: "claude", Session: "s1",
+			Usage: harness.Usage{"claude-sonnet-4-5": {Input: 1_000_000}}},
+		{Timestamp: ts.Add(time.Hour), Repo: "api", Worktree: "a", Harness: "claude", Session: "s1",
+			Usage: harness.Usage{"claude-sonnet-4-5": {Output: 100_000}}},
+		{Timestamp: ts.Add(24 * time.Hour), Repo: "web", Worktree: "b", Harness: "codex", Session: "s2",
+			Usage: harness.Usage{"mystery": {Input: 500}}},
+	}
+
+	byRepo, err := Summarize(records, ByRepo)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if len(byRepo) != 2 || byRepo[0].Key != "api" || byRepo[0].Sessions != 1 || !approx(byRepo[0].CostUSD, 4.5) {
+		t.Fatalf("by repo = %+v", byRepo)
+	}
+	if byRepo[0].Tokens != (harness.TokenUsage{Input: 1_000_000, Output: 100_000}) {
+		t.Errorf("api tokens = %+v", byRepo[0].Tokens)
+	}
+	if byRepo[1].Key != "web" || !byRepo[1].Unpriced || byRepo[1].CostUSD != 0 {
+		t.Errorf("web summary = %+v", byRepo[1])
+	}
+
+	byDay, err := Summarize(records, ByDay)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if len(byDay) != 2 || byDay[0].Key != "2026-03-04" || byDay[1].Key != "2026-03-05" {
+		t.Fatalf("by day = %+v", byDay)
+	}
+
+	if _, err := Summarize(records, "branch"); err == nil {
+		t.Fatal("unknown grouping should error")
+	}
+}
@@ -0,0 +1,273 @@
+// Package watcher maintains an in-memory index of agent session status files,
+// updated from filesystem notifications instead of periodic directory scans.
+package watcher
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+)
+
+// Depths below the status root: <repo>/<worktree>/<harness>/<session>.json.
+const (
+	depthRepo     = 1
+	depthWorktree = 2
+	depthHarness  = 3
+	depthSession  = 4
+
+	eventBuffer = 256
+)
+
+type EventType string
+
+const (
+	EventUpdate EventType = "update"
+	EventRemove EventType = "remove"
+)
+
+// Event describes a single session that was written or removed. Removed
+// sessions carry the last indexed state.
+type Event struct {
+	Type EventType
+	Info agent.SessionFileInfo
+}
+
+type Watcher struct {
+	root string
+	fw   *fsnotify.Watcher
+
+	mu    sync.RWMutex
+	index map[string]agent.SessionFileInfo // keyed by session file path
+
+	events chan Event
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New watches the willow status directory, creating it if needed, and
+// indexes the sessions already present.
+func New() (*Watcher, error) {
+	root := agent.StatusDir()
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		root:   root,
+		fw:     fw,
+		index:  make(map[string]agent.SessionFileInfo),
+		events: make(chan Event, eventBuffer),
+		done:   make(chan struct{}),
+	}
+	// Watches are registered before the initial load so writes racing with
+	// startup are either seen by the walk or delivered as events.
+	if err := w.addTree(root, false); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Events delivers session changes. Events are dropped rather than blocking
+// the watcher when the consumer falls behind; Sessions is always current.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Sessions returns a snapshot of every indexed session, ordered like
+// agent.ScanAllSessions.
+func (w *Watcher) Sessions() []agent.SessionFileInfo {
+	w.mu.RLock()
+	paths := make([]string, 0, len(w.index))
+	for p := range w.index {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	out := make([]agent.SessionFileInfo, 0, len(paths))
+	for _, p := range paths {
+		out = append(out, w.index[p])
+	}
+	w.mu.RUnlock()
+	return out
+}
+
+// Index returns a snapshot of the sessions grouped by worktree.
+func (w *Watcher) Index() *agent.SessionIndex {
+	return agent.NewSessionIndex(w.Sessions())
+}
+
+func (w *Watcher) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	close(w.done)
+	err := w.fw.Close()
+	w.wg.Wait()
+	close(w.events)
+	return err
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fw.Events:
+			if !ok {
+				return
+			}
+			w.handle(ev)
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				w.resync()
+			}
+		}
+	}
+}
+
+func (w *Watcher) handle(ev fsnotify.Event) {
+	parts := w.relParts(ev.Name)
+	if len(parts) == 0 || len(parts) > depthSession {
+		return
+	}
+
+	if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+		if len(parts) < depthSession {
+			_ = w.fw.Remove(ev.Name)
+		}
+		w.dropUnder(ev.Name)
+		return
+	}
+	if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Write) {
+		return
+	}
+
+	if len(parts) < depthSession {
+		if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() && ev.Has(fsnotify.Create) {
+			_ = w.addTree(ev.Name, true)
+		}
+		return
+	}
+	w.load(ev.Name, parts, true)
+}
+
+// addTree watches dir and every status directory below it, indexing any
+// session files found along the way.
+func (w *Watcher) addTree(dir string, emit bool) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		parts := w.relParts(path)
+		if d.IsDir() {
+			if len(parts) >= depthSession {
+				return filepath.SkipDir
+			}
+			if err := w.fw.Add(path); err != nil && path == dir {
+				return err
+			}
+			return nil
+		}
+		if len(parts) == depthSession {
+			w.load(path, parts, emit)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) load(path string, parts []string, emit bool) {
+	name := parts[depthSession-1]
+	if !strings.HasSuffix(name, ".json") {
+		return
+	}
+	repo, wtDir, harnessID := parts[depthRepo-1], parts[depthWorktree-1], parts[depthHarness-1]
+	ss := agent.ReadSessionFile(repo, wtDir, harnessID, strings.TrimSuffix(name, ".json"))
+	if ss == nil {
+		// Missing files arrive as Remove events; an unparsable one is most
+		// likely mid-write, so keep the last good state until the next Write.
+		return
+	}
+	info := agent.SessionFileInfo{RepoName: repo, WorktreeDir: wtDir, Session: *ss}
+
+	w.mu.Lock()
+	prev, existed := w.index[path]
+	w.index[path] = info
+	w.mu.Unlock()
+	if emit && (!existed || !reflect.DeepEqual(prev.Session, info.Session)) {
+		w.emit(Event{Type: EventUpdate, Info: info})
+	}
+}
+
+// dropUnder removes the session at path, or every session below it when path
+// is a directory that went away.
+func (w *Watcher) dropUnder(path string) {
+	prefix := path + string(filepath.Separator)
+	var removed []agent.SessionFileInfo
+	w.mu.Lock()
+	for p, info := range w.index {
+		if p == path || strings.HasPrefix(p, prefix) {
+			removed = append(removed, info)
+			delete(w.index, p)
+		}
+	}
+	w.mu.Unlock()
+	for _, info := range removed {
+		w.emit(Event{Type: EventRemove, Info: info})
+	}
+}
+
+// resync reconciles the index with disk after the kernel queue overflowed
+// and events were lost.
+func (w *Watcher) resync() {
+	_ = w.addTree(w.root, true)
+
+	w.mu.RLock()
+	paths := make([]string, 0, len(w.index))
+	for p := range w.index {
+		paths = append(paths, p)
+	}
+	w.mu.RUnlock()
+	for _, p := range paths {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			w.dropUnder(p)
+		}
+	}
+}
+
+func (w *Watcher) emit(ev Event) {
+	select {
+	case <-w.done:
+	case w.events <- ev:
+	default:
+	}
+}
+
+func (w *Watcher) relParts(path string) []string {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	return strings.Split(rel, string(filepath.Separator))
+}
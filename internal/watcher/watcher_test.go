@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iamrajjoshi/willow/internal/agent"
+	"github.com/iamrajjoshi/willow/internal/testutil"
+)
+
+func nextEvent(t *testing.T, w *Watcher) Event {
+	t.Helper()
+	select {
+	case ev := <-w.Events():
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watcher event")
+		return Event{}
+	}
+}
+
+func TestNewIndexesExistingSessions(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt-a", "s1", agent.StatusBusy)
+	testutil.WriteSession(t, "repo", "wt-b", "s2", agent.StatusDone)
+
+	w, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	want, err := agent.ScanAllSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := w.Sessions()
+	if len(got) != len(want) {
+		t.Fatalf("Sessions() = %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].RepoName != want[i].RepoName || got[i].WorktreeDir != want[i].WorktreeDir || got[i].Session.SessionID != want[i].Session.SessionID {
+			t.Errorf("Sessions()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if s := w.Index().ReadAllSessions("repo", "wt-b"); len(s) != 1 || s[0].Status != agent.StatusDone {
+		t.Errorf("Index().ReadAllSessions(repo, wt-b) = %+v", s)
+	}
+}
+
+func TestWatcherTracksWritesAndRemovals(t *testing.T) {
+	testutil.SetupHome(t)
+	w, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Repo, worktree, and harness directories all appear after startup.
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+	ev := nextEvent(t, w)
+	if ev.Type != EventUpdate || ev.Info.RepoName != "repo" || ev.Info.WorktreeDir != "wt" || ev.Info.Session.Status != agent.StatusBusy {
+		t.Fatalf("create event = %+v", ev)
+	}
+
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusDone)
+	ev = nextEvent(t, w)
+	if ev.Type != EventUpdate || ev.Info.Session.Status != agent.StatusDone {
+		t.Fatalf("update event = %+v", ev)
+	}
+	if s := w.Sessions(); len(s) != 1 || s[0].Session.Status != agent.StatusDone {
+		t.Fatalf("Sessions() after update = %+v", s)
+	}
+
+	if err := os.Remove(agent.SessionPath("repo", "wt", "claude", "s1")); err != nil {
+		t.Fatal(err)
+	}
+	ev = nextEvent(t, w)
+	if ev.Type != EventRemove || ev.Info.Session.SessionID != "s1" {
+		t.Fatalf("remove event = %+v", ev)
+	}
+	if s := w.Sessions(); len(s) != 0 {
+		t.Fatalf("Sessions() after remove = %+v", s)
+	}
+}
+
+func TestWatcherDropsSessionsUnderRemovedWorktree(t *testing.T) {
+	testutil.SetupHome(t)
+	testutil.WriteSession(t, "repo", "wt", "s1", agent.StatusBusy)
+	testutil.WriteSession(t, "repo", "wt", "s2", agent.StatusWait)
+
+	w, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := os.RemoveAll(agent.StatusWorktreeDir("repo", "wt")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if ev := nextEvent(t, w); ev.Type != EventRemove {
+			t.Fatalf("event %d = %+v, want remove", i, ev)
+		}
+	}
+	if s := w.Sessions(); len(s) != 0 {
+		t.Fatalf("Sessions() = %+v, want empty", s)
+	}
+}